@@ -0,0 +1,652 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/Masterminds/squirrel"
+	gomysql "github.com/go-sql-driver/mysql"
+	"github.com/godzie44/d3/adapter"
+	"github.com/godzie44/d3/orm"
+	"github.com/godzie44/d3/orm/entity"
+	"github.com/godzie44/d3/orm/persistence"
+	"github.com/godzie44/d3/orm/query"
+	"github.com/godzie44/d3/orm/schema"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+type mysqlDriver struct {
+	beforeQCallback, afterQCallback []func(query string, args ...interface{})
+
+	db *sql.DB
+}
+
+// NewMySQLDriver - create driver, dataSourceName must contain parseTime=true parameter if entities has time fields.
+func NewMySQLDriver(dataSourceName string) (*mysqlDriver, error) {
+	db, err := sql.Open("mysql", dataSourceName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mysqlDriver{
+		db: db,
+	}, nil
+}
+
+func (m *mysqlDriver) UnwrapConn() interface{} {
+	return m.db
+}
+
+func (m *mysqlDriver) Close() error {
+	return m.db.Close()
+}
+
+func (m *mysqlDriver) ExecuteQuery(ctx context.Context, query *query.Query, tx orm.Transaction) ([]map[string]interface{}, error) {
+	q, args, err := adapter.QueryToSqlWithPlaceholders(query, squirrel.Question)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range m.beforeQCallback {
+		m.beforeQCallback[i](q, args...)
+	}
+
+	mysqlTx, ok := tx.(*mysqlTransaction)
+	if !ok {
+		panic(errors.New("transaction type must be mysqlTransaction"))
+	}
+
+	rows, err := mysqlTx.tx.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, translateError("", err)
+	}
+	defer rows.Close()
+
+	for i := range m.afterQCallback {
+		m.afterQCallback[i](q, args...)
+	}
+
+	result := make([]map[string]interface{}, 0)
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]interface{}, len(cols))
+	valuePtrs := make([]interface{}, len(cols))
+	for i := range values {
+		valuePtrs[i] = &values[i]
+	}
+
+	for rows.Next() {
+		err := rows.Scan(valuePtrs...)
+		if err != nil {
+			return nil, err
+		}
+
+		row := make(map[string]interface{})
+		for i, col := range cols {
+			// byte slices can't be used as keys when hydrator groups rows by primary key
+			if b, ok := values[i].([]byte); ok {
+				row[col] = string(b)
+				continue
+			}
+			row[col] = values[i]
+		}
+
+		result = append(result, row)
+	}
+
+	return result, rows.Err()
+}
+
+func (m *mysqlDriver) BeforeQuery(fn func(query string, args ...interface{})) {
+	m.beforeQCallback = append(m.beforeQCallback, fn)
+}
+
+func (m *mysqlDriver) AfterQuery(fn func(query string, args ...interface{})) {
+	m.afterQCallback = append(m.afterQCallback, fn)
+}
+
+// MakeScalarDataMapper - mysql returns most of scalars as strings (text protocol) or as int64 (binary protocol),
+// so mapper converts it into field kind. Values for sql.Null* fields returned as is, cause them scanned by entity setter.
+func (m *mysqlDriver) MakeScalarDataMapper() orm.ScalarDataMapper {
+	return func(data interface{}, into reflect.Kind) interface{} {
+		switch d := data.(type) {
+		case string:
+			return mapString(d, into)
+		case int64:
+			return mapInt(d, into)
+		case float32:
+			if into == reflect.Float64 {
+				return float64(d)
+			}
+		case float64:
+			if into == reflect.Float32 {
+				return float32(d)
+			}
+		}
+
+		return data
+	}
+}
+
+func mapString(data string, into reflect.Kind) interface{} {
+	switch into {
+	case reflect.Bool:
+		return data == "1"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v, err := strconv.ParseInt(data, 10, 64)
+		if err != nil {
+			return data
+		}
+		return mapInt(v, into)
+	case reflect.Float32:
+		v, err := strconv.ParseFloat(data, 32)
+		if err != nil {
+			return data
+		}
+		return float32(v)
+	case reflect.Float64:
+		v, err := strconv.ParseFloat(data, 64)
+		if err != nil {
+			return data
+		}
+		return v
+	default:
+		return data
+	}
+}
+
+func mapInt(data int64, into reflect.Kind) interface{} {
+	switch into {
+	case reflect.Bool:
+		return data != 0
+	case reflect.Int:
+		return int(data)
+	case reflect.Int8:
+		return int8(data)
+	case reflect.Int16:
+		return int16(data)
+	case reflect.Int32:
+		return int32(data)
+	default:
+		return data
+	}
+}
+
+type mysqlTransaction struct {
+	tx *sql.Tx
+}
+
+func (t *mysqlTransaction) Commit() error {
+	return t.tx.Commit()
+}
+
+func (t *mysqlTransaction) Rollback() error {
+	return t.tx.Rollback()
+}
+
+func (t *mysqlTransaction) Savepoint(name string) error {
+	_, err := t.tx.Exec("SAVEPOINT " + name)
+	return err
+}
+
+func (t *mysqlTransaction) RollbackToSavepoint(name string) error {
+	_, err := t.tx.Exec("ROLLBACK TO SAVEPOINT " + name)
+	return err
+}
+
+func (t *mysqlTransaction) ReleaseSavepoint(name string) error {
+	_, err := t.tx.Exec("RELEASE SAVEPOINT " + name)
+	return err
+}
+
+func (m *mysqlDriver) BeginTx(ctx context.Context, opts orm.TxOptions) (orm.Transaction, error) {
+	tx, err := m.db.BeginTx(ctx, &sql.TxOptions{Isolation: opts.Isolation, ReadOnly: opts.ReadOnly})
+	if err != nil {
+		return nil, err
+	}
+
+	return &mysqlTransaction{
+		tx: tx,
+	}, nil
+}
+
+// IsRetryable - report that error is deadlock, so transaction can be repeated.
+func (m *mysqlDriver) IsRetryable(err error) bool {
+	var mysqlErr *gomysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == 1213
+}
+
+func (m *mysqlDriver) MakePusher(tx orm.Transaction) persistence.Pusher {
+	mysqlTx, ok := tx.(*mysqlTransaction)
+	if !ok {
+		panic(errors.New("transaction type must be mysqlTransaction"))
+	}
+
+	return &mysqlPusher{tx: mysqlTx}
+}
+
+type mysqlPusher struct {
+	tx *mysqlTransaction
+}
+
+func placeholders(count int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", count), ",")
+}
+
+func (m *mysqlPusher) Insert(ctx context.Context, table string, cols []string, values []interface{}, onConflict persistence.OnConflict) error {
+	var onConflictClause string
+	switch onConflict {
+	case persistence.DoNothing:
+		onConflictClause = "IGNORE"
+	case persistence.Undefined:
+		break
+	}
+
+	sql := fmt.Sprintf("INSERT %s INTO %s(%s) VALUES(%s)", onConflictClause, table, strings.Join(cols, ","), placeholders(len(values)))
+
+	_, err := m.tx.tx.ExecContext(
+		ctx,
+		sql,
+		values...,
+	)
+	if err != nil {
+		return fmt.Errorf("insert mysql driver: %w", translateError(table, err))
+	}
+
+	return nil
+}
+
+type idScanner struct {
+	id int64
+}
+
+func (i *idScanner) Scan(v ...interface{}) error {
+	if len(v) > 0 {
+		switch v[0].(type) {
+		case *sql.NullInt32:
+			return v[0].(*sql.NullInt32).Scan(int32(i.id))
+		case *sql.NullInt64:
+			return v[0].(*sql.NullInt64).Scan(i.id)
+		default:
+			return errors.New("unknown pk type, expected sql.NullInt32, sql.NullInt64")
+		}
+	}
+	return nil
+}
+
+// InsertWithReturn - mysql has no RETURNING clause, so only auto increment id returned, it taken from LAST_INSERT_ID().
+func (m *mysqlPusher) InsertWithReturn(
+	ctx context.Context,
+	table string,
+	cols []string,
+	values []interface{},
+	_ []string,
+	withReturned func(scanner persistence.Scanner) error,
+) error {
+	res, err := m.tx.tx.ExecContext(
+		ctx,
+		fmt.Sprintf("INSERT INTO %s(%s) VALUES(%s)", table, strings.Join(cols, ","), placeholders(len(values))),
+		values...,
+	)
+	if err != nil {
+		return fmt.Errorf("insert mysql driver: %w", translateError(table, err))
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	if err := withReturned(&idScanner{id: id}); err != nil {
+		return fmt.Errorf("insert mysql driver: %w", err)
+	}
+
+	return nil
+}
+
+// Update - mysql reports changed (not matched) rows as affected, it is enough for version check
+// cause versioned update always change version column.
+func (m *mysqlPusher) Update(ctx context.Context, table string, cols []string, values []interface{}, identityCond map[string]interface{}) (int64, error) {
+	queryValues := values
+
+	setCommands := make([]string, len(values))
+	for i := range values {
+		setCommands[i] = cols[i] + "=?"
+	}
+
+	where := make([]string, 0, len(identityCond))
+	for col, val := range identityCond {
+		queryValues = append(queryValues, val)
+		where = append(where, col+"=?")
+	}
+
+	res, err := m.tx.tx.ExecContext(
+		ctx,
+		fmt.Sprintf("UPDATE %s SET %s WHERE %s", table, strings.Join(setCommands, ","), strings.Join(where, " AND ")),
+		queryValues...,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("update mysql driver: %w", translateError(table, err))
+	}
+
+	return res.RowsAffected()
+}
+
+func (m *mysqlPusher) Remove(ctx context.Context, table string, identityCond map[string]interface{}) error {
+	args := make([]interface{}, 0, len(identityCond))
+	where := make([]string, 0, len(identityCond))
+
+	for col, val := range identityCond {
+		args = append(args, val)
+		where = append(where, col+"=?")
+	}
+
+	_, err := m.tx.tx.ExecContext(
+		ctx,
+		fmt.Sprintf("DELETE FROM %s WHERE %s", table, strings.Join(where, " AND ")),
+		args...,
+	)
+
+	return translateError(table, err)
+}
+
+var constraintViolations = map[uint16]error{
+	1062: orm.ErrUniqueViolation,
+	1451: orm.ErrForeignKeyViolation,
+	1452: orm.ErrForeignKeyViolation,
+	1048: orm.ErrNotNullViolation,
+	3819: orm.ErrCheckViolation,
+}
+
+// translateError - translate mysql constraint violation into orm.ConstraintError, other errors returned as is.
+// Mysql not report constraint name in structured form, so it stay empty.
+func translateError(table string, err error) error {
+	var mysqlErr *gomysql.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return err
+	}
+
+	if kind, isViolation := constraintViolations[mysqlErr.Number]; isViolation {
+		return orm.NewConstraintError(kind, table, "", err)
+	}
+	return err
+}
+
+func (m *mysqlDriver) CreateTableSql(name string, columns map[string]schema.ColumnType, pkColumns []string, pkStrategy entity.PkStrategy, foreignKeys []schema.ForeignKey) string {
+	isPkCol := func(colName string) bool {
+		for _, pkCol := range pkColumns {
+			if pkCol == colName {
+				return true
+			}
+		}
+		return false
+	}
+
+	sql := strings.Builder{}
+	sql.WriteString("CREATE TABLE IF NOT EXISTS " + name)
+	sql.WriteString("(\n")
+
+	var colsSql []string
+	for col, ctype := range columns {
+		def := m.ColumnDefinition(ctype, isPkCol(col), pkStrategy)
+
+		colSql := strings.Builder{}
+		colSql.WriteString(col)
+		colSql.WriteRune(' ')
+		colSql.WriteString(columnType(def.Type))
+		if !def.Nullable {
+			colSql.WriteString(" NOT NULL")
+		}
+
+		if isAutoIncrement(ctype, isPkCol(col), pkStrategy) {
+			colSql.WriteString(" AUTO_INCREMENT")
+		}
+
+		colsSql = append(colsSql, colSql.String())
+	}
+	sql.WriteString(strings.Join(colsSql, ",\n"))
+
+	sql.WriteString(",\n")
+	sql.WriteString(fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(pkColumns, ",")))
+
+	for _, fk := range foreignKeys {
+		sql.WriteString(",\n")
+		sql.WriteString(foreignKeyDefinition(name, fk))
+	}
+
+	sql.WriteString("\n);\n")
+
+	return sql.String()
+}
+
+// CreateForeignKeySql - mysql not support IF NOT EXISTS for constraints, so generated sql can't be applied twice.
+func (m *mysqlDriver) CreateForeignKeySql(table string, fk schema.ForeignKey) (string, error) {
+	return fmt.Sprintf("ALTER TABLE %s ADD %s;\n", table, foreignKeyDefinition(table, fk)), nil
+}
+
+// foreignKeyDefinition - innodb not support deferred constraints, so foreign keys checked immediately.
+// Unit of work executes actions of related entities in order of references
+// (referenced entity inserted first, referencing entity deleted first),
+// deletion of relation rows and nulling of join columns relies on ON DELETE action of constraint.
+func foreignKeyDefinition(table string, fk schema.ForeignKey) string {
+	var onDelete string
+	switch fk.OnDelete {
+	case entity.Cascade:
+		onDelete = " ON DELETE CASCADE"
+	case entity.Nullable:
+		onDelete = " ON DELETE SET NULL"
+	}
+
+	return fmt.Sprintf(
+		"CONSTRAINT %s_%s_fkey FOREIGN KEY (%s) REFERENCES %s(%s)%s",
+		table, strings.Join(fk.Columns, "_"), strings.Join(fk.Columns, ","), fk.RefTable, strings.Join(fk.RefColumns, ","), onDelete,
+	)
+}
+
+func isAutoIncrement(ctype schema.ColumnType, isPk bool, pkStrategy entity.PkStrategy) bool {
+	if !isPk || pkStrategy != entity.Auto {
+		return false
+	}
+
+	switch ctype {
+	case schema.Int, schema.Int32, schema.Int64, schema.NullInt32, schema.NullInt64:
+		return true
+	}
+	return false
+}
+
+// ColumnDefinition - types returned in format of information_schema.columns.data_type.
+// Strings stored in varchar, cause mysql can't use text columns in primary keys and indexes without prefix length.
+func (m *mysqlDriver) ColumnDefinition(ctype schema.ColumnType, isPk bool, _ entity.PkStrategy) schema.ColumnDefinition {
+	var def schema.ColumnDefinition
+
+	switch ctype {
+	case schema.UUID:
+		def = schema.ColumnDefinition{Type: "char", Nullable: true}
+	case schema.Bool, schema.NullBool:
+		def = schema.ColumnDefinition{Type: "tinyint", Nullable: ctype == schema.NullBool}
+	case schema.Int, schema.Int64, schema.NullInt64:
+		def = schema.ColumnDefinition{Type: "bigint", Nullable: ctype == schema.NullInt64}
+	case schema.Int32, schema.NullInt32:
+		def = schema.ColumnDefinition{Type: "int", Nullable: ctype == schema.NullInt32}
+	case schema.Float32:
+		def = schema.ColumnDefinition{Type: "float"}
+	case schema.Float64, schema.NullFloat64:
+		def = schema.ColumnDefinition{Type: "double", Nullable: ctype == schema.NullFloat64}
+	case schema.String, schema.NullString:
+		def = schema.ColumnDefinition{Type: "varchar", Nullable: ctype == schema.NullString}
+	case schema.Time, schema.NullTime:
+		def = schema.ColumnDefinition{Type: "datetime", Nullable: ctype == schema.NullTime}
+	}
+
+	// primary key columns always not null
+	if isPk {
+		def.Nullable = false
+	}
+
+	return def
+}
+
+// columnType - return full column type for data type from ColumnDefinition.
+func columnType(dataType string) string {
+	switch dataType {
+	case "char":
+		return "CHAR(36)"
+	case "tinyint":
+		return "BOOLEAN"
+	case "varchar":
+		return "VARCHAR(255)"
+	case "datetime":
+		return "DATETIME(6)"
+	default:
+		return strings.ToUpper(dataType)
+	}
+}
+
+func (m *mysqlDriver) TableInfo(name string) (*schema.TableInfo, error) {
+	rows, err := m.db.Query(`
+SELECT column_name, data_type, is_nullable
+FROM information_schema.columns
+WHERE table_schema = DATABASE() AND table_name = ?
+`, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns := make(map[string]schema.ColumnDefinition)
+	for rows.Next() {
+		var colName, dataType, isNullable string
+		if err := rows.Scan(&colName, &dataType, &isNullable); err != nil {
+			return nil, err
+		}
+		columns[colName] = schema.ColumnDefinition{Type: dataType, Nullable: isNullable == "YES"}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(columns) == 0 {
+		return nil, nil
+	}
+
+	indexes, err := m.tableIndexes(name)
+	if err != nil {
+		return nil, err
+	}
+
+	foreignKeys, err := m.tableForeignKeys(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &schema.TableInfo{Columns: columns, Indexes: indexes, ForeignKeys: foreignKeys}, nil
+}
+
+func (m *mysqlDriver) tableForeignKeys(table string) ([]schema.ForeignKey, error) {
+	rows, err := m.db.Query(`
+SELECT constraint_name, referenced_table_name, column_name, referenced_column_name
+FROM information_schema.key_column_usage
+WHERE table_schema = DATABASE() AND table_name = ? AND referenced_table_name IS NOT NULL
+ORDER BY constraint_name, ordinal_position
+`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var foreignKeys []schema.ForeignKey
+	var lastName string
+	for rows.Next() {
+		var name, refTable, colName, refColName string
+		if err := rows.Scan(&name, &refTable, &colName, &refColName); err != nil {
+			return nil, err
+		}
+
+		if len(foreignKeys) == 0 || lastName != name {
+			foreignKeys = append(foreignKeys, schema.ForeignKey{RefTable: refTable})
+			lastName = name
+		}
+		fk := &foreignKeys[len(foreignKeys)-1]
+		fk.Columns = append(fk.Columns, colName)
+		fk.RefColumns = append(fk.RefColumns, refColName)
+	}
+
+	return foreignKeys, rows.Err()
+}
+
+// tableIndexes - indexes created by innodb for foreign key columns are not returned, cause they are not entity indexes.
+func (m *mysqlDriver) tableIndexes(table string) ([]entity.Index, error) {
+	rows, err := m.db.Query(`
+SELECT index_name, non_unique, column_name
+FROM information_schema.statistics
+WHERE table_schema = DATABASE() AND table_name = ? AND index_name <> 'PRIMARY'
+AND index_name NOT IN (
+	SELECT constraint_name
+	FROM information_schema.table_constraints
+	WHERE table_schema = DATABASE() AND table_name = ? AND constraint_type = 'FOREIGN KEY'
+)
+ORDER BY index_name, seq_in_index
+`, table, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var indexes []entity.Index
+	for rows.Next() {
+		var indexName, colName string
+		var nonUnique bool
+		if err := rows.Scan(&indexName, &nonUnique, &colName); err != nil {
+			return nil, err
+		}
+
+		if len(indexes) == 0 || indexes[len(indexes)-1].Name != indexName {
+			indexes = append(indexes, entity.Index{Name: indexName, Unique: !nonUnique})
+		}
+		indexes[len(indexes)-1].Columns = append(indexes[len(indexes)-1].Columns, colName)
+	}
+
+	return indexes, rows.Err()
+}
+
+// AddColumnSql - mysql fill NOT NULL column with implicit default value for existing rows.
+func (m *mysqlDriver) AddColumnSql(table, column string, def schema.ColumnDefinition) string {
+	return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;\n", table, columnSql(column, def))
+}
+
+func (m *mysqlDriver) DropColumnSql(table, column string) (string, error) {
+	return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;\n", table, column), nil
+}
+
+func (m *mysqlDriver) AlterColumnSql(table, column string, def schema.ColumnDefinition) (string, error) {
+	return fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s;\n", table, columnSql(column, def)), nil
+}
+
+func columnSql(column string, def schema.ColumnDefinition) string {
+	if def.Nullable {
+		return column + " " + columnType(def.Type)
+	}
+	return column + " " + columnType(def.Type) + " NOT NULL"
+}
+
+func (m *mysqlDriver) DropIndexSql(table, name string) string {
+	return fmt.Sprintf("DROP INDEX %s ON %s;\n", name, table)
+}
+
+// CreateIndexSql - mysql not support IF NOT EXISTS for indexes, so generated sql can't be applied twice.
+func (m *mysqlDriver) CreateIndexSql(name string, unique bool, table string, columns ...string) string {
+	var uniqueDef string
+	if unique {
+		uniqueDef = "UNIQUE"
+	}
+
+	return fmt.Sprintf("CREATE %s INDEX %s ON %s (%s);\n", uniqueDef, name, table, strings.Join(columns, ","))
+}
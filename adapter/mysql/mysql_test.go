@@ -0,0 +1,178 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"github.com/godzie44/d3/orm"
+	"github.com/godzie44/d3/orm/entity"
+	"github.com/godzie44/d3/orm/persistence"
+	"github.com/godzie44/d3/orm/query"
+	"github.com/godzie44/d3/orm/schema"
+	"github.com/godzie44/d3/tests/helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"os"
+	"reflect"
+	"testing"
+)
+
+type mysqlDriverTS struct {
+	suite.Suite
+	driver *mysqlDriver
+	tester *helpers.MySQLTester
+}
+
+func (m *mysqlDriverTS) SetupTest() {
+	_, err := m.driver.UnwrapConn().(*sql.DB).Exec(`CREATE TABLE IF NOT EXISTS d3_test_table(
+		id integer NOT NULL AUTO_INCREMENT,
+		data varchar(255) NOT NULL,
+		PRIMARY KEY (id)
+	)`)
+	m.NoError(err)
+
+	_, err = m.driver.UnwrapConn().(*sql.DB).Exec(`INSERT INTO d3_test_table(id, data) VALUES (1, 'test 1'), (2, 'test 2'), (3, 'test 3')`)
+	m.NoError(err)
+}
+
+func (m *mysqlDriverTS) TearDownTest() {
+	_, err := m.driver.UnwrapConn().(*sql.DB).Exec(`DROP TABLE d3_test_table`)
+	m.NoError(err)
+}
+
+func (m *mysqlDriverTS) TearDownSuite() {
+	m.NoError(m.driver.Close())
+}
+
+func TestMySQLDriverTestSuite(t *testing.T) {
+	driver, err := NewMySQLDriver(os.Getenv("D3_MYSQL_TEST_DB"))
+	assert.NoError(t, err)
+
+	suite.Run(t, &mysqlDriverTS{
+		driver: driver,
+		tester: helpers.NewMySQLTester(t, driver.UnwrapConn().(*sql.DB)),
+	})
+}
+
+func (m *mysqlDriverTS) TestMySQLDriverQuery() {
+	tx, err := m.driver.BeginTx(context.Background(), orm.TxOptions{})
+	m.NoError(err)
+	defer tx.Commit() //nolint
+
+	data, err := m.driver.ExecuteQuery(context.Background(), query.New().Select("*").From("d3_test_table").Where("id", "=", 1), tx)
+	m.NoError(err)
+
+	m.Len(data, 1)
+	m.Equal("test 1", data[0]["data"])
+
+	data, err = m.driver.ExecuteQuery(context.Background(), query.New().Select("*").From("d3_test_table"), tx)
+	m.NoError(err)
+
+	m.Len(data, 3)
+}
+
+func (m *mysqlDriverTS) TestMySQLDriverTxInsert() {
+	tx, err := m.driver.BeginTx(context.Background(), orm.TxOptions{})
+	m.NoError(err)
+
+	pusher := m.driver.MakePusher(tx)
+
+	err = pusher.Insert(context.Background(), "d3_test_table", []string{"id", "data"}, []interface{}{4, "test 4"}, persistence.Undefined)
+	m.NoError(err)
+	err = pusher.Insert(context.Background(), "d3_test_table", []string{"id", "data"}, []interface{}{4, "test 4"}, persistence.DoNothing)
+	m.NoError(err)
+	m.NoError(tx.Commit())
+
+	m.tester.SeeFour("select * from d3_test_table")
+}
+
+func (m *mysqlDriverTS) TestMySQLDriverTxInsertWithReturn() {
+	tx, err := m.driver.BeginTx(context.Background(), orm.TxOptions{})
+	m.NoError(err)
+
+	pusher := m.driver.MakePusher(tx)
+
+	var id sql.NullInt32
+	err = pusher.InsertWithReturn(context.Background(), "d3_test_table", []string{"data"}, []interface{}{"test 4"}, []string{"id"}, func(scanner persistence.Scanner) error {
+		return scanner.Scan(&id)
+	})
+	m.NoError(err)
+	m.NoError(tx.Commit())
+
+	m.Equal(sql.NullInt32{Int32: 4, Valid: true}, id)
+	m.tester.SeeOne("select * from d3_test_table where id = 4 and data = 'test 4'")
+}
+
+func (m *mysqlDriverTS) TestMySQLDriverTxUpdate() {
+	tx, err := m.driver.BeginTx(context.Background(), orm.TxOptions{})
+	m.NoError(err)
+
+	pusher := m.driver.MakePusher(tx)
+
+	_, err = pusher.Update(context.Background(), "d3_test_table", []string{"data"}, []interface{}{"test upd"}, map[string]interface{}{"id": 1})
+	m.NoError(err)
+	m.NoError(tx.Commit())
+
+	m.tester.SeeOne("select * from d3_test_table where data = 'test upd'")
+}
+
+func (m *mysqlDriverTS) TestMySQLDriverTxDelete() {
+	tx, err := m.driver.BeginTx(context.Background(), orm.TxOptions{})
+	m.NoError(err)
+
+	pusher := m.driver.MakePusher(tx)
+
+	err = pusher.Remove(context.Background(), "d3_test_table", map[string]interface{}{"id": 1})
+	m.NoError(err)
+	m.NoError(tx.Commit())
+
+	m.tester.SeeTwo("select * from d3_test_table")
+}
+
+func (m *mysqlDriverTS) TestMySQLDriverTxRollback() {
+	tx, err := m.driver.BeginTx(context.Background(), orm.TxOptions{})
+	m.NoError(err)
+
+	pusher := m.driver.MakePusher(tx)
+
+	err = pusher.Insert(context.Background(), "d3_test_table", []string{"id", "data"}, []interface{}{4, "test 4"}, persistence.Undefined)
+	m.NoError(err)
+	m.NoError(tx.Rollback())
+
+	m.tester.SeeThree("select * from d3_test_table")
+}
+
+func TestScalarDataMapper(t *testing.T) {
+	mapper := (&mysqlDriver{}).MakeScalarDataMapper()
+
+	assert.Equal(t, "text", mapper("text", reflect.String))
+	assert.Equal(t, true, mapper("1", reflect.Bool))
+	assert.Equal(t, false, mapper(int64(0), reflect.Bool))
+	assert.Equal(t, 12, mapper("12", reflect.Int))
+	assert.Equal(t, int32(12), mapper("12", reflect.Int32))
+	assert.Equal(t, int32(12), mapper(int64(12), reflect.Int32))
+	assert.Equal(t, int64(12), mapper("12", reflect.Int64))
+	assert.Equal(t, float32(1.5), mapper("1.5", reflect.Float32))
+	assert.Equal(t, 1.5, mapper("1.5", reflect.Float64))
+	assert.Equal(t, "12", mapper("12", reflect.Struct))
+	assert.Nil(t, mapper(nil, reflect.Int))
+}
+
+func TestCreateTableSql(t *testing.T) {
+	sql := (&mysqlDriver{}).CreateTableSql("shop", map[string]schema.ColumnType{"id": schema.NullInt32}, []string{"id"}, entity.Auto, nil)
+	assert.Equal(t, "CREATE TABLE IF NOT EXISTS shop(\nid INT NOT NULL AUTO_INCREMENT,\nPRIMARY KEY (id)\n);\n", sql)
+
+	sql = (&mysqlDriver{}).CreateTableSql("book", map[string]schema.ColumnType{"uuid": schema.String}, []string{"uuid"}, entity.Manual, nil)
+	assert.Equal(t, "CREATE TABLE IF NOT EXISTS book(\nuuid VARCHAR(255) NOT NULL,\nPRIMARY KEY (uuid)\n);\n", sql)
+}
+
+func TestCreateTableSqlWithForeignKeys(t *testing.T) {
+	fk := schema.ForeignKey{Columns: []string{"shop_id"}, RefTable: "shop", RefColumns: []string{"id"}, OnDelete: entity.Cascade}
+
+	sql := (&mysqlDriver{}).CreateTableSql("book", map[string]schema.ColumnType{"id": schema.Int64}, []string{"id"}, entity.Manual, []schema.ForeignKey{fk})
+	assert.Equal(t, "CREATE TABLE IF NOT EXISTS book(\nid BIGINT NOT NULL,\nPRIMARY KEY (id),\nCONSTRAINT book_shop_id_fkey FOREIGN KEY (shop_id) REFERENCES shop(id) ON DELETE CASCADE\n);\n", sql)
+
+	fk.OnDelete = entity.Nullable
+	sql, err := (&mysqlDriver{}).CreateForeignKeySql("book", fk)
+	assert.NoError(t, err)
+	assert.Equal(t, "ALTER TABLE book ADD CONSTRAINT book_shop_id_fkey FOREIGN KEY (shop_id) REFERENCES shop(id) ON DELETE SET NULL;\n", sql)
+}
@@ -2,6 +2,7 @@ package pgx
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"github.com/godzie44/d3/adapter"
@@ -10,6 +11,7 @@ import (
 	"github.com/godzie44/d3/orm/persistence"
 	"github.com/godzie44/d3/orm/query"
 	"github.com/godzie44/d3/orm/schema"
+	"github.com/jackc/pgconn"
 	"github.com/jackc/pgtype"
 	pgtypeuuid "github.com/jackc/pgtype/ext/gofrs-uuid"
 	"github.com/jackc/pgx/v4"
@@ -21,7 +23,7 @@ import (
 
 type xConn interface {
 	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
-	Begin(ctx context.Context) (pgx.Tx, error)
+	BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error)
 }
 
 type pgxDriver struct {
@@ -83,6 +85,10 @@ func (g *pgxDriver) Close() error {
 
 func (g *pgxDriver) MakeScalarDataMapper() orm.ScalarDataMapper {
 	return func(data interface{}, into reflect.Kind) interface{} {
+		if numeric, ok := data.(*pgtype.Numeric); ok {
+			return mapNumeric(numeric, into)
+		}
+
 		switch into {
 		case reflect.Int:
 			return int(data.(int64))
@@ -92,7 +98,37 @@ func (g *pgxDriver) MakeScalarDataMapper() orm.ScalarDataMapper {
 	}
 }
 
-func (g *pgxDriver) CreateTableSql(name string, columns map[string]schema.ColumnType, pkColumns []string, pkStrategy entity.PkStrategy) string {
+// mapNumeric - map numeric (for example result of SUM over bigint column) into int or float kinds.
+func mapNumeric(numeric *pgtype.Numeric, into reflect.Kind) interface{} {
+	var dst interface{}
+	switch into {
+	case reflect.Int:
+		dst = new(int)
+	case reflect.Int32:
+		dst = new(int32)
+	case reflect.Int64:
+		dst = new(int64)
+	case reflect.Float32:
+		dst = new(float32)
+	case reflect.Float64:
+		dst = new(float64)
+	default:
+		return numeric
+	}
+
+	if err := numeric.AssignTo(dst); err != nil {
+		return numeric
+	}
+	return reflect.ValueOf(dst).Elem().Interface()
+}
+
+// serialTypes - auto increment types of integer columns, used for primary key columns with auto strategy.
+var serialTypes = map[string]string{
+	"integer": "serial",
+	"bigint":  "bigserial",
+}
+
+func (g *pgxDriver) CreateTableSql(name string, columns map[string]schema.ColumnType, pkColumns []string, pkStrategy entity.PkStrategy, foreignKeys []schema.ForeignKey) string {
 	isPkCol := func(colName string) bool {
 		for _, pkCol := range pkColumns {
 			if pkCol == colName {
@@ -108,61 +144,19 @@ func (g *pgxDriver) CreateTableSql(name string, columns map[string]schema.Column
 
 	var colsSql []string
 	for col, ctype := range columns {
+		def := g.ColumnDefinition(ctype, isPkCol(col), pkStrategy)
+
+		colType := def.Type
+		if serial, isInteger := serialTypes[colType]; isInteger && isPkCol(col) && pkStrategy == entity.Auto {
+			colType = serial
+		}
+
 		colSql := strings.Builder{}
 		colSql.WriteString(col)
 		colSql.WriteRune(' ')
-
-		switch ctype {
-		case schema.UUID:
-			colSql.WriteString("UUID")
-		case schema.Bool:
-			colSql.WriteString("BOOLEAN NOT NULL")
-		case schema.Int:
-			if isPkCol(col) && pkStrategy == entity.Auto {
-				colSql.WriteString("BIGSERIAL")
-			} else {
-				colSql.WriteString("BIGINT NOT NULL")
-			}
-		case schema.Int32:
-			if isPkCol(col) && pkStrategy == entity.Auto {
-				colSql.WriteString("SERIAL")
-			} else {
-				colSql.WriteString("INTEGER NOT NULL")
-			}
-		case schema.Int64:
-			if isPkCol(col) && pkStrategy == entity.Auto {
-				colSql.WriteString("BIGSERIAL")
-			} else {
-				colSql.WriteString("BIGINT NOT NULL")
-			}
-		case schema.Float32:
-			colSql.WriteString("REAL NOT NULL")
-		case schema.Float64:
-			colSql.WriteString("DOUBLE PRECISION NOT NULL")
-		case schema.String:
-			colSql.WriteString("TEXT NOT NULL")
-		case schema.Time:
-			colSql.WriteString("TIMESTAMP WITH TIME ZONE NOT NULL")
-		case schema.NullBool:
-			colSql.WriteString("BOOLEAN")
-		case schema.NullInt64:
-			if isPkCol(col) && pkStrategy == entity.Auto {
-				colSql.WriteString("BIGSERIAL")
-			} else {
-				colSql.WriteString("BIGINT")
-			}
-		case schema.NullInt32:
-			if isPkCol(col) && pkStrategy == entity.Auto {
-				colSql.WriteString("SERIAL")
-			} else {
-				colSql.WriteString("INTEGER")
-			}
-		case schema.NullFloat64:
-			colSql.WriteString("DOUBLE PRECISION")
-		case schema.NullString:
-			colSql.WriteString("TEXT")
-		case schema.NullTime:
-			colSql.WriteString("TIMESTAMP WITH TIME ZONE")
+		colSql.WriteString(colType)
+		if !def.Nullable {
+			colSql.WriteString(" NOT NULL")
 		}
 
 		if isPkCol(col) && len(pkColumns) == 1 {
@@ -179,11 +173,40 @@ func (g *pgxDriver) CreateTableSql(name string, columns map[string]schema.Column
 		sql.WriteString(fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(pkColumns, ",")))
 	}
 
+	for _, fk := range foreignKeys {
+		sql.WriteString(",\n")
+		sql.WriteString(foreignKeyDefinition(fk))
+	}
+
 	sql.WriteString("\n);\n")
 
 	return sql.String()
 }
 
+// CreateForeignKeySql - postgres has no ADD CONSTRAINT IF NOT EXISTS, so duplicate constraint error ignored.
+func (g *pgxDriver) CreateForeignKeySql(table string, fk schema.ForeignKey) (string, error) {
+	return fmt.Sprintf(
+		"DO $$ BEGIN\nALTER TABLE %s ADD CONSTRAINT %s_%s_fkey %s;\nEXCEPTION WHEN duplicate_object THEN NULL;\nEND $$;\n",
+		table, table, strings.Join(fk.Columns, "_"), foreignKeyDefinition(fk),
+	), nil
+}
+
+// foreignKeyDefinition - constraints are deferred cause unit of work not guarantee order of actions inside transaction.
+func foreignKeyDefinition(fk schema.ForeignKey) string {
+	var onDelete string
+	switch fk.OnDelete {
+	case entity.Cascade:
+		onDelete = " ON DELETE CASCADE"
+	case entity.Nullable:
+		onDelete = " ON DELETE SET NULL"
+	}
+
+	return fmt.Sprintf(
+		"FOREIGN KEY (%s) REFERENCES %s(%s)%s DEFERRABLE INITIALLY DEFERRED",
+		strings.Join(fk.Columns, ","), fk.RefTable, strings.Join(fk.RefColumns, ","), onDelete,
+	)
+}
+
 func (g *pgxDriver) CreateIndexSql(name string, unique bool, table string, columns ...string) string {
 	var uniqueDef string
 	if unique {
@@ -193,6 +216,192 @@ func (g *pgxDriver) CreateIndexSql(name string, unique bool, table string, colum
 	return fmt.Sprintf("CREATE %s INDEX IF NOT EXISTS %s ON %s (%s);\n", uniqueDef, name, table, strings.Join(columns, ","))
 }
 
+func (g *pgxDriver) ColumnDefinition(ctype schema.ColumnType, isPk bool, _ entity.PkStrategy) schema.ColumnDefinition {
+	var def schema.ColumnDefinition
+
+	switch ctype {
+	case schema.UUID:
+		def = schema.ColumnDefinition{Type: "uuid", Nullable: true}
+	case schema.Bool, schema.NullBool:
+		def = schema.ColumnDefinition{Type: "boolean", Nullable: ctype == schema.NullBool}
+	case schema.Int, schema.Int64, schema.NullInt64:
+		def = schema.ColumnDefinition{Type: "bigint", Nullable: ctype == schema.NullInt64}
+	case schema.Int32, schema.NullInt32:
+		def = schema.ColumnDefinition{Type: "integer", Nullable: ctype == schema.NullInt32}
+	case schema.Float32:
+		def = schema.ColumnDefinition{Type: "real"}
+	case schema.Float64, schema.NullFloat64:
+		def = schema.ColumnDefinition{Type: "double precision", Nullable: ctype == schema.NullFloat64}
+	case schema.String, schema.NullString:
+		def = schema.ColumnDefinition{Type: "text", Nullable: ctype == schema.NullString}
+	case schema.Time, schema.NullTime:
+		def = schema.ColumnDefinition{Type: "timestamp with time zone", Nullable: ctype == schema.NullTime}
+	}
+
+	// primary key columns always not null
+	if isPk {
+		def.Nullable = false
+	}
+
+	return def
+}
+
+func (g *pgxDriver) TableInfo(name string) (*schema.TableInfo, error) {
+	rows, err := g.pgDb.Query(
+		context.Background(),
+		"SELECT column_name, data_type, is_nullable FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = $1",
+		name,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns := make(map[string]schema.ColumnDefinition)
+	for rows.Next() {
+		var colName, colType, isNullable string
+		if err := rows.Scan(&colName, &colType, &isNullable); err != nil {
+			return nil, err
+		}
+		columns[colName] = schema.ColumnDefinition{Type: colType, Nullable: isNullable == "YES"}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(columns) == 0 {
+		return nil, nil
+	}
+
+	indexes, err := g.tableIndexes(name)
+	if err != nil {
+		return nil, err
+	}
+
+	foreignKeys, err := g.tableForeignKeys(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &schema.TableInfo{Columns: columns, Indexes: indexes, ForeignKeys: foreignKeys}, nil
+}
+
+func (g *pgxDriver) tableForeignKeys(table string) ([]schema.ForeignKey, error) {
+	rows, err := g.pgDb.Query(context.Background(), `
+SELECT c.conname, rt.relname, a.attname, ra.attname
+FROM pg_constraint c
+JOIN pg_class t ON t.oid = c.conrelid
+JOIN pg_namespace n ON n.oid = t.relnamespace
+JOIN pg_class rt ON rt.oid = c.confrelid
+CROSS JOIN LATERAL unnest(c.conkey, c.confkey) WITH ORDINALITY AS k(col, ref_col, ord)
+JOIN pg_attribute a ON a.attrelid = c.conrelid AND a.attnum = k.col
+JOIN pg_attribute ra ON ra.attrelid = c.confrelid AND ra.attnum = k.ref_col
+WHERE c.contype = 'f' AND t.relname = $1 AND n.nspname = current_schema()
+ORDER BY c.conname, k.ord
+`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var foreignKeys []schema.ForeignKey
+	var lastName string
+	for rows.Next() {
+		var name, refTable, colName, refColName string
+		if err := rows.Scan(&name, &refTable, &colName, &refColName); err != nil {
+			return nil, err
+		}
+
+		if len(foreignKeys) == 0 || lastName != name {
+			foreignKeys = append(foreignKeys, schema.ForeignKey{RefTable: refTable})
+			lastName = name
+		}
+		fk := &foreignKeys[len(foreignKeys)-1]
+		fk.Columns = append(fk.Columns, colName)
+		fk.RefColumns = append(fk.RefColumns, refColName)
+	}
+
+	return foreignKeys, rows.Err()
+}
+
+func (g *pgxDriver) tableIndexes(table string) ([]entity.Index, error) {
+	rows, err := g.pgDb.Query(context.Background(), `
+SELECT i.relname, ix.indisunique, a.attname
+FROM pg_class t
+JOIN pg_namespace n ON n.oid = t.relnamespace
+JOIN pg_index ix ON t.oid = ix.indrelid
+JOIN pg_class i ON i.oid = ix.indexrelid
+JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = ANY(ix.indkey)
+WHERE t.relname = $1 AND n.nspname = current_schema() AND NOT ix.indisprimary
+ORDER BY i.relname, array_position(ix.indkey::int2[], a.attnum)
+`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var indexes []entity.Index
+	for rows.Next() {
+		var indexName, colName string
+		var unique bool
+		if err := rows.Scan(&indexName, &unique, &colName); err != nil {
+			return nil, err
+		}
+
+		if len(indexes) == 0 || indexes[len(indexes)-1].Name != indexName {
+			indexes = append(indexes, entity.Index{Name: indexName, Unique: unique})
+		}
+		indexes[len(indexes)-1].Columns = append(indexes[len(indexes)-1].Columns, colName)
+	}
+
+	return indexes, rows.Err()
+}
+
+func (g *pgxDriver) AddColumnSql(table, column string, def schema.ColumnDefinition) string {
+	if def.Nullable {
+		return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s;\n", table, column, def.Type)
+	}
+
+	// fill existing rows with zero value, then drop default
+	return fmt.Sprintf(
+		"ALTER TABLE %s ADD COLUMN %s %s NOT NULL DEFAULT %s;\nALTER TABLE %s ALTER COLUMN %s DROP DEFAULT;\n",
+		table, column, def.Type, zeroValueSql(def.Type), table, column,
+	)
+}
+
+func zeroValueSql(colType string) string {
+	switch colType {
+	case "boolean":
+		return "false"
+	case "text":
+		return "''"
+	case "timestamp with time zone":
+		return "'epoch'"
+	default:
+		return "0"
+	}
+}
+
+func (g *pgxDriver) DropColumnSql(table, column string) (string, error) {
+	return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;\n", table, column), nil
+}
+
+func (g *pgxDriver) AlterColumnSql(table, column string, def schema.ColumnDefinition) (string, error) {
+	nullability := "SET NOT NULL"
+	if def.Nullable {
+		nullability = "DROP NOT NULL"
+	}
+
+	return fmt.Sprintf(
+		"ALTER TABLE %s ALTER COLUMN %s TYPE %s USING %s::%s;\nALTER TABLE %s ALTER COLUMN %s %s;\n",
+		table, column, def.Type, column, def.Type, table, column, nullability,
+	), nil
+}
+
+func (g *pgxDriver) DropIndexSql(_, name string) string {
+	return fmt.Sprintf("DROP INDEX IF EXISTS %s;\n", name)
+}
+
 func (g *pgxDriver) BeforeQuery(fn func(query string, args ...interface{})) {
 	g.beforeQCallback = append(g.beforeQCallback, fn)
 }
@@ -201,7 +410,7 @@ func (g *pgxDriver) AfterQuery(fn func(query string, args ...interface{})) {
 	g.afterQCallback = append(g.afterQCallback, fn)
 }
 
-func (g *pgxDriver) ExecuteQuery(query *query.Query, tx orm.Transaction) ([]map[string]interface{}, error) {
+func (g *pgxDriver) ExecuteQuery(ctx context.Context, query *query.Query, tx orm.Transaction) ([]map[string]interface{}, error) {
 	q, args, err := adapter.QueryToSql(query)
 	if err != nil {
 		return nil, err
@@ -216,10 +425,11 @@ func (g *pgxDriver) ExecuteQuery(query *query.Query, tx orm.Transaction) ([]map[
 		panic(errors.New("transaction type must be pgxTransaction"))
 	}
 
-	rows, err := pgxTx.tx.Query(context.Background(), q, args...)
+	rows, err := pgxTx.tx.Query(ctx, q, args...)
 	if err != nil {
-		return nil, err
+		return nil, translateError("", err)
 	}
+	defer rows.Close()
 
 	for i := range g.afterQCallback {
 		g.afterQCallback[i](q, args...)
@@ -240,7 +450,41 @@ func (g *pgxDriver) ExecuteQuery(query *query.Query, tx orm.Transaction) ([]map[
 		result = append(result, m)
 	}
 
-	return result, nil
+	return result, translateError("", rows.Err())
+}
+
+var constraintViolations = map[string]error{
+	"23505": orm.ErrUniqueViolation,
+	"23503": orm.ErrForeignKeyViolation,
+	"23502": orm.ErrNotNullViolation,
+	"23514": orm.ErrCheckViolation,
+}
+
+// translateError - translate postgres constraint violation into orm.ConstraintError, other errors returned as is.
+func translateError(table string, err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	kind, isViolation := constraintViolations[pgErr.Code]
+	if !isViolation {
+		return err
+	}
+
+	if pgErr.TableName != "" {
+		table = pgErr.TableName
+	}
+	return orm.NewConstraintError(kind, table, pgErr.ConstraintName, err)
+}
+
+// IsRetryable - report that error is serialization failure or deadlock, so transaction can be repeated.
+func (g *pgxDriver) IsRetryable(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == "40001" || pgErr.Code == "40P01"
 }
 
 func (g *pgxDriver) MakePusher(tx orm.Transaction) persistence.Pusher {
@@ -256,7 +500,7 @@ type pgxPusher struct {
 	tx *pgxTransaction
 }
 
-func (p *pgxPusher) Insert(table string, cols []string, values []interface{}, onConflict persistence.OnConflict) error {
+func (p *pgxPusher) Insert(ctx context.Context, table string, cols []string, values []interface{}, onConflict persistence.OnConflict) error {
 	argsPlaceHolders := make([]string, len(values))
 	for i := 0; i < len(values); i++ {
 		argsPlaceHolders[i] = "$" + strconv.Itoa(i+1)
@@ -271,19 +515,20 @@ func (p *pgxPusher) Insert(table string, cols []string, values []interface{}, on
 	}
 
 	_, err := p.tx.tx.Exec(
-		context.Background(),
+		ctx,
 		sql,
 		values...,
 	)
 
 	if err != nil {
-		return fmt.Errorf("insert pgx driver: %w", err)
+		return fmt.Errorf("insert pgx driver: %w", translateError(table, err))
 	}
 
 	return nil
 }
 
 func (p *pgxPusher) InsertWithReturn(
+	ctx context.Context,
 	table string,
 	cols []string,
 	values []interface{},
@@ -296,19 +541,19 @@ func (p *pgxPusher) InsertWithReturn(
 	}
 
 	row := p.tx.tx.QueryRow(
-		context.Background(),
+		ctx,
 		fmt.Sprintf("INSERT INTO %s(%s) VALUES(%s) RETURNING %s", table, strings.Join(cols, ","), strings.Join(argsPlaceHolders, ","), strings.Join(returnCols, ",")),
 		values...,
 	)
 
 	if err := withReturned(row); err != nil {
-		return fmt.Errorf("insert pgx driver: %w", err)
+		return fmt.Errorf("insert pgx driver: %w", translateError(table, err))
 	}
 
 	return nil
 }
 
-func (p *pgxPusher) Update(table string, cols []string, values []interface{}, identityCond map[string]interface{}) error {
+func (p *pgxPusher) Update(ctx context.Context, table string, cols []string, values []interface{}, identityCond map[string]interface{}) (int64, error) {
 	queryValues := values
 
 	setCommands := make([]string, len(values))
@@ -317,25 +562,25 @@ func (p *pgxPusher) Update(table string, cols []string, values []interface{}, id
 		setCommands[placeholderNum] = cols[placeholderNum] + "=$" + strconv.Itoa(placeholderNum+1)
 	}
 
-	var whereStr string
+	where := make([]string, 0, len(identityCond))
 	for col, val := range identityCond {
 		queryValues = append(queryValues, val)
-		whereStr += " " + col + "=$" + strconv.Itoa(placeholderNum+1)
+		where = append(where, col+"=$"+strconv.Itoa(placeholderNum+1))
 		placeholderNum++
 	}
 
-	_, err := p.tx.tx.Exec(
-		context.Background(),
-		fmt.Sprintf("UPDATE %s SET %s WHERE %s", table, strings.Join(setCommands, ","), whereStr),
+	res, err := p.tx.tx.Exec(
+		ctx,
+		fmt.Sprintf("UPDATE %s SET %s WHERE %s", table, strings.Join(setCommands, ","), strings.Join(where, " AND ")),
 		queryValues...,
 	)
 	if err != nil {
-		return fmt.Errorf("insert pgx driver: %w", err)
+		return 0, fmt.Errorf("update pgx driver: %w", translateError(table, err))
 	}
-	return nil
+	return res.RowsAffected(), nil
 }
 
-func (p *pgxPusher) Remove(table string, identityCond map[string]interface{}) error {
+func (p *pgxPusher) Remove(ctx context.Context, table string, identityCond map[string]interface{}) error {
 	args := make([]interface{}, 0, len(identityCond))
 	where := make([]string, 0, len(identityCond))
 
@@ -345,31 +590,78 @@ func (p *pgxPusher) Remove(table string, identityCond map[string]interface{}) er
 	}
 
 	_, err := p.tx.tx.Exec(
-		context.Background(),
+		ctx,
 		fmt.Sprintf("DELETE FROM %s WHERE %s", table, strings.Join(where, " AND ")),
 		args...,
 	)
 
-	return err
+	return translateError(table, err)
 }
 
 type pgxTransaction struct {
-	tx pgx.Tx
+	tx  pgx.Tx
+	ctx context.Context
 }
 
+// Commit - deferred foreign keys checked on commit, so commit error may be a constraint violation.
 func (p *pgxTransaction) Commit() error {
-	return p.tx.Commit(context.Background())
+	return translateError("", p.tx.Commit(p.ctx))
 }
 
+// Rollback - rollback not bound to transaction context, cause connection must be released even if context canceled.
 func (p *pgxTransaction) Rollback() error {
 	return p.tx.Rollback(context.Background())
 }
 
-func (g *pgxDriver) BeginTx() (orm.Transaction, error) {
-	tx, err := g.pgDb.Begin(context.Background())
+func (p *pgxTransaction) Savepoint(name string) error {
+	_, err := p.tx.Exec(p.ctx, "SAVEPOINT "+name)
+	return err
+}
+
+func (p *pgxTransaction) RollbackToSavepoint(name string) error {
+	_, err := p.tx.Exec(p.ctx, "ROLLBACK TO SAVEPOINT "+name)
+	return err
+}
+
+func (p *pgxTransaction) ReleaseSavepoint(name string) error {
+	_, err := p.tx.Exec(p.ctx, "RELEASE SAVEPOINT "+name)
+	return err
+}
+
+var pgxIsoLevels = map[sql.IsolationLevel]pgx.TxIsoLevel{
+	sql.LevelDefault:         "",
+	sql.LevelReadUncommitted: pgx.ReadUncommitted,
+	sql.LevelReadCommitted:   pgx.ReadCommitted,
+	sql.LevelRepeatableRead:  pgx.RepeatableRead,
+	sql.LevelSerializable:    pgx.Serializable,
+}
+
+func toPgxTxOptions(opts orm.TxOptions) (pgx.TxOptions, error) {
+	isoLevel, exists := pgxIsoLevels[opts.Isolation]
+	if !exists {
+		return pgx.TxOptions{}, fmt.Errorf("unsupported isolation level: %s", opts.Isolation)
+	}
+
+	pgxOpts := pgx.TxOptions{IsoLevel: isoLevel}
+	if opts.ReadOnly {
+		pgxOpts.AccessMode = pgx.ReadOnly
+	}
+	if opts.Deferrable {
+		pgxOpts.DeferrableMode = pgx.Deferrable
+	}
+	return pgxOpts, nil
+}
+
+func (g *pgxDriver) BeginTx(ctx context.Context, opts orm.TxOptions) (orm.Transaction, error) {
+	pgxOpts, err := toPgxTxOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := g.pgDb.BeginTx(ctx, pgxOpts)
 	if err != nil {
 		return nil, err
 	}
 
-	return &pgxTransaction{tx: tx}, nil
+	return &pgxTransaction{tx: tx, ctx: ctx}, nil
 }
@@ -2,15 +2,24 @@ package pgx
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/godzie44/d3/orm"
+	"github.com/godzie44/d3/orm/entity"
 	"github.com/godzie44/d3/orm/persistence"
 	"github.com/godzie44/d3/orm/query"
+	"github.com/godzie44/d3/orm/schema"
 	"github.com/godzie44/d3/tests/helpers"
 	"github.com/jackc/pgconn"
+	"github.com/jackc/pgtype"
 	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+	"math/big"
 	"os"
+	"reflect"
 	"testing"
 )
 
@@ -76,29 +85,29 @@ func TestPgxPoolDriverTestSuite(t *testing.T) {
 }
 
 func (p *PgxDriverTS) TestPgxDriverQuery() {
-	tx, err := p.driver.BeginTx()
+	tx, err := p.driver.BeginTx(context.Background(), orm.TxOptions{})
 	p.NoError(err)
 	defer tx.Commit() //nolint
 
-	data, err := p.driver.ExecuteQuery(query.New().Select("*").From("pgx_test_table").Where("id", "=", "1"), tx)
+	data, err := p.driver.ExecuteQuery(context.Background(), query.New().Select("*").From("pgx_test_table").Where("id", "=", "1"), tx)
 	p.NoError(err)
 
 	p.Len(data, 1)
 	p.Equal(data[0], map[string]interface{}{"id": int32(1), "data": "test 1"})
 
-	data, err = p.driver.ExecuteQuery(query.New().Select("*").From("pgx_test_table"), tx)
+	data, err = p.driver.ExecuteQuery(context.Background(), query.New().Select("*").From("pgx_test_table"), tx)
 	p.NoError(err)
 
 	p.Len(data, 3)
 }
 
 func (p *PgxDriverTS) TestPgxDriverTxInsert() {
-	tx, err := p.driver.BeginTx()
+	tx, err := p.driver.BeginTx(context.Background(), orm.TxOptions{})
 	p.NoError(err)
 
 	pusher := p.driver.MakePusher(tx)
 
-	err = pusher.Insert("pgx_test_table", []string{"id", "data"}, []interface{}{4, "test 4"}, persistence.Undefined)
+	err = pusher.Insert(context.Background(), "pgx_test_table", []string{"id", "data"}, []interface{}{4, "test 4"}, persistence.Undefined)
 	p.NoError(err)
 	p.NoError(tx.Commit())
 
@@ -106,12 +115,12 @@ func (p *PgxDriverTS) TestPgxDriverTxInsert() {
 }
 
 func (p *PgxDriverTS) TestPgxDriverTxUpdate() {
-	tx, err := p.driver.BeginTx()
+	tx, err := p.driver.BeginTx(context.Background(), orm.TxOptions{})
 	p.NoError(err)
 
 	pusher := p.driver.MakePusher(tx)
 
-	err = pusher.Update("pgx_test_table", []string{"data"}, []interface{}{"test upd"}, map[string]interface{}{"id": 1})
+	_, err = pusher.Update(context.Background(), "pgx_test_table", []string{"data"}, []interface{}{"test upd"}, map[string]interface{}{"id": 1})
 	p.NoError(err)
 	p.NoError(tx.Commit())
 
@@ -119,12 +128,12 @@ func (p *PgxDriverTS) TestPgxDriverTxUpdate() {
 }
 
 func (p *PgxDriverTS) TestPgxDriverTxDelete() {
-	tx, err := p.driver.BeginTx()
+	tx, err := p.driver.BeginTx(context.Background(), orm.TxOptions{})
 	p.NoError(err)
 
 	pusher := p.driver.MakePusher(tx)
 
-	err = pusher.Remove("pgx_test_table", map[string]interface{}{"id": 1})
+	err = pusher.Remove(context.Background(), "pgx_test_table", map[string]interface{}{"id": 1})
 	p.NoError(err)
 	p.NoError(tx.Commit())
 
@@ -132,14 +141,147 @@ func (p *PgxDriverTS) TestPgxDriverTxDelete() {
 }
 
 func (p *PgxDriverTS) TestPgxDriverTxRollback() {
-	tx, err := p.driver.BeginTx()
+	tx, err := p.driver.BeginTx(context.Background(), orm.TxOptions{})
 	p.NoError(err)
 
 	pusher := p.driver.MakePusher(tx)
 
-	err = pusher.Insert("pgx_test_table", []string{"id", "data"}, []interface{}{4, "test 4"}, persistence.Undefined)
+	err = pusher.Insert(context.Background(), "pgx_test_table", []string{"id", "data"}, []interface{}{4, "test 4"}, persistence.Undefined)
 	p.NoError(err)
 	p.NoError(tx.Rollback())
 
 	p.tester.SeeThree("select * from pgx_test_table")
 }
+
+func (p *PgxDriverTS) TestPgxDriverTxSavepoint() {
+	tx, err := p.driver.BeginTx(context.Background(), orm.TxOptions{})
+	p.NoError(err)
+
+	pusher := p.driver.MakePusher(tx)
+
+	err = pusher.Insert(context.Background(), "pgx_test_table", []string{"id", "data"}, []interface{}{4, "test 4"}, persistence.Undefined)
+	p.NoError(err)
+
+	p.NoError(tx.Savepoint("sp1"))
+	err = pusher.Insert(context.Background(), "pgx_test_table", []string{"id", "data"}, []interface{}{5, "test 5"}, persistence.Undefined)
+	p.NoError(err)
+	p.NoError(tx.RollbackToSavepoint("sp1"))
+	p.NoError(tx.ReleaseSavepoint("sp1"))
+	p.NoError(tx.Commit())
+
+	p.tester.SeeFour("select * from pgx_test_table")
+}
+
+func (p *PgxDriverTS) TestPgxDriverReadOnlyTx() {
+	tx, err := p.driver.BeginTx(context.Background(), orm.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	p.NoError(err)
+	defer tx.Rollback() //nolint
+
+	pusher := p.driver.MakePusher(tx)
+	err = pusher.Insert(context.Background(), "pgx_test_table", []string{"id", "data"}, []interface{}{4, "test 4"}, persistence.Undefined)
+	p.Error(err)
+}
+
+func (p *PgxDriverTS) TestPgxDriverConstraintError() {
+	tx, err := p.driver.BeginTx(context.Background(), orm.TxOptions{})
+	p.NoError(err)
+	defer tx.Rollback() //nolint
+
+	pusher := p.driver.MakePusher(tx)
+
+	err = pusher.Insert(context.Background(), "pgx_test_table", []string{"id", "data"}, []interface{}{1, "test 1"}, persistence.Undefined)
+	p.True(errors.Is(err, orm.ErrUniqueViolation))
+
+	var constraintErr *orm.ConstraintError
+	p.True(errors.As(err, &constraintErr))
+	p.Equal("pgx_test_table", constraintErr.Table)
+	p.Equal("pgx_test_table_pk", constraintErr.Constraint)
+}
+
+func (p *PgxDriverTS) TestPgxDriverDeferredConstraintErrorOnCommit() {
+	_, err := p.driver.UnwrapConn().(pgxExecer).Exec(context.Background(), `CREATE TABLE IF NOT EXISTS pgx_test_ref_table(
+		id integer NOT NULL,
+		test_id integer,
+		CONSTRAINT pgx_test_ref_table_pk PRIMARY KEY (id),
+		CONSTRAINT pgx_test_ref_table_fk FOREIGN KEY (test_id) REFERENCES pgx_test_table(id) DEFERRABLE INITIALLY DEFERRED
+	)`)
+	p.NoError(err)
+	defer func() {
+		_, err := p.driver.UnwrapConn().(pgxExecer).Exec(context.Background(), `DROP TABLE pgx_test_ref_table;`)
+		p.NoError(err)
+	}()
+
+	tx, err := p.driver.BeginTx(context.Background(), orm.TxOptions{})
+	p.NoError(err)
+
+	err = p.driver.MakePusher(tx).Insert(context.Background(), "pgx_test_ref_table", []string{"id", "test_id"}, []interface{}{1, 100}, persistence.Undefined)
+	p.NoError(err)
+
+	err = tx.Commit()
+	p.True(errors.Is(err, orm.ErrForeignKeyViolation))
+
+	var constraintErr *orm.ConstraintError
+	p.True(errors.As(err, &constraintErr))
+	p.Equal("pgx_test_ref_table", constraintErr.Table)
+	p.Equal("pgx_test_ref_table_fk", constraintErr.Constraint)
+}
+
+func TestToPgxTxOptions(t *testing.T) {
+	opts, err := toPgxTxOptions(orm.TxOptions{Isolation: sql.LevelSerializable, ReadOnly: true, Deferrable: true})
+	assert.NoError(t, err)
+	assert.Equal(t, pgx.TxOptions{IsoLevel: pgx.Serializable, AccessMode: pgx.ReadOnly, DeferrableMode: pgx.Deferrable}, opts)
+
+	opts, err = toPgxTxOptions(orm.TxOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, pgx.TxOptions{}, opts)
+
+	_, err = toPgxTxOptions(orm.TxOptions{Isolation: sql.LevelLinearizable})
+	assert.Error(t, err)
+}
+
+func TestTranslateError(t *testing.T) {
+	pgErr := &pgconn.PgError{Code: "23503", TableName: "book", ConstraintName: "book_author_fk"}
+
+	err := translateError("author", pgErr)
+	assert.True(t, errors.Is(err, orm.ErrForeignKeyViolation))
+	assert.False(t, errors.Is(err, orm.ErrUniqueViolation))
+	assert.Equal(t, orm.NewConstraintError(orm.ErrForeignKeyViolation, "book", "book_author_fk", pgErr), err)
+
+	otherErr := &pgconn.PgError{Code: "42P01"}
+	assert.Equal(t, otherErr, translateError("book", otherErr))
+	assert.Nil(t, translateError("book", nil))
+}
+
+func TestIsRetryable(t *testing.T) {
+	driver := &pgxDriver{}
+
+	assert.True(t, driver.IsRetryable(fmt.Errorf("insert pgx driver: %w", &pgconn.PgError{Code: "40001"})))
+	assert.True(t, driver.IsRetryable(&pgconn.PgError{Code: "40P01"}))
+	assert.False(t, driver.IsRetryable(&pgconn.PgError{Code: "23505"}))
+	assert.False(t, driver.IsRetryable(errors.New("other error")))
+}
+
+func TestScalarDataMapperMapNumeric(t *testing.T) {
+	mapper := (&pgxDriver{}).MakeScalarDataMapper()
+	numeric := &pgtype.Numeric{Int: big.NewInt(94), Status: pgtype.Present}
+
+	assert.Equal(t, 94, mapper(numeric, reflect.Int))
+	assert.Equal(t, int64(94), mapper(numeric, reflect.Int64))
+	assert.Equal(t, float64(94), mapper(numeric, reflect.Float64))
+	assert.Equal(t, numeric, mapper(numeric, reflect.String))
+}
+
+func TestCreateTableSql(t *testing.T) {
+	driver := &pgxDriver{}
+
+	sql := driver.CreateTableSql("shop", map[string]schema.ColumnType{"id": schema.Int64}, []string{"id"}, entity.Auto, nil)
+	assert.Equal(t, "CREATE TABLE IF NOT EXISTS shop(\nid bigserial NOT NULL PRIMARY KEY\n);\n", sql)
+
+	sql = driver.CreateTableSql("shop", map[string]schema.ColumnType{"id": schema.NullInt32}, []string{"id"}, entity.Manual, nil)
+	assert.Equal(t, "CREATE TABLE IF NOT EXISTS shop(\nid integer NOT NULL PRIMARY KEY\n);\n", sql)
+
+	fk := schema.ForeignKey{Columns: []string{"shop_id"}, RefTable: "shop", RefColumns: []string{"id"}, OnDelete: entity.Nullable}
+	sql = driver.CreateTableSql("book", map[string]schema.ColumnType{"shop_id": schema.NullInt64}, nil, entity.Auto, []schema.ForeignKey{fk})
+	assert.Equal(t, "CREATE TABLE IF NOT EXISTS book(\nshop_id bigint,\n"+
+		"FOREIGN KEY (shop_id) REFERENCES shop(id) ON DELETE SET NULL DEFERRABLE INITIALLY DEFERRED\n);\n", sql)
+}
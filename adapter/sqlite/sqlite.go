@@ -1,6 +1,7 @@
 package sqlite
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/godzie44/d3/adapter"
@@ -9,7 +10,7 @@ import (
 	"github.com/godzie44/d3/orm/persistence"
 	"github.com/godzie44/d3/orm/query"
 	"github.com/godzie44/d3/orm/schema"
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/mattn/go-sqlite3"
 	"reflect"
 	"strconv"
 	"strings"
@@ -17,6 +18,8 @@ import (
 	"database/sql"
 )
 
+var ErrLockUnsupported = errors.New("sqlite not support row locking clause (FOR UPDATE, FOR SHARE)")
+
 type sqliteDriver struct {
 	beforeQCallback, afterQCallback []func(query string, args ...interface{})
 
@@ -42,7 +45,16 @@ func (s *sqliteDriver) Close() error {
 	return s.db.Close()
 }
 
-func (s *sqliteDriver) ExecuteQuery(query *query.Query, tx orm.Transaction) ([]map[string]interface{}, error) {
+// SupportsRowLock - sqlite not support row locking clauses, database locked by write transaction instead.
+func (s *sqliteDriver) SupportsRowLock() bool {
+	return false
+}
+
+func (s *sqliteDriver) ExecuteQuery(ctx context.Context, query *query.Query, tx orm.Transaction) ([]map[string]interface{}, error) {
+	if adapter.HasLock(query) {
+		return nil, ErrLockUnsupported
+	}
+
 	q, args, err := adapter.QueryToSql(query)
 	if err != nil {
 		return nil, err
@@ -57,9 +69,9 @@ func (s *sqliteDriver) ExecuteQuery(query *query.Query, tx orm.Transaction) ([]m
 		panic(errors.New("transaction type must be sqliteTransaction"))
 	}
 
-	rows, err := sqliteTx.tx.Query(q, args...)
+	rows, err := sqliteTx.tx.QueryContext(ctx, q, args...)
 	if err != nil {
-		return nil, err
+		return nil, translateError("", err)
 	}
 	defer rows.Close()
 
@@ -125,16 +137,32 @@ type sqliteTransaction struct {
 	tx *sql.Tx
 }
 
+// Commit - deferred foreign keys checked on commit, so commit error may be a constraint violation.
 func (t *sqliteTransaction) Commit() error {
-	return t.tx.Commit()
+	return translateError("", t.tx.Commit())
 }
 
 func (t *sqliteTransaction) Rollback() error {
 	return t.tx.Rollback()
 }
 
-func (s *sqliteDriver) BeginTx() (orm.Transaction, error) {
-	tx, err := s.db.Begin()
+func (t *sqliteTransaction) Savepoint(name string) error {
+	_, err := t.tx.Exec("SAVEPOINT " + name)
+	return err
+}
+
+func (t *sqliteTransaction) RollbackToSavepoint(name string) error {
+	_, err := t.tx.Exec("ROLLBACK TO SAVEPOINT " + name)
+	return err
+}
+
+func (t *sqliteTransaction) ReleaseSavepoint(name string) error {
+	_, err := t.tx.Exec("RELEASE SAVEPOINT " + name)
+	return err
+}
+
+func (s *sqliteDriver) BeginTx(ctx context.Context, opts orm.TxOptions) (orm.Transaction, error) {
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{Isolation: opts.Isolation, ReadOnly: opts.ReadOnly})
 	if err != nil {
 		return nil, err
 	}
@@ -157,7 +185,7 @@ type sqlitePusher struct {
 	tx *sqliteTransaction
 }
 
-func (s *sqlitePusher) Insert(table string, cols []string, values []interface{}, onConflict persistence.OnConflict) error {
+func (s *sqlitePusher) Insert(ctx context.Context, table string, cols []string, values []interface{}, onConflict persistence.OnConflict) error {
 	argsPlaceHolders := make([]string, len(values))
 	for i := 0; i < len(values); i++ {
 		argsPlaceHolders[i] = "$" + strconv.Itoa(i+1)
@@ -173,12 +201,13 @@ func (s *sqlitePusher) Insert(table string, cols []string, values []interface{},
 
 	sql := fmt.Sprintf("INSERT %s INTO %s(%s) VALUES(%s)", onConflictClause, table, strings.Join(cols, ","), strings.Join(argsPlaceHolders, ","))
 
-	_, err := s.tx.tx.Exec(
+	_, err := s.tx.tx.ExecContext(
+		ctx,
 		sql,
 		values...,
 	)
 	if err != nil {
-		return fmt.Errorf("insert sqlite driver: %w", err)
+		return fmt.Errorf("insert sqlite driver: %w", translateError(table, err))
 	}
 
 	return nil
@@ -203,6 +232,7 @@ func (i *idScanner) Scan(v ...interface{}) error {
 }
 
 func (s *sqlitePusher) InsertWithReturn(
+	ctx context.Context,
 	table string,
 	cols []string,
 	values []interface{},
@@ -214,12 +244,13 @@ func (s *sqlitePusher) InsertWithReturn(
 		argsPlaceHolders[i] = "$" + strconv.Itoa(i+1)
 	}
 
-	res, err := s.tx.tx.Exec(
+	res, err := s.tx.tx.ExecContext(
+		ctx,
 		fmt.Sprintf("INSERT INTO %s(%s) VALUES(%s)", table, strings.Join(cols, ","), strings.Join(argsPlaceHolders, ",")),
 		values...,
 	)
 	if err != nil {
-		return err
+		return fmt.Errorf("insert sqlite driver: %w", translateError(table, err))
 	}
 
 	id, err := res.LastInsertId()
@@ -234,7 +265,7 @@ func (s *sqlitePusher) InsertWithReturn(
 	return nil
 }
 
-func (s *sqlitePusher) Update(table string, cols []string, values []interface{}, identityCond map[string]interface{}) error {
+func (s *sqlitePusher) Update(ctx context.Context, table string, cols []string, values []interface{}, identityCond map[string]interface{}) (int64, error) {
 	queryValues := values
 
 	setCommands := make([]string, len(values))
@@ -243,25 +274,26 @@ func (s *sqlitePusher) Update(table string, cols []string, values []interface{},
 		setCommands[placeholderNum] = cols[placeholderNum] + "=$" + strconv.Itoa(placeholderNum+1)
 	}
 
-	var whereStr string
+	where := make([]string, 0, len(identityCond))
 	for col, val := range identityCond {
 		queryValues = append(queryValues, val)
-		whereStr += " " + col + "=$" + strconv.Itoa(placeholderNum+1)
+		where = append(where, col+"=$"+strconv.Itoa(placeholderNum+1))
 		placeholderNum++
 	}
 
-	_, err := s.tx.tx.Exec(
-		fmt.Sprintf("UPDATE %s SET %s WHERE %s", table, strings.Join(setCommands, ","), whereStr),
+	res, err := s.tx.tx.ExecContext(
+		ctx,
+		fmt.Sprintf("UPDATE %s SET %s WHERE %s", table, strings.Join(setCommands, ","), strings.Join(where, " AND ")),
 		queryValues...,
 	)
 	if err != nil {
-		return fmt.Errorf("insert sqlite driver: %w", err)
+		return 0, fmt.Errorf("update sqlite driver: %w", translateError(table, err))
 	}
 
-	return nil
+	return res.RowsAffected()
 }
 
-func (s *sqlitePusher) Remove(table string, identityCond map[string]interface{}) error {
+func (s *sqlitePusher) Remove(ctx context.Context, table string, identityCond map[string]interface{}) error {
 	args := make([]interface{}, 0, len(identityCond))
 	where := make([]string, 0, len(identityCond))
 
@@ -270,15 +302,44 @@ func (s *sqlitePusher) Remove(table string, identityCond map[string]interface{})
 		where = append(where, col+"=$"+strconv.Itoa(len(args)))
 	}
 
-	_, err := s.tx.tx.Exec(
+	_, err := s.tx.tx.ExecContext(
+		ctx,
 		fmt.Sprintf("DELETE FROM %s WHERE %s", table, strings.Join(where, " AND ")),
 		args...,
 	)
 
-	return err
+	return translateError(table, err)
+}
+
+var constraintViolations = map[sqlite3.ErrNoExtended]error{
+	sqlite3.ErrConstraintUnique:     orm.ErrUniqueViolation,
+	sqlite3.ErrConstraintPrimaryKey: orm.ErrUniqueViolation,
+	sqlite3.ErrConstraintForeignKey: orm.ErrForeignKeyViolation,
+	sqlite3.ErrConstraintNotNull:    orm.ErrNotNullViolation,
+	sqlite3.ErrConstraintCheck:      orm.ErrCheckViolation,
+}
+
+// translateError - translate sqlite constraint violation into orm.ConstraintError, other errors returned as is.
+// Sqlite not report constraint name, so columns (or check name) from error message used instead.
+func translateError(table string, err error) error {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) {
+		return err
+	}
+
+	kind, isViolation := constraintViolations[sqliteErr.ExtendedCode]
+	if !isViolation {
+		return err
+	}
+
+	var constraint string
+	if idx := strings.Index(sqliteErr.Error(), "constraint failed: "); idx != -1 {
+		constraint = sqliteErr.Error()[idx+len("constraint failed: "):]
+	}
+	return orm.NewConstraintError(kind, table, constraint, err)
 }
 
-func (s *sqliteDriver) CreateTableSql(name string, columns map[string]schema.ColumnType, pkColumns []string, pkStrategy entity.PkStrategy) string {
+func (s *sqliteDriver) CreateTableSql(name string, columns map[string]schema.ColumnType, pkColumns []string, pkStrategy entity.PkStrategy, foreignKeys []schema.ForeignKey) string {
 	isPkCol := func(colName string) bool {
 		for _, pkCol := range pkColumns {
 			if pkCol == colName {
@@ -294,70 +355,20 @@ func (s *sqliteDriver) CreateTableSql(name string, columns map[string]schema.Col
 
 	var colsSql []string
 	for col, ctype := range columns {
-		needAI := false
+		def := s.ColumnDefinition(ctype, isPkCol(col), pkStrategy)
 
 		colSql := strings.Builder{}
 		colSql.WriteString(col)
 		colSql.WriteRune(' ')
-
-		switch ctype {
-		case schema.UUID:
-			colSql.WriteString("TEXT")
-		case schema.Bool:
-			colSql.WriteString("BOOLEAN NOT NULL")
-		case schema.Int:
-			if isPkCol(col) && pkStrategy == entity.Auto {
-				needAI = true
-				colSql.WriteString("INTEGER NOT NULL")
-			} else {
-				colSql.WriteString("BIGINT NOT NULL")
-			}
-		case schema.Int32:
-			if isPkCol(col) && pkStrategy == entity.Auto {
-				needAI = true
-			}
-			colSql.WriteString("INTEGER NOT NULL")
-		case schema.Int64:
-			if isPkCol(col) && pkStrategy == entity.Auto {
-				needAI = true
-				colSql.WriteString("INTEGER NOT NULL")
-			} else {
-				colSql.WriteString("BIGINT NOT NULL")
-			}
-		case schema.Float32:
-			colSql.WriteString("FLOAT NOT NULL")
-		case schema.Float64:
-			colSql.WriteString("DOUBLE NOT NULL")
-		case schema.String:
-			colSql.WriteString("TEXT NOT NULL")
-		case schema.Time:
-			colSql.WriteString("datetime NOT NULL")
-		case schema.NullBool:
-			colSql.WriteString("BOOLEAN")
-		case schema.NullInt64:
-			if isPkCol(col) && pkStrategy == entity.Auto {
-				needAI = true
-				colSql.WriteString("INTEGER")
-			} else {
-				colSql.WriteString("BIGINT")
-			}
-		case schema.NullInt32:
-			if isPkCol(col) && pkStrategy == entity.Auto {
-				needAI = true
-			}
-			colSql.WriteString("INTEGER")
-		case schema.NullFloat64:
-			colSql.WriteString("DOUBLE")
-		case schema.NullString:
-			colSql.WriteString("TEXT")
-		case schema.NullTime:
-			colSql.WriteString("datetime")
+		colSql.WriteString(def.Type)
+		if !def.Nullable {
+			colSql.WriteString(" NOT NULL")
 		}
 
 		if isPkCol(col) && len(pkColumns) == 1 {
 			colSql.WriteRune(' ')
 			colSql.WriteString("PRIMARY KEY")
-			if needAI {
+			if isAutoIncrement(ctype, true, pkStrategy) {
 				colSql.WriteString(" AUTOINCREMENT")
 			}
 		}
@@ -371,11 +382,205 @@ func (s *sqliteDriver) CreateTableSql(name string, columns map[string]schema.Col
 		sql.WriteString(fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(pkColumns, ",")))
 	}
 
+	for _, fk := range foreignKeys {
+		sql.WriteString(",\n")
+		sql.WriteString(foreignKeyDefinition(fk))
+	}
+
 	sql.WriteString("\n);\n")
 
 	return sql.String()
 }
 
+// CreateForeignKeySql - sqlite not support adding constraints into existing table.
+func (s *sqliteDriver) CreateForeignKeySql(_ string, _ schema.ForeignKey) (string, error) {
+	return "", schema.ErrAlterUnsupported
+}
+
+// foreignKeyDefinition - constraints are deferred cause unit of work not guarantee order of actions inside transaction.
+func foreignKeyDefinition(fk schema.ForeignKey) string {
+	var onDelete string
+	switch fk.OnDelete {
+	case entity.Cascade:
+		onDelete = " ON DELETE CASCADE"
+	case entity.Nullable:
+		onDelete = " ON DELETE SET NULL"
+	}
+
+	return fmt.Sprintf(
+		"FOREIGN KEY (%s) REFERENCES %s(%s)%s DEFERRABLE INITIALLY DEFERRED",
+		strings.Join(fk.Columns, ","), fk.RefTable, strings.Join(fk.RefColumns, ","), onDelete,
+	)
+}
+
+func isAutoIncrement(ctype schema.ColumnType, isPk bool, pkStrategy entity.PkStrategy) bool {
+	if !isPk || pkStrategy != entity.Auto {
+		return false
+	}
+
+	switch ctype {
+	case schema.Int, schema.Int32, schema.Int64, schema.NullInt32, schema.NullInt64:
+		return true
+	}
+	return false
+}
+
+func (s *sqliteDriver) ColumnDefinition(ctype schema.ColumnType, isPk bool, pkStrategy entity.PkStrategy) schema.ColumnDefinition {
+	// autoincrement available only for INTEGER columns
+	if isAutoIncrement(ctype, isPk, pkStrategy) {
+		return schema.ColumnDefinition{Type: "INTEGER", Nullable: ctype == schema.NullInt32 || ctype == schema.NullInt64}
+	}
+
+	switch ctype {
+	case schema.UUID:
+		return schema.ColumnDefinition{Type: "TEXT", Nullable: true}
+	case schema.Bool, schema.NullBool:
+		return schema.ColumnDefinition{Type: "BOOLEAN", Nullable: ctype == schema.NullBool}
+	case schema.Int, schema.Int64, schema.NullInt64:
+		return schema.ColumnDefinition{Type: "BIGINT", Nullable: ctype == schema.NullInt64}
+	case schema.Int32, schema.NullInt32:
+		return schema.ColumnDefinition{Type: "INTEGER", Nullable: ctype == schema.NullInt32}
+	case schema.Float32:
+		return schema.ColumnDefinition{Type: "FLOAT"}
+	case schema.Float64, schema.NullFloat64:
+		return schema.ColumnDefinition{Type: "DOUBLE", Nullable: ctype == schema.NullFloat64}
+	case schema.String, schema.NullString:
+		return schema.ColumnDefinition{Type: "TEXT", Nullable: ctype == schema.NullString}
+	case schema.Time, schema.NullTime:
+		return schema.ColumnDefinition{Type: "datetime", Nullable: ctype == schema.NullTime}
+	}
+
+	return schema.ColumnDefinition{}
+}
+
+func (s *sqliteDriver) TableInfo(name string) (*schema.TableInfo, error) {
+	rows, err := s.db.Query("SELECT name, type, \"notnull\" FROM pragma_table_info($1)", name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns := make(map[string]schema.ColumnDefinition)
+	for rows.Next() {
+		var colName, colType string
+		var notNull bool
+		if err := rows.Scan(&colName, &colType, &notNull); err != nil {
+			return nil, err
+		}
+		columns[colName] = schema.ColumnDefinition{Type: colType, Nullable: !notNull}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(columns) == 0 {
+		return nil, nil
+	}
+
+	indexes, err := s.tableIndexes(name)
+	if err != nil {
+		return nil, err
+	}
+
+	foreignKeys, err := s.tableForeignKeys(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &schema.TableInfo{Columns: columns, Indexes: indexes, ForeignKeys: foreignKeys}, nil
+}
+
+func (s *sqliteDriver) tableForeignKeys(table string) ([]schema.ForeignKey, error) {
+	rows, err := s.db.Query(`SELECT id, "table", "from", "to" FROM pragma_foreign_key_list($1) ORDER BY id, seq`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var foreignKeys []schema.ForeignKey
+	lastId := -1
+	for rows.Next() {
+		var id int
+		var refTable, colName string
+		var refColName sql.NullString
+		if err := rows.Scan(&id, &refTable, &colName, &refColName); err != nil {
+			return nil, err
+		}
+
+		if id != lastId {
+			foreignKeys = append(foreignKeys, schema.ForeignKey{RefTable: refTable})
+			lastId = id
+		}
+		fk := &foreignKeys[len(foreignKeys)-1]
+		fk.Columns = append(fk.Columns, colName)
+		fk.RefColumns = append(fk.RefColumns, refColName.String)
+	}
+
+	return foreignKeys, rows.Err()
+}
+
+func (s *sqliteDriver) tableIndexes(table string) ([]entity.Index, error) {
+	// origin 'c' means that index created by CREATE INDEX statement, not by PRIMARY KEY or UNIQUE constraint
+	rows, err := s.db.Query(`
+SELECT il.name, il."unique", ii.name
+FROM pragma_index_list($1) il
+JOIN pragma_index_info(il.name) ii
+WHERE il.origin = 'c'
+ORDER BY il.name, ii.seqno
+`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var indexes []entity.Index
+	for rows.Next() {
+		var indexName, colName string
+		var unique bool
+		if err := rows.Scan(&indexName, &unique, &colName); err != nil {
+			return nil, err
+		}
+
+		if len(indexes) == 0 || indexes[len(indexes)-1].Name != indexName {
+			indexes = append(indexes, entity.Index{Name: indexName, Unique: unique})
+		}
+		indexes[len(indexes)-1].Columns = append(indexes[len(indexes)-1].Columns, colName)
+	}
+
+	return indexes, rows.Err()
+}
+
+// AddColumnSql - sqlite can't add NOT NULL column without default, so zero value used as default.
+func (s *sqliteDriver) AddColumnSql(table, column string, def schema.ColumnDefinition) string {
+	if def.Nullable {
+		return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s;\n", table, column, def.Type)
+	}
+
+	var zeroVal string
+	switch def.Type {
+	case "TEXT":
+		zeroVal = "''"
+	case "datetime":
+		zeroVal = "'1970-01-01 00:00:00'"
+	default:
+		zeroVal = "0"
+	}
+
+	return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s NOT NULL DEFAULT %s;\n", table, column, def.Type, zeroVal)
+}
+
+func (s *sqliteDriver) DropColumnSql(_, _ string) (string, error) {
+	return "", schema.ErrAlterUnsupported
+}
+
+func (s *sqliteDriver) AlterColumnSql(_, _ string, _ schema.ColumnDefinition) (string, error) {
+	return "", schema.ErrAlterUnsupported
+}
+
+func (s *sqliteDriver) DropIndexSql(_, name string) string {
+	return fmt.Sprintf("DROP INDEX IF EXISTS %s;\n", name)
+}
+
 func (s *sqliteDriver) CreateIndexSql(name string, unique bool, table string, columns ...string) string {
 	var uniqueDef string
 	if unique {
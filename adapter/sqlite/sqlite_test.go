@@ -1,9 +1,14 @@
 package sqlite
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"github.com/godzie44/d3/orm"
+	"github.com/godzie44/d3/orm/entity"
 	"github.com/godzie44/d3/orm/persistence"
 	"github.com/godzie44/d3/orm/query"
+	"github.com/godzie44/d3/orm/schema"
 	"github.com/godzie44/d3/tests/helpers"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
@@ -55,29 +60,29 @@ func TestPgxConnDriverTestSuite(t *testing.T) {
 }
 
 func (s *sqliteDriverTS) TestPgxDriverQuery() {
-	tx, err := s.driver.BeginTx()
+	tx, err := s.driver.BeginTx(context.Background(), orm.TxOptions{})
 	s.NoError(err)
 	defer tx.Commit() //nolint
 
-	data, err := s.driver.ExecuteQuery(query.New().Select("*").From("d3_test_table").Where("id", "=", "1"), tx)
+	data, err := s.driver.ExecuteQuery(context.Background(), query.New().Select("*").From("d3_test_table").Where("id", "=", "1"), tx)
 	s.NoError(err)
 
 	s.Len(data, 1)
 	s.Equal(data[0], map[string]interface{}{"id": int64(1), "data": "test 1"})
 
-	data, err = s.driver.ExecuteQuery(query.New().Select("*").From("d3_test_table"), tx)
+	data, err = s.driver.ExecuteQuery(context.Background(), query.New().Select("*").From("d3_test_table"), tx)
 	s.NoError(err)
 
 	s.Len(data, 3)
 }
 
 func (s *sqliteDriverTS) TestSqliteDriverTxInsert() {
-	tx, err := s.driver.BeginTx()
+	tx, err := s.driver.BeginTx(context.Background(), orm.TxOptions{})
 	s.NoError(err)
 
 	pusher := s.driver.MakePusher(tx)
 
-	err = pusher.Insert("d3_test_table", []string{"id", "data"}, []interface{}{4, "test 4"}, persistence.Undefined)
+	err = pusher.Insert(context.Background(), "d3_test_table", []string{"id", "data"}, []interface{}{4, "test 4"}, persistence.Undefined)
 	s.NoError(err)
 	s.NoError(tx.Commit())
 
@@ -85,12 +90,12 @@ func (s *sqliteDriverTS) TestSqliteDriverTxInsert() {
 }
 
 func (s *sqliteDriverTS) TestSqliteDriverTxUpdate() {
-	tx, err := s.driver.BeginTx()
+	tx, err := s.driver.BeginTx(context.Background(), orm.TxOptions{})
 	s.NoError(err)
 
 	pusher := s.driver.MakePusher(tx)
 
-	err = pusher.Update("d3_test_table", []string{"data"}, []interface{}{"test upd"}, map[string]interface{}{"id": 1})
+	_, err = pusher.Update(context.Background(), "d3_test_table", []string{"data"}, []interface{}{"test upd"}, map[string]interface{}{"id": 1})
 	s.NoError(err)
 	s.NoError(tx.Commit())
 
@@ -98,12 +103,12 @@ func (s *sqliteDriverTS) TestSqliteDriverTxUpdate() {
 }
 
 func (s *sqliteDriverTS) TestSqliteDriverTxDelete() {
-	tx, err := s.driver.BeginTx()
+	tx, err := s.driver.BeginTx(context.Background(), orm.TxOptions{})
 	s.NoError(err)
 
 	pusher := s.driver.MakePusher(tx)
 
-	err = pusher.Remove("d3_test_table", map[string]interface{}{"id": 1})
+	err = pusher.Remove(context.Background(), "d3_test_table", map[string]interface{}{"id": 1})
 	s.NoError(err)
 	s.NoError(tx.Commit())
 
@@ -111,14 +116,124 @@ func (s *sqliteDriverTS) TestSqliteDriverTxDelete() {
 }
 
 func (s *sqliteDriverTS) TestSqliteDriverTxRollback() {
-	tx, err := s.driver.BeginTx()
+	tx, err := s.driver.BeginTx(context.Background(), orm.TxOptions{})
 	s.NoError(err)
 
 	pusher := s.driver.MakePusher(tx)
 
-	err = pusher.Insert("d3_test_table", []string{"id", "data"}, []interface{}{4, "test 4"}, persistence.Undefined)
+	err = pusher.Insert(context.Background(), "d3_test_table", []string{"id", "data"}, []interface{}{4, "test 4"}, persistence.Undefined)
 	s.NoError(err)
 	s.NoError(tx.Rollback())
 
 	s.tester.SeeThree("select * from d3_test_table")
 }
+
+func (s *sqliteDriverTS) TestSqliteDriverTxSavepoint() {
+	tx, err := s.driver.BeginTx(context.Background(), orm.TxOptions{})
+	s.NoError(err)
+
+	pusher := s.driver.MakePusher(tx)
+
+	err = pusher.Insert(context.Background(), "d3_test_table", []string{"id", "data"}, []interface{}{4, "test 4"}, persistence.Undefined)
+	s.NoError(err)
+
+	s.NoError(tx.Savepoint("sp1"))
+	err = pusher.Insert(context.Background(), "d3_test_table", []string{"id", "data"}, []interface{}{5, "test 5"}, persistence.Undefined)
+	s.NoError(err)
+	s.NoError(tx.RollbackToSavepoint("sp1"))
+	s.NoError(tx.ReleaseSavepoint("sp1"))
+	s.NoError(tx.Commit())
+
+	s.tester.SeeFour("select * from d3_test_table")
+}
+
+func (s *sqliteDriverTS) TestForeignKeys() {
+	ctx := context.Background()
+	conn, err := s.driver.UnwrapConn().(*sql.DB).Conn(ctx)
+	s.NoError(err)
+	defer conn.Close()
+
+	_, err = conn.ExecContext(ctx, "PRAGMA foreign_keys = ON")
+	s.NoError(err)
+
+	fkSql, err := s.driver.CreateForeignKeySql("d3_test_child", schema.ForeignKey{})
+	s.Empty(fkSql)
+	s.True(errors.Is(err, schema.ErrAlterUnsupported))
+
+	ddl := s.driver.CreateTableSql("d3_test_parent", map[string]schema.ColumnType{"id": schema.Int32}, []string{"id"}, entity.Manual, nil) +
+		s.driver.CreateTableSql(
+			"d3_test_child",
+			map[string]schema.ColumnType{"id": schema.Int32, "parent_id": schema.NullInt32, "owner_id": schema.Int32},
+			[]string{"id"},
+			entity.Manual,
+			[]schema.ForeignKey{
+				{Columns: []string{"parent_id"}, RefTable: "d3_test_parent", RefColumns: []string{"id"}, OnDelete: entity.Nullable},
+				{Columns: []string{"owner_id"}, RefTable: "d3_test_parent", RefColumns: []string{"id"}, OnDelete: entity.Cascade},
+			},
+		)
+	_, err = conn.ExecContext(ctx, ddl)
+	s.NoError(err)
+	defer conn.ExecContext(ctx, "DROP TABLE d3_test_child; DROP TABLE d3_test_parent;") //nolint
+
+	info, err := s.driver.TableInfo("d3_test_child")
+	s.NoError(err)
+	s.ElementsMatch([]schema.ForeignKey{
+		{Columns: []string{"parent_id"}, RefTable: "d3_test_parent", RefColumns: []string{"id"}},
+		{Columns: []string{"owner_id"}, RefTable: "d3_test_parent", RefColumns: []string{"id"}},
+	}, info.ForeignKeys)
+
+	_, err = conn.ExecContext(ctx, `
+INSERT INTO d3_test_parent(id) VALUES (1), (2);
+INSERT INTO d3_test_child(id, parent_id, owner_id) VALUES (1, 1, 2), (2, 2, 1);
+`)
+	s.NoError(err)
+
+	_, err = conn.ExecContext(ctx, "DELETE FROM d3_test_parent WHERE id = 1")
+	s.NoError(err)
+
+	s.tester.SeeOne("select * from d3_test_child where id = 1 and parent_id is null and owner_id = 2")
+	s.tester.SeeOne("select * from d3_test_child")
+}
+
+func (s *sqliteDriverTS) TestSqliteDriverCanceledContext() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := s.driver.BeginTx(ctx, orm.TxOptions{})
+	s.True(errors.Is(err, context.Canceled))
+
+	tx, err := s.driver.BeginTx(context.Background(), orm.TxOptions{})
+	s.NoError(err)
+	defer tx.Rollback() //nolint
+
+	_, err = s.driver.ExecuteQuery(ctx, query.New().Select("*").From("d3_test_table"), tx)
+	s.True(errors.Is(err, context.Canceled))
+
+	err = s.driver.MakePusher(tx).Insert(ctx, "d3_test_table", []string{"id", "data"}, []interface{}{4, "test 4"}, persistence.Undefined)
+	s.True(errors.Is(err, context.Canceled))
+}
+
+func (s *sqliteDriverTS) TestSqliteDriverConstraintError() {
+	tx, err := s.driver.BeginTx(context.Background(), orm.TxOptions{})
+	s.NoError(err)
+	defer tx.Rollback() //nolint
+
+	pusher := s.driver.MakePusher(tx)
+
+	err = pusher.Insert(context.Background(), "d3_test_table", []string{"id", "data"}, []interface{}{4, nil}, persistence.Undefined)
+	s.True(errors.Is(err, orm.ErrNotNullViolation))
+
+	var constraintErr *orm.ConstraintError
+	s.True(errors.As(err, &constraintErr))
+	s.Equal("d3_test_table", constraintErr.Table)
+	s.Equal("d3_test_table.data", constraintErr.Constraint)
+}
+
+func (s *sqliteDriverTS) TestSqliteDriverRejectLock() {
+	tx, err := s.driver.BeginTx(context.Background(), orm.TxOptions{})
+	s.NoError(err)
+	defer tx.Commit() //nolint
+
+	_, err = s.driver.ExecuteQuery(context.Background(), query.New().Select("*").From("d3_test_table").ForUpdate(), tx)
+	s.True(errors.Is(err, ErrLockUnsupported))
+}
@@ -8,47 +8,91 @@ import (
 )
 
 func QueryToSql(q *query.Query) (string, []interface{}, error) {
+	return QueryToSqlWithPlaceholders(q, squirrel.Dollar)
+}
+
+// QueryToSqlWithPlaceholders - same as QueryToSql but use custom placeholder format, for example squirrel.Question for mysql.
+func QueryToSqlWithPlaceholders(q *query.Query, placeholders squirrel.PlaceholderFormat) (string, []interface{}, error) {
 	sqQuery, err := toSquirrel(q)
 	if err != nil {
 		return "", nil, err
 	}
 
-	return sqQuery.PlaceholderFormat(squirrel.Dollar).ToSql()
+	return sqQuery.PlaceholderFormat(placeholders).ToSql()
+}
+
+// HasLock - true if query has row locking clause (FOR UPDATE, FOR SHARE), for drivers which not support it.
+func HasLock(q *query.Query) bool {
+	var hasLock bool
+	query.Visit(q, func(pred interface{}) {
+		if _, ok := pred.(*query.Lock); ok {
+			hasLock = true
+		}
+	})
+	return hasLock
 }
 
 func toSquirrel(q *query.Query) (*squirrel.SelectBuilder, error) {
 	sb := squirrel.SelectBuilder{}
+	var from query.From
+	var visitErr error
 
 	query.Visit(q, func(pred interface{}) {
+		if visitErr != nil {
+			return
+		}
+
 		switch p := pred.(type) {
 		case query.From:
+			from = p
 			sb = sb.From(string(p))
+		case *query.FromSubquery:
+			from = query.From(p.Alias)
+			sqQuery, err := toSquirrel(p.Q)
+			if err != nil {
+				visitErr = fmt.Errorf("from subquery: %w", err)
+				return
+			}
+			sb = sb.FromSelect(sqQuery.PlaceholderFormat(squirrel.Question), p.Alias)
+		case query.Distinct:
+			sb = sb.Distinct()
 		case query.Columns:
+			columns := make([]string, len(p))
 			for i := range p {
-				if p[i] == "*" {
-					continue
+				columns[i] = p[i]
+				if p[i] != "*" {
+					columns[i] = fmt.Sprintf("%s as \"%s\"", p[i], p[i])
 				}
-				p[i] = fmt.Sprintf("%s as \"%s\"", p[i], p[i])
 			}
-			sb = sb.Columns(p...)
+			sb = sb.Columns(columns...)
+		case *query.AliasedColumn:
+			sb = sb.Column(fmt.Sprintf("%s as \"%s\"", p.Expr, p.Alias))
+		case *query.SubqueryColumn:
+			sb = sb.Column(subqueryColumn{p})
 		case *query.Join:
+			if p.Subquery != nil {
+				sb = sb.JoinClause(subqueryJoin{p})
+				break
+			}
 			switch p.Type {
 			case query.JoinLeft:
-				sb = sb.LeftJoin(p.Join + " ON " + p.On)
+				sb = sb.LeftJoin(p.Join+" ON "+p.On, p.Params...)
 			case query.JoinInner:
-				sb = sb.Join(p.Join + " ON " + p.On)
+				sb = sb.Join(p.Join+" ON "+p.On, p.Params...)
 			case query.JoinRight:
-				sb = sb.RightJoin(p.Join + " ON " + p.On)
+				sb = sb.RightJoin(p.Join+" ON "+p.On, p.Params...)
 			}
 		case query.Order:
 			sb = sb.OrderBy(p...)
 		case *query.Union:
 			sqQuery, err := toSquirrel(p.Q)
 			if err != nil {
+				visitErr = fmt.Errorf("union: %w", err)
 				return
 			}
 			sql, args, err := sqQuery.PlaceholderFormat(squirrel.Question).ToSql()
 			if err != nil {
+				visitErr = fmt.Errorf("union: %w", err)
 				return
 			}
 
@@ -61,13 +105,48 @@ func toSquirrel(q *query.Query) (*squirrel.SelectBuilder, error) {
 			sb = sb.Limit(uint64(p))
 		case query.Offset:
 			sb = sb.Offset(uint64(p))
+		case *query.Lock:
+			sb = sb.Suffix(lockClause(p, lockTarget(from)))
 		}
 	})
+	if visitErr != nil {
+		return nil, visitErr
+	}
 	sb = sb.Where(visitWherePart(q))
 
 	return &sb, nil
 }
 
+// lockClause - render locking clause, lock restricted to target table (if not empty), so rows of outer joined tables
+// are not locked (postgres not allow lock nullable side of outer join).
+func lockClause(lock *query.Lock, target string) string {
+	clause := "FOR UPDATE"
+	if lock.Mode == query.LockForShare {
+		clause = "FOR SHARE"
+	}
+
+	if target != "" {
+		clause += " OF " + target
+	}
+
+	switch {
+	case lock.SkipLocked:
+		clause += " SKIP LOCKED"
+	case lock.NoWait:
+		clause += " NOWAIT"
+	}
+	return clause
+}
+
+// lockTarget - name of main table used in locking clause, alias if table aliased ("book b" or "book as b").
+func lockTarget(from query.From) string {
+	parts := strings.Fields(string(from))
+	if len(parts) == 0 {
+		return ""
+	}
+	return parts[len(parts)-1]
+}
+
 func visitWherePart(q *query.Query) squirrel.Sqlizer {
 	var whereExpr squirrel.Sqlizer
 
@@ -103,13 +182,13 @@ func handleWhere(parent squirrel.Sqlizer, w query.Where, wType string) squirrel.
 }
 
 func handleNestedWhere(parent squirrel.Sqlizer, w query.NestedWhere, wType string) squirrel.Sqlizer {
+	q := &query.Query{}
+	w.Supply(q)
+	nestedExpr := visitWherePart(q)
+
 	if parent == nil {
-		return parent
+		return nestedExpr
 	} else {
-		q := &query.Query{}
-		w.Supply(q)
-		nestedExpr := visitWherePart(q)
-
 		switch wType {
 		case "and":
 			return squirrel.And{parent, nestedExpr}
@@ -121,14 +200,96 @@ func handleNestedWhere(parent squirrel.Sqlizer, w query.NestedWhere, wType strin
 }
 
 func createWhereExpr(where query.Where) squirrel.Sqlizer {
-	switch len(where.Params) {
-	case 0:
-		return squirrel.Expr(strings.Join([]string{where.Field, where.Op}, " "))
-	case 1:
-		return squirrel.Expr(strings.Join([]string{where.Field, where.Op, "?"}, " "), where.Params[0])
-	default:
-		paramsPlaceholder := strings.Repeat("?,", len(where.Params))
-		paramsPlaceholder = paramsPlaceholder[:len(paramsPlaceholder)-1]
-		return squirrel.Expr(strings.Join([]string{where.Field, where.Op, "(" + paramsPlaceholder + ")"}, " "), where.Params...)
+	return whereExpr{where}
+}
+
+// whereExpr - sqlizer of WHERE expression, params rendered as placeholders, except column references
+// and subqueries which rendered in place.
+type whereExpr struct {
+	where query.Where
+}
+
+func (w whereExpr) ToSql() (string, []interface{}, error) {
+	var args []interface{}
+	params := make([]string, len(w.where.Params))
+	for i, param := range w.where.Params {
+		switch p := param.(type) {
+		case query.Ref:
+			params[i] = string(p)
+		case *query.Query:
+			sql, subArgs, err := subquery{p}.ToSql()
+			if err != nil {
+				return "", nil, err
+			}
+			params[i] = sql
+			args = append(args, subArgs...)
+		default:
+			params[i] = "?"
+			args = append(args, param)
+		}
+	}
+
+	parts := make([]string, 0, 3)
+	if w.where.Field != "" {
+		parts = append(parts, w.where.Field)
+	}
+	parts = append(parts, w.where.Op)
+
+	switch {
+	case len(params) == 1:
+		parts = append(parts, params[0])
+	case len(params) > 1:
+		parts = append(parts, "("+strings.Join(params, ",")+")")
+	}
+
+	return strings.Join(parts, " "), args, nil
+}
+
+// subquery - sqlizer of nested query. Subquery rendered with question placeholders, so all placeholders
+// of query numbered once in order of appearance, when outer query placeholder format applied.
+type subquery struct {
+	q *query.Query
+}
+
+func (s subquery) ToSql() (string, []interface{}, error) {
+	sqQuery, err := toSquirrel(s.q)
+	if err != nil {
+		return "", nil, err
+	}
+
+	sql, args, err := sqQuery.PlaceholderFormat(squirrel.Question).ToSql()
+	if err != nil {
+		return "", nil, fmt.Errorf("subquery: %w", err)
+	}
+	return "(" + sql + ")", args, nil
+}
+
+type subqueryColumn struct {
+	column *query.SubqueryColumn
+}
+
+func (s subqueryColumn) ToSql() (string, []interface{}, error) {
+	sql, args, err := subquery{s.column.Q}.ToSql()
+	if err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("%s as \"%s\"", sql, s.column.Alias), args, nil
+}
+
+type subqueryJoin struct {
+	join *query.Join
+}
+
+var joinKeywords = map[query.JoinType]string{
+	query.JoinLeft:  "LEFT JOIN",
+	query.JoinInner: "JOIN",
+	query.JoinRight: "RIGHT JOIN",
+}
+
+func (s subqueryJoin) ToSql() (string, []interface{}, error) {
+	sql, args, err := subquery{s.join.Subquery}.ToSql()
+	if err != nil {
+		return "", nil, err
 	}
+	return fmt.Sprintf("%s %s AS %s ON %s", joinKeywords[s.join.Type], sql, s.join.Join, s.join.On), append(args, s.join.Params...), nil
 }
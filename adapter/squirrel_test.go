@@ -1,6 +1,7 @@
 package adapter
 
 import (
+	"github.com/Masterminds/squirrel"
 	"github.com/godzie44/d3/orm/entity"
 	"github.com/godzie44/d3/orm/query"
 	"github.com/stretchr/testify/assert"
@@ -75,3 +76,150 @@ func TestQueryToSquirrelSql(t *testing.T) {
 		assert.Equal(t, tCase.args, args)
 	}
 }
+
+func TestQueryToSqlWithQuestionPlaceholders(t *testing.T) {
+	sql, args, err := QueryToSqlWithPlaceholders(query.New().ForEntity(metaStub).Where("id", "IN", 1, 2), squirrel.Question)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT test_table.id as \"test_table.id\" FROM test_table WHERE id IN (?,?)", sql)
+	assert.Equal(t, []interface{}{1, 2}, args)
+}
+
+func TestQueryToSqlWithSoftDelete(t *testing.T) {
+	deletedAt := &entity.FieldInfo{Name: "deleted_at", DbAlias: "deleted_at", FullDbAlias: "test_table.deleted_at"}
+	softDeleteMetaStub := &entity.MetaInfo{
+		Fields: map[string]*entity.FieldInfo{
+			"id":         metaStub.Fields["id"],
+			"deleted_at": deletedAt,
+		},
+		SoftDelete: deletedAt,
+		TableName:  "test_table",
+	}
+
+	sql, args, err := QueryToSql(query.New().ForEntity(softDeleteMetaStub).Where("id", "=", 1).OrWhere("id", "=", 3))
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT test_table.deleted_at as \"test_table.deleted_at\", test_table.id as \"test_table.id\" FROM test_table WHERE ((id = $1 OR id = $2) AND test_table.deleted_at IS NULL)", sql)
+	assert.Equal(t, []interface{}{1, 3}, args)
+
+	sql, _, err = QueryToSql(query.New().ForEntity(softDeleteMetaStub).Where("id", "=", 1).WithDeleted())
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT test_table.deleted_at as \"test_table.deleted_at\", test_table.id as \"test_table.id\" FROM test_table WHERE id = $1", sql)
+}
+
+func TestQueryToSqlWithFilter(t *testing.T) {
+	filteredMetaStub := &entity.MetaInfo{
+		Fields: map[string]*entity.FieldInfo{
+			"id":       metaStub.Fields["id"],
+			"TenantId": {Name: "TenantId", DbAlias: "tenant_id", FullDbAlias: "test_table.tenant_id"},
+		},
+		TableName: "test_table",
+	}
+
+	sql, args, err := QueryToSql(query.New().ForEntity(filteredMetaStub).Where("id", "=", 1).Filter("TenantId", 5).Filter("Unknown", 6))
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT test_table.tenant_id as \"test_table.tenant_id\", test_table.id as \"test_table.id\" FROM test_table WHERE (id = $1 AND test_table.tenant_id = $2)", sql)
+	assert.Equal(t, []interface{}{1, 5}, args)
+}
+
+func TestQueryToSqlWithLock(t *testing.T) {
+	sql, _, err := QueryToSql(query.New().ForEntity(metaStub).Where("id", "=", 1).Limit(1).ForUpdate().SkipLocked())
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT test_table.id as \"test_table.id\" FROM test_table WHERE id = $1 LIMIT 1 FOR UPDATE OF test_table SKIP LOCKED", sql)
+
+	sql, _, err = QueryToSql(query.New().ForEntity(metaStub).ForShare().NoWait())
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT test_table.id as \"test_table.id\" FROM test_table FOR SHARE OF test_table NOWAIT", sql)
+
+	sql, _, err = QueryToSql(query.New().ForEntity(metaStub).NoWait())
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT test_table.id as \"test_table.id\" FROM test_table FOR UPDATE OF test_table NOWAIT", sql)
+
+	sql, _, err = QueryToSql(query.New().Select("b.id").From("book b").Join(query.JoinLeft, "shop s", "s.id = b.shop_id").ForUpdate())
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT b.id as \"b.id\" FROM book b LEFT JOIN shop s ON s.id = b.shop_id FOR UPDATE OF b", sql)
+
+	sql, _, err = QueryToSqlWithPlaceholders(query.New().ForEntity(metaStub).Where("id", "=", 1).ForShare().SkipLocked(), squirrel.Question)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT test_table.id as \"test_table.id\" FROM test_table WHERE id = ? FOR SHARE OF test_table SKIP LOCKED", sql)
+
+	assert.True(t, HasLock(query.New().ForEntity(metaStub).ForUpdate()))
+	assert.False(t, HasLock(query.New().ForEntity(metaStub)))
+}
+
+func TestScalarQueryToSql(t *testing.T) {
+	q := query.New().ForEntity(metaStub).Where("id", ">", 1).OrderBy("id DESC").Limit(10).Offset(5).ForUpdate()
+
+	sql, args, err := QueryToSql(q.ScalarQuery("COUNT(*)"))
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT COUNT(*) as \"COUNT(*)\" FROM test_table WHERE id > $1", sql)
+	assert.Equal(t, []interface{}{1}, args)
+
+	sql, _, err = QueryToSql(q)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT test_table.id as \"test_table.id\" FROM test_table WHERE id > $1 ORDER BY id DESC LIMIT 10 OFFSET 5 FOR UPDATE OF test_table", sql)
+
+	q = query.New().ForEntity(metaStub).Where("id", ">", 1).AndWhere("id", "<", 10).AndWhere("id", "<>", 5)
+	scalar := q.ScalarQuery("COUNT(*)").AndWhere("id", "<>", 6)
+	q.AndWhere("id", "<>", 7)
+
+	sql, args, err = QueryToSql(scalar)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT COUNT(*) as \"COUNT(*)\" FROM test_table WHERE (((id > $1 AND id < $2) AND id <> $3) AND id <> $4)", sql)
+	assert.Equal(t, []interface{}{1, 10, 5, 6}, args)
+}
+
+func TestQueryToSqlFromSubquery(t *testing.T) {
+	sub := query.New().Select("b.shop_id").From("book b").Join(query.JoinLeft, "author a", "a.book_id = b.id").Where("a.name", "=", "Bob").Distinct()
+
+	sql, args, err := QueryToSql(query.New().Select("COUNT(*)").FromSubquery(sub, "roots").Where("roots.shop_id", ">", 1))
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT COUNT(*) as \"COUNT(*)\" FROM (SELECT DISTINCT b.shop_id as \"b.shop_id\" FROM book b LEFT JOIN author a ON a.book_id = b.id WHERE a.name = $1) AS roots WHERE roots.shop_id > $2", sql)
+	assert.Equal(t, []interface{}{"Bob", 1}, args)
+}
+
+func TestQueryToSqlNestedQueryError(t *testing.T) {
+	withoutColumns := query.New().From("other")
+
+	_, _, err := QueryToSql(query.New().ForEntity(metaStub).Union(withoutColumns))
+	assert.Error(t, err)
+
+	_, _, err = QueryToSql(query.New().Select("COUNT(*)").FromSubquery(query.New().ForEntity(metaStub).Union(withoutColumns), "roots"))
+	assert.Error(t, err)
+}
+
+func TestQueryToSqlWithSubqueries(t *testing.T) {
+	makeQuery := func() *query.Query {
+		return query.New().ForEntity(metaStub).
+			SelectSubquery(query.New().Select("COUNT(*)").From("other o").Where("o.test_id", "=", query.Ref("test_table.id")).AndWhere("o.kind", "=", "a"), "cnt").
+			JoinSubquery(query.JoinLeft, query.New().Select("test_id").From("other").Where("kind", "=", "b"), "sub", "sub.test_id=test_table.id").
+			Where("id", ">", 1).
+			AndWhere("id", "IN", query.New().Select("test_id").From("other").Where("kind", "=", "c")).
+			Exists(query.New().Select("1").From("other o").Where("o.test_id", "=", query.Ref("test_table.id"))).
+			NotExists(query.New().Select("1").From("other o").Where("o.test_id", "=", query.Ref("test_table.id")).AndWhere("o.kind", "=", "d"))
+	}
+
+	sql, args, err := QueryToSql(makeQuery())
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT test_table.id as \"test_table.id\", (SELECT COUNT(*) as \"COUNT(*)\" FROM other o WHERE (o.test_id = test_table.id AND o.kind = $1)) as \"cnt\" "+
+		"FROM test_table LEFT JOIN (SELECT test_id as \"test_id\" FROM other WHERE kind = $2) AS sub ON sub.test_id=test_table.id "+
+		"WHERE (((id > $3 AND id IN (SELECT test_id as \"test_id\" FROM other WHERE kind = $4)) AND EXISTS (SELECT 1 as \"1\" FROM other o WHERE o.test_id = test_table.id)) "+
+		"AND NOT EXISTS (SELECT 1 as \"1\" FROM other o WHERE (o.test_id = test_table.id AND o.kind = $5)))", sql)
+	assert.Equal(t, []interface{}{"a", "b", 1, "c", "d"}, args)
+
+	sql, args, err = QueryToSqlWithPlaceholders(makeQuery(), squirrel.Question)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT test_table.id as \"test_table.id\", (SELECT COUNT(*) as \"COUNT(*)\" FROM other o WHERE (o.test_id = test_table.id AND o.kind = ?)) as \"cnt\" "+
+		"FROM test_table LEFT JOIN (SELECT test_id as \"test_id\" FROM other WHERE kind = ?) AS sub ON sub.test_id=test_table.id "+
+		"WHERE (((id > ? AND id IN (SELECT test_id as \"test_id\" FROM other WHERE kind = ?)) AND EXISTS (SELECT 1 as \"1\" FROM other o WHERE o.test_id = test_table.id)) "+
+		"AND NOT EXISTS (SELECT 1 as \"1\" FROM other o WHERE (o.test_id = test_table.id AND o.kind = ?)))", sql)
+	assert.Equal(t, []interface{}{"a", "b", 1, "c", "d"}, args)
+}
+
+func TestQueryToSqlRenderQueryTwice(t *testing.T) {
+	q := query.New().ForEntity(metaStub).Where("id", "IN", query.New().Select("test_id").From("other"))
+
+	sql1, _, err := QueryToSql(q)
+	assert.NoError(t, err)
+	sql2, _, err := QueryToSql(q)
+	assert.NoError(t, err)
+	assert.Equal(t, sql1, sql2)
+}
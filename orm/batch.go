@@ -0,0 +1,225 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	d3entity "github.com/godzie44/d3/orm/entity"
+	"github.com/godzie44/d3/orm/query"
+	"strings"
+)
+
+// batchLoader - registry of not initialized lazy relations with batch size (see entity.Relation BatchSize).
+// When one of lazy relations initialized, other registered relations of the same entity field (up to batch size)
+// fetched in the same query.
+type batchLoader struct {
+	pending map[d3entity.Relation][]*batchEntry
+}
+
+// pendingBatchesLimit - count of batches of relation kept in registry. Oldest entries dropped from registry
+// when limit exceeded (dropped relation still loaded when initialized, but in batch with other registered relations),
+// so registry not grows if lazy relations never initialized.
+const pendingBatchesLimit = 10
+
+type batchEntry struct {
+	// id - owner join column values for one to one relation, owner primary key values for one to many and many to many.
+	id     []interface{}
+	loaded bool
+	result *d3entity.Collection
+}
+
+func newBatchLoader() *batchLoader {
+	return &batchLoader{pending: make(map[d3entity.Relation][]*batchEntry)}
+}
+
+func (b *batchLoader) register(rel d3entity.Relation, id []interface{}) *batchEntry {
+	entry := &batchEntry{id: id}
+
+	entries := append(b.pending[rel], entry)
+	if limit := rel.BatchSize() * pendingBatchesLimit; len(entries) > limit {
+		entries = entries[len(entries)-limit:]
+	}
+	b.pending[rel] = entries
+
+	return entry
+}
+
+// reset - remove all entries from registry.
+func (b *batchLoader) reset() {
+	b.pending = make(map[d3entity.Relation][]*batchEntry)
+}
+
+// next - return entry and other not loaded entries of relation, no more than relation batch size.
+func (b *batchLoader) next(rel d3entity.Relation, entry *batchEntry) []*batchEntry {
+	batch := []*batchEntry{entry}
+	for _, e := range b.pending[rel] {
+		if len(batch) >= rel.BatchSize() {
+			break
+		}
+		if e != entry && !e.loaded {
+			batch = append(batch, e)
+		}
+	}
+	return batch
+}
+
+// release - remove loaded entries of relation from registry.
+func (b *batchLoader) release(rel d3entity.Relation) {
+	var pending []*batchEntry
+	for _, e := range b.pending[rel] {
+		if !e.loaded {
+			pending = append(pending, e)
+		}
+	}
+
+	if len(pending) == 0 {
+		delete(b.pending, rel)
+		return
+	}
+	b.pending[rel] = pending
+}
+
+// batchKey - key of owner built from id values. Values formatted, so key not depends on driver type of column
+// (owner primary key and join column may have different integer types).
+func batchKey(values []interface{}) string {
+	return fmt.Sprintf("%v", values)
+}
+
+func (s *session) makeBatchExtractor(ctx context.Context, id []interface{}, rel d3entity.Relation, relatedMeta *d3entity.MetaInfo) extractor {
+	entry := s.batches.register(rel, id)
+
+	return func() *d3entity.Collection {
+		if !entry.loaded {
+			if err := s.loadBatch(ctx, rel, relatedMeta, entry); err != nil {
+				return nil
+			}
+		}
+
+		return entry.result
+	}
+}
+
+// loadBatch - fetch related entities of entry and other pending entries of relation in one query,
+// then distribute fetched entities between entries. Entities already in identity map are not fetched (if possible)
+// and not hydrated again, existing instances are used instead.
+func (s *session) loadBatch(ctx context.Context, rel d3entity.Relation, relatedMeta *d3entity.MetaInfo, entry *batchEntry) error {
+	batch := s.resolveFromIdentityMap(rel, relatedMeta, s.batches.next(rel, entry))
+	if len(batch) == 0 {
+		s.batches.release(rel)
+		return nil
+	}
+
+	ids := make([][]interface{}, len(batch))
+	for i, e := range batch {
+		ids[i] = e.id
+	}
+
+	q, ownerColumns := makeBatchQuery(rel, relatedMeta, ids)
+	s.uow.filters.apply(q)
+
+	data, err := s.Execute(ctx, q)
+	if err != nil {
+		return err
+	}
+
+	pkColumns := relatedMeta.Pk.FullDbAliases()
+	entityByPk := make(map[interface{}]interface{}, len(data))
+	var newData []map[string]interface{}
+	for _, row := range data {
+		pk := keyFromRow(row, pkColumns)
+		if e, exists := s.uow.identityMap.find(relatedMeta.EntityName, pk); exists {
+			entityByPk[pk] = e
+		} else {
+			newData = append(newData, row)
+		}
+	}
+
+	if len(newData) != 0 {
+		entities, err := s.hydrate(ctx, newData, query.Preprocessor.MakeFetchPlan(q), relatedMeta)
+		if err != nil {
+			return err
+		}
+
+		// hydrator keeps order of first appearance of entity in rows
+		var hydrated int
+		for _, row := range newData {
+			pk := keyFromRow(row, pkColumns)
+			if _, exists := entityByPk[pk]; !exists {
+				entityByPk[pk] = entities.Get(hydrated)
+				hydrated++
+			}
+		}
+	}
+
+	relatedByOwner := make(map[string][]interface{})
+	for _, row := range data {
+		ownerId, _ := columnValues(row, ownerColumns)
+		key := batchKey(ownerId)
+		relatedByOwner[key] = append(relatedByOwner[key], entityByPk[keyFromRow(row, pkColumns)])
+	}
+
+	for _, e := range batch {
+		e.result = d3entity.NewCollection(relatedByOwner[batchKey(e.id)]...)
+		e.loaded = true
+	}
+	s.batches.release(rel)
+
+	return nil
+}
+
+// resolveFromIdentityMap - load entries of one to one relation (entry id is a related entity primary key)
+// which related entity is in identity map and matches session filters, return entries that must be fetched.
+func (s *session) resolveFromIdentityMap(rel d3entity.Relation, relatedMeta *d3entity.MetaInfo, batch []*batchEntry) []*batchEntry {
+	if _, isOneToOne := rel.(*d3entity.OneToOne); !isOneToOne {
+		return batch
+	}
+
+	filters := s.uow.filters.fields()
+	var unresolved []*batchEntry
+	for _, e := range batch {
+		pk := e.id[0]
+		if len(e.id) > 1 {
+			pk = d3entity.NewCompositeKey(e.id...)
+		}
+
+		related, exists := s.uow.identityMap.find(relatedMeta.EntityName, pk)
+		if !exists || !matchFilters(related, relatedMeta, filters) || isSoftDeleted(related, relatedMeta) {
+			unresolved = append(unresolved, e)
+			continue
+		}
+
+		e.result = d3entity.NewCollection(related)
+		e.loaded = true
+	}
+	return unresolved
+}
+
+// makeBatchQuery - create query which fetch related entities of all owners with ids,
+// second returned value is columns of query result which identify owner of related entity.
+func makeBatchQuery(rel d3entity.Relation, relatedMeta *d3entity.MetaInfo, ids [][]interface{}) (*query.Query, []string) {
+	q := query.New().ForEntity(relatedMeta)
+
+	var ownerColumns []string
+	switch rel := rel.(type) {
+	case *d3entity.OneToOne:
+		ownerColumns = relatedMeta.Pk.FullDbAliases()
+	case *d3entity.OneToMany:
+		for _, joinCol := range rel.JoinColumns() {
+			ownerColumns = append(ownerColumns, relatedMeta.FullColumnAlias(joinCol))
+		}
+		q.Select(ownerColumns...)
+	case *d3entity.ManyToMany:
+		pkColumns := relatedMeta.Pk.FullDbAliases()
+
+		var joinOn []string
+		for i, refCol := range rel.ReferenceColumns() {
+			joinOn = append(joinOn, fmt.Sprintf("%s.%s=%s", rel.JoinTable, refCol, pkColumns[i]))
+		}
+		for _, joinCol := range rel.JoinColumns() {
+			ownerColumns = append(ownerColumns, fmt.Sprintf("%s.%s", rel.JoinTable, joinCol))
+		}
+
+		q.Select(ownerColumns...).Join(query.JoinInner, rel.JoinTable, strings.Join(joinOn, " AND "))
+	}
+
+	return q.AndWhereIn(ownerColumns, ids), ownerColumns
+}
@@ -0,0 +1,37 @@
+package orm
+
+import (
+	"github.com/godzie44/d3/orm/entity"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type batchTestEntity struct {
+	ID    int                `d3:"pk:auto"`
+	Items *entity.Collection `d3:"one_to_many:<target_entity:github.com/godzie44/d3/orm/batchTestItem,join_on:owner_id>,type:lazy,batch:2"`
+}
+
+func (b *batchTestEntity) D3Token() entity.MetaToken {
+	return entity.MetaToken{}
+}
+
+func makeBatchTestRelation(t *testing.T) entity.Relation {
+	meta, err := entity.NewMeta((*batchTestEntity)(nil))
+	assert.NoError(t, err)
+	return meta.Relations["Items"]
+}
+
+func TestBatchLoaderLimitPendingEntries(t *testing.T) {
+	rel := makeBatchTestRelation(t)
+	loader := newBatchLoader()
+
+	var entries []*batchEntry
+	for i := 0; i < rel.BatchSize()*pendingBatchesLimit+3; i++ {
+		entries = append(entries, loader.register(rel, []interface{}{i}))
+	}
+
+	assert.Len(t, loader.pending[rel], rel.BatchSize()*pendingBatchesLimit)
+	assert.Equal(t, entries[3], loader.pending[rel][0])
+
+	assert.Equal(t, []*batchEntry{entries[0], entries[3]}, loader.next(rel, entries[0]))
+}
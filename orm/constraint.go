@@ -0,0 +1,83 @@
+package orm
+
+import (
+	"errors"
+	"fmt"
+	"github.com/godzie44/d3/orm/persistence"
+	"strings"
+)
+
+var (
+	ErrUniqueViolation     = errors.New("unique constraint violation")
+	ErrForeignKeyViolation = errors.New("foreign key constraint violation")
+	ErrNotNullViolation    = errors.New("not null constraint violation")
+	ErrCheckViolation      = errors.New("check constraint violation")
+)
+
+// ConstraintError - database constraint violation, drivers translate native errors into it.
+// Use errors.Is with ErrUniqueViolation, ErrForeignKeyViolation, ErrNotNullViolation or ErrCheckViolation to check the kind of violation
+// and errors.As to get table, constraint and entity.
+type ConstraintError struct {
+	// Kind - one of ErrUniqueViolation, ErrForeignKeyViolation, ErrNotNullViolation, ErrCheckViolation.
+	Kind error
+	// Table - table where constraint violated, may be empty if driver can't provide it.
+	Table string
+	// Constraint - name of violated constraint, may be empty if driver can't provide it.
+	Constraint string
+	// Entity - name of entity mapped on table, empty if table is not mapped on entity (for example many to many join table).
+	Entity string
+	// Err - native driver error.
+	Err error
+}
+
+// NewConstraintError - create ConstraintError of kind for native driver error.
+func NewConstraintError(kind error, table, constraint string, err error) *ConstraintError {
+	return &ConstraintError{Kind: kind, Table: table, Constraint: constraint, Err: err}
+}
+
+func (c *ConstraintError) Error() string {
+	details := make([]string, 0, 3)
+	if c.Entity != "" {
+		details = append(details, "entity "+c.Entity)
+	}
+	if c.Table != "" {
+		details = append(details, "table "+c.Table)
+	}
+	if c.Constraint != "" {
+		details = append(details, "constraint "+c.Constraint)
+	}
+
+	if len(details) == 0 {
+		return fmt.Sprintf("%s: %v", c.Kind, c.Err)
+	}
+	return fmt.Sprintf("%s (%s): %v", c.Kind, strings.Join(details, ", "), c.Err)
+}
+
+func (c *ConstraintError) Unwrap() error {
+	return c.Err
+}
+
+func (c *ConstraintError) Is(target error) bool {
+	return c.Kind == target
+}
+
+// fillConstraintEntity - if err is ConstraintError set name of entity mapped on violated table.
+func (uow *unitOfWork) fillConstraintEntity(err error, graph *persistence.PersistGraph) {
+	var cErr *ConstraintError
+	if !errors.As(err, &cErr) || cErr.Entity != "" || cErr.Table == "" {
+		return
+	}
+
+	boxes := append(graph.InsertedEntities(), graph.UpdatedEntities()...)
+	boxes = append(boxes, graph.DeletedEntities()...)
+	for _, box := range boxes {
+		if box.Meta.TableName == cErr.Table {
+			cErr.Entity = string(box.Meta.EntityName)
+			return
+		}
+	}
+
+	if uow.outboxMeta != nil && uow.outboxMeta.TableName == cErr.Table {
+		cErr.Entity = string(uow.outboxMeta.EntityName)
+	}
+}
@@ -0,0 +1,80 @@
+package orm
+
+import (
+	"context"
+	"github.com/godzie44/d3/orm/entity"
+	"github.com/godzie44/d3/orm/persistence"
+)
+
+// EventRecorder - aggregate that records domain events. Recorded events released on flush
+// and passed to DomainEventHandler after transaction committed, events of rolled back transaction are dropped.
+type EventRecorder interface {
+	// ReleaseEvents - return recorded events and clear them.
+	ReleaseEvents() []interface{}
+}
+
+// DomainEventHandler - listener (see Orm.AddListener) that handle domain events released by aggregates.
+type DomainEventHandler interface {
+	HandleDomainEvents(ctx context.Context, events []interface{})
+}
+
+// recordedEvent - domain event with aggregate which records it.
+type recordedEvent struct {
+	event     interface{}
+	aggregate *entity.Box
+}
+
+// releaseEvents - release domain events of all new, dirty and removed entities, including entities
+// that will be inserted or deleted by cascade.
+func (uow *unitOfWork) releaseEvents(graph *persistence.PersistGraph) []recordedEvent {
+	var boxes []*entity.Box
+	for _, newEntities := range uow.newEntities {
+		boxes = append(boxes, newEntities...)
+	}
+	for _, dirtyEntities := range uow.dirtyEntities {
+		for _, el := range dirtyEntities {
+			boxes = append(boxes, el.box)
+		}
+	}
+	for _, deletedEntities := range uow.deletedEntities {
+		for _, box := range deletedEntities {
+			boxes = append(boxes, box)
+		}
+	}
+	boxes = append(boxes, graph.InsertedEntities()...)
+	boxes = append(boxes, graph.DeletedEntities()...)
+
+	var events []recordedEvent
+	released := make(map[interface{}]bool, len(boxes))
+	for _, box := range boxes {
+		if released[box.Entity] {
+			continue
+		}
+		released[box.Entity] = true
+
+		if recorder, ok := box.Entity.(EventRecorder); ok {
+			for _, event := range recorder.ReleaseEvents() {
+				events = append(events, recordedEvent{event: event, aggregate: box})
+			}
+		}
+	}
+
+	return events
+}
+
+func (d *eventDispatcher) domainEvents(ctx context.Context, recorded []recordedEvent) {
+	if len(recorded) == 0 {
+		return
+	}
+
+	events := make([]interface{}, 0, len(recorded))
+	for _, r := range recorded {
+		events = append(events, r.event)
+	}
+
+	for _, l := range d.listeners {
+		if h, ok := l.(DomainEventHandler); ok {
+			h.HandleDomainEvents(ctx, events)
+		}
+	}
+}
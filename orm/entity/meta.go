@@ -1,11 +1,13 @@
 package entity
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
 	"reflect"
 	"regexp"
 	"strings"
+	"time"
 )
 
 type PkStrategy int
@@ -56,6 +58,13 @@ type MetaInfo struct {
 	Relations map[string]Relation
 	Fields    map[string]*FieldInfo
 	Pk        *pk
+	// Version - field used for optimistic locking, nil if entity not versioned.
+	Version *FieldInfo
+	// SoftDelete - field contains time of entity deletion, nil if entity deleted from database physically.
+	SoftDelete *FieldInfo
+	// CreatedAt, UpdatedAt - fields contains time of entity creation and last update, set automatically on flush.
+	CreatedAt *FieldInfo
+	UpdatedAt *FieldInfo
 
 	RelatedMeta map[Name]*MetaInfo
 	Tools       InternalTools
@@ -113,6 +122,26 @@ func NewMeta(e interface{}) (*MetaInfo, error) {
 
 		tag := parseTag(fieldReflection.Tag)
 
+		if tag.hasProperty("-") {
+			continue
+		}
+
+		if tag.hasProperty("embedded") {
+			if tag.hasProperty("pk") {
+				return nil, fmt.Errorf("entity %s: embedded field %s can't be a part of pk", entityName, fieldReflection.Name)
+			}
+
+			embeddedFields, err := extractEmbeddedFields(fieldReflection, tag, meta)
+			if err != nil {
+				return nil, fmt.Errorf("entity %s: %w", entityName, err)
+			}
+
+			for _, f := range embeddedFields {
+				meta.Fields[f.Name] = f
+			}
+			continue
+		}
+
 		field := &FieldInfo{
 			Name:           fieldReflection.Name,
 			AssociatedType: fieldReflection.Type,
@@ -139,7 +168,43 @@ func NewMeta(e interface{}) (*MetaInfo, error) {
 		}
 
 		if tag.hasProperty("pk") {
-			meta.Pk = &pk{field, extractPkStrategy(tag)}
+			if meta.Pk == nil {
+				meta.Pk = &pk{Strategy: extractPkStrategy(tag)}
+			}
+
+			if meta.Pk.Strategy != extractPkStrategy(tag) {
+				return nil, fmt.Errorf("entity %s: all pk fields must have the same strategy", entityName)
+			}
+
+			meta.Pk.Fields = append(meta.Pk.Fields, field)
+		}
+
+		if tag.hasProperty("version") {
+			if err := validateVersionField(meta, field, tag); err != nil {
+				return nil, fmt.Errorf("entity %s: %w", entityName, err)
+			}
+			meta.Version = field
+		}
+
+		if tag.hasProperty("soft_delete") {
+			if err := validateSoftDeleteField(meta, field, tag); err != nil {
+				return nil, fmt.Errorf("entity %s: %w", entityName, err)
+			}
+			meta.SoftDelete = field
+		}
+
+		if tag.hasProperty("created_at") {
+			if err := validateTimestampField(meta.CreatedAt, field, tag); err != nil {
+				return nil, fmt.Errorf("entity %s: %w", entityName, err)
+			}
+			meta.CreatedAt = field
+		}
+
+		if tag.hasProperty("updated_at") {
+			if err := validateTimestampField(meta.UpdatedAt, field, tag); err != nil {
+				return nil, fmt.Errorf("entity %s: %w", entityName, err)
+			}
+			meta.UpdatedAt = field
 		}
 	}
 
@@ -173,6 +238,131 @@ func extractDbFieldAlias(tag *parsedTag, fieldName string) string {
 	return prop.val
 }
 
+// extractEmbeddedFields - return fields of embedded value object. Name of field is a path from the entity root
+// (like Price.Amount), column name is a column of value object field with embedding prefix (like price_amount).
+func extractEmbeddedFields(embedded reflect.StructField, tag *parsedTag, meta *MetaInfo) ([]*FieldInfo, error) {
+	if embedded.Type.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("embedded field %s must be a struct", embedded.Name)
+	}
+
+	prefix := extractEmbeddedPrefix(tag, embedded.Name)
+
+	var result []*FieldInfo
+	for i := 0; i < embedded.Type.NumField(); i++ {
+		fieldReflection := embedded.Type.Field(i)
+		fieldTag := parseTag(fieldReflection.Tag)
+		if fieldTag.hasProperty("-") {
+			continue
+		}
+
+		if fieldTag.hasProperty("one_to_one") || fieldTag.hasProperty("one_to_many") || fieldTag.hasProperty("many_to_many") || fieldTag.hasProperty("pk") {
+			return nil, fmt.Errorf("embedded field %s: relations and pk not allowed in value object", embedded.Name)
+		}
+
+		if fieldTag.hasProperty("embedded") {
+			nestedFields, err := extractEmbeddedFields(fieldReflection, fieldTag, meta)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, f := range nestedFields {
+				f.Name = embedded.Name + "." + f.Name
+				f.DbAlias = prefix + f.DbAlias
+				f.FullDbAlias = meta.FullColumnAlias(f.DbAlias)
+			}
+
+			result = append(result, nestedFields...)
+			continue
+		}
+
+		dbAlias := prefix + extractDbFieldAlias(fieldTag, fieldReflection.Name)
+		result = append(result, &FieldInfo{
+			Name:           embedded.Name + "." + fieldReflection.Name,
+			AssociatedType: fieldReflection.Type,
+			DbAlias:        dbAlias,
+			FullDbAlias:    meta.FullColumnAlias(dbAlias),
+		})
+	}
+
+	return result, nil
+}
+
+// extractEmbeddedPrefix - return prefix for columns of embedded value object,
+// if prefix not defined in tag then snake cased field name used.
+// Example:
+// `d3:"embedded:<prefix:cost_>"` - columns will be prefixed with cost_
+// `d3:"embedded:<prefix:>"` - columns without prefix
+// `d3:"embedded:<>"` - columns will be prefixed with field name
+func extractEmbeddedPrefix(tag *parsedTag, fieldName string) string {
+	prop, _ := tag.getProperty("embedded")
+	if prefix, exists := prop.subProperty["prefix"]; exists {
+		return prefix.val
+	}
+
+	return toSnakeCase(fieldName) + "_"
+}
+
+// validateVersionField - check that field can be used as optimistic lock version.
+func validateVersionField(meta *MetaInfo, field *FieldInfo, tag *parsedTag) error {
+	if meta.Version != nil {
+		return fmt.Errorf("only one version field allowed, found %s and %s", meta.Version.Name, field.Name)
+	}
+
+	if tag.hasProperty("pk") || field.FullDbAlias == "" {
+		return fmt.Errorf("version field %s can't be a pk or relation", field.Name)
+	}
+
+	switch field.AssociatedType.Kind() {
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return nil
+	default:
+		return fmt.Errorf("version field %s must be int, int32 or int64", field.Name)
+	}
+}
+
+// validateSoftDeleteField - check that field can be used as soft delete marker.
+func validateSoftDeleteField(meta *MetaInfo, field *FieldInfo, tag *parsedTag) error {
+	if meta.SoftDelete != nil {
+		return fmt.Errorf("only one soft delete field allowed, found %s and %s", meta.SoftDelete.Name, field.Name)
+	}
+
+	if tag.hasProperty("pk") || field.FullDbAlias == "" {
+		return fmt.Errorf("soft delete field %s can't be a pk or relation", field.Name)
+	}
+
+	if field.AssociatedType != reflect.TypeOf(sql.NullTime{}) {
+		return fmt.Errorf("soft delete field %s must be sql.NullTime", field.Name)
+	}
+	return nil
+}
+
+// validateTimestampField - check that field can be used as created_at or updated_at timestamp,
+// current - already found field with the same purpose.
+func validateTimestampField(current *FieldInfo, field *FieldInfo, tag *parsedTag) error {
+	if current != nil {
+		return fmt.Errorf("only one timestamp field of each kind allowed, found %s and %s", current.Name, field.Name)
+	}
+
+	if tag.hasProperty("pk") || field.FullDbAlias == "" {
+		return fmt.Errorf("timestamp field %s can't be a pk or relation", field.Name)
+	}
+
+	if field.AssociatedType != reflect.TypeOf(time.Time{}) {
+		return fmt.Errorf("timestamp field %s must be time.Time", field.Name)
+	}
+	return nil
+}
+
+// IsTransient - return true if struct field marked as not persisted (`d3:"-"`).
+func IsTransient(field reflect.StructField) bool {
+	return parseTag(field.Tag).hasProperty("-")
+}
+
+// IsEmbedded - return true if struct field is a value object embedded into entity.
+func IsEmbedded(field reflect.StructField) bool {
+	return parseTag(field.Tag).hasProperty("embedded")
+}
+
 func (m *MetaInfo) Deps() []Name {
 	dependencies := make([]Name, 0, len(m.Relations))
 
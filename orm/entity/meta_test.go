@@ -5,6 +5,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestNewSimpleMeta(t *testing.T) {
@@ -46,7 +47,7 @@ func TestNewMetaWithRelations(t *testing.T) {
 		AssociatedType: reflect.TypeOf(sql.NullInt32{}),
 		DbAlias:        "id",
 		FullDbAlias:    "shop.id",
-	}, *meta.Pk.Field)
+	}, *meta.Pk.Fields[0])
 	assert.Equal(t, OneToOne{
 		baseRelation: baseRelation{
 			relType:        Lazy,
@@ -113,3 +114,242 @@ func TestShortNamesInRelationSwitchTooFullNames(t *testing.T) {
 	assert.Equal(t, "github.com/godzie44/d3/orm/entity/book", string(meta.Relations["Books"].RelatedWith()))
 	assert.Equal(t, "github.com/godzie44/d3/orm/entity/shopProfile", string(meta.Relations["Profile"].RelatedWith()))
 }
+
+type customer struct {
+	TenantID int32       `d3:"pk:manual"`
+	ID       int32       `d3:"pk:manual"`
+	Orders   *Collection `d3:"one_to_many:<target_entity:order,join_on:tenant_id; customer_id>,type:lazy"`
+}
+
+func (c *customer) D3Token() MetaToken {
+	return MetaToken{
+		Tools: InternalTools{
+			ExtractField: func(s interface{}, name string) (interface{}, error) {
+				switch name {
+				case "TenantID":
+					return s.(*customer).TenantID, nil
+				case "ID":
+					return s.(*customer).ID, nil
+				default:
+					return nil, nil
+				}
+			},
+		},
+	}
+}
+
+func TestNewMetaWithCompositePk(t *testing.T) {
+	meta, err := NewMeta((*customer)(nil))
+	assert.NoError(t, err)
+
+	assert.True(t, meta.Pk.IsComposite())
+	assert.Equal(t, Manual, meta.Pk.Strategy)
+	assert.Equal(t, []string{"tenant_id", "id"}, meta.Pk.DbAliases())
+	assert.Equal(t, []string{"customer.tenant_id", "customer.id"}, meta.Pk.FullDbAliases())
+	assert.Equal(t, []string{"tenant_id", "customer_id"}, meta.Relations["Orders"].(*OneToMany).JoinColumns())
+
+	pk, err := meta.ExtractPkValue(&customer{TenantID: 1, ID: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, NewCompositeKey(1, 2), pk)
+	assert.NotEqual(t, NewCompositeKey(2, 1), pk)
+}
+
+func TestCompositeKeyNormalizeIntegers(t *testing.T) {
+	expected := NewCompositeKey(int64(1), int64(2), "a")
+
+	assert.Equal(t, expected, NewCompositeKey(int8(1), int16(2), "a"))
+	assert.Equal(t, expected, NewCompositeKey(uint(1), uint32(2), "a"))
+	assert.Equal(t, expected, NewCompositeKey(1, sql.NullInt64{Int64: 2, Valid: true}, "a"))
+	assert.NotEqual(t, expected, NewCompositeKey(int64(1), int64(2), "b"))
+}
+
+type invalidCompositePk struct {
+	TenantID int32         `d3:"pk:manual"`
+	ID       sql.NullInt32 `d3:"pk:auto"`
+}
+
+func (i *invalidCompositePk) D3Token() MetaToken {
+	return MetaToken{}
+}
+
+func TestNewMetaWithMixedPkStrategies(t *testing.T) {
+	_, err := NewMeta((*invalidCompositePk)(nil))
+	assert.Error(t, err)
+}
+
+type money struct {
+	Amount   int64
+	Currency string `d3:"column:cur"`
+}
+
+type address struct {
+	City     string
+	Location struct {
+		Lat, Lon float64
+	} `d3:"embedded:<prefix:>"`
+}
+
+type product struct {
+	ID      sql.NullInt32 `d3:"pk:auto"`
+	Price   money         `d3:"embedded:<>"`
+	Cost    money         `d3:"embedded:<prefix:c_>"`
+	Address address       `d3:"embedded:<prefix:addr_>"`
+}
+
+func (p *product) D3Token() MetaToken {
+	return MetaToken{}
+}
+
+func TestNewMetaWithEmbedded(t *testing.T) {
+	meta, err := NewMeta((*product)(nil))
+	assert.NoError(t, err)
+
+	assert.NotContains(t, meta.Fields, "Price")
+	assert.Equal(t, FieldInfo{
+		Name:           "Price.Amount",
+		AssociatedType: reflect.TypeOf(int64(0)),
+		DbAlias:        "price_amount",
+		FullDbAlias:    "product.price_amount",
+	}, *meta.Fields["Price.Amount"])
+	assert.Equal(t, "price_cur", meta.Fields["Price.Currency"].DbAlias)
+	assert.Equal(t, "c_amount", meta.Fields["Cost.Amount"].DbAlias)
+	assert.Equal(t, "c_cur", meta.Fields["Cost.Currency"].DbAlias)
+	assert.Equal(t, "addr_city", meta.Fields["Address.City"].DbAlias)
+	assert.Equal(t, "addr_lat", meta.Fields["Address.Location.Lat"].DbAlias)
+	assert.Equal(t, "product.addr_lon", meta.Fields["Address.Location.Lon"].FullDbAlias)
+}
+
+type versioned struct {
+	ID      int32 `d3:"pk:manual"`
+	Version int64 `d3:"version"`
+}
+
+func (v *versioned) D3Token() MetaToken {
+	return MetaToken{}
+}
+
+type invalidVersioned struct {
+	ID      int32  `d3:"pk:manual"`
+	Version string `d3:"version"`
+}
+
+func (i *invalidVersioned) D3Token() MetaToken {
+	return MetaToken{}
+}
+
+func TestNewMetaWithVersion(t *testing.T) {
+	meta, err := NewMeta((*versioned)(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, meta.Fields["Version"], meta.Version)
+	assert.Equal(t, "version", meta.Version.DbAlias)
+
+	meta, err = NewMeta((*shop)(nil))
+	assert.NoError(t, err)
+	assert.Nil(t, meta.Version)
+
+	_, err = NewMeta((*invalidVersioned)(nil))
+	assert.Error(t, err)
+}
+
+type aggregate struct {
+	ID     int32         `d3:"pk:manual"`
+	events []interface{} `d3:"-"` //nolint
+}
+
+func (a *aggregate) D3Token() MetaToken {
+	return MetaToken{}
+}
+
+func TestNewMetaWithTransientField(t *testing.T) {
+	meta, err := NewMeta((*aggregate)(nil))
+	assert.NoError(t, err)
+
+	assert.Len(t, meta.Fields, 1)
+	assert.NotContains(t, meta.Fields, "events")
+}
+
+type softDeletable struct {
+	ID        int32        `d3:"pk:manual"`
+	DeletedAt sql.NullTime `d3:"soft_delete"`
+}
+
+func (s *softDeletable) D3Token() MetaToken {
+	return MetaToken{}
+}
+
+type invalidSoftDeletable struct {
+	ID        int32 `d3:"pk:manual"`
+	DeletedAt int64 `d3:"soft_delete"`
+}
+
+func (i *invalidSoftDeletable) D3Token() MetaToken {
+	return MetaToken{}
+}
+
+func TestNewMetaWithSoftDelete(t *testing.T) {
+	meta, err := NewMeta((*softDeletable)(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, meta.Fields["DeletedAt"], meta.SoftDelete)
+	assert.Equal(t, "deleted_at", meta.SoftDelete.DbAlias)
+
+	meta, err = NewMeta((*shop)(nil))
+	assert.NoError(t, err)
+	assert.Nil(t, meta.SoftDelete)
+
+	_, err = NewMeta((*invalidSoftDeletable)(nil))
+	assert.Error(t, err)
+}
+
+type timestamped struct {
+	ID        int32     `d3:"pk:manual"`
+	CreatedAt time.Time `d3:"created_at"`
+	UpdatedAt time.Time `d3:"updated_at"`
+}
+
+func (t *timestamped) D3Token() MetaToken {
+	return MetaToken{}
+}
+
+type invalidTimestamped struct {
+	ID        int32        `d3:"pk:manual"`
+	CreatedAt sql.NullTime `d3:"created_at"`
+}
+
+func (i *invalidTimestamped) D3Token() MetaToken {
+	return MetaToken{}
+}
+
+func TestNewMetaWithTimestamps(t *testing.T) {
+	meta, err := NewMeta((*timestamped)(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, meta.Fields["CreatedAt"], meta.CreatedAt)
+	assert.Equal(t, meta.Fields["UpdatedAt"], meta.UpdatedAt)
+
+	meta, err = NewMeta((*shop)(nil))
+	assert.NoError(t, err)
+	assert.Nil(t, meta.CreatedAt)
+	assert.Nil(t, meta.UpdatedAt)
+
+	_, err = NewMeta((*invalidTimestamped)(nil))
+	assert.Error(t, err)
+}
+
+type batchedShop struct {
+	ID      int32       `d3:"pk:auto"`
+	Books   *Collection `d3:"one_to_many:<target_entity:book,join_on:shop_id>,type:lazy,batch:50"`
+	Sellers *Collection `d3:"one_to_many:<target_entity:seller,join_on:shop_id>,type:lazy,batch:many"`
+	Profile *Cell       `d3:"one_to_one:<target_entity:shopProfile,join_on:profile_id>,type:lazy"`
+}
+
+func (b *batchedShop) D3Token() MetaToken {
+	return MetaToken{}
+}
+
+func TestNewMetaWithBatchSize(t *testing.T) {
+	meta, err := NewMeta((*batchedShop)(nil))
+	assert.NoError(t, err)
+
+	assert.Equal(t, 50, meta.Relations["Books"].BatchSize())
+	assert.Equal(t, 0, meta.Relations["Sellers"].BatchSize())
+	assert.Equal(t, 0, meta.Relations["Profile"].BatchSize())
+}
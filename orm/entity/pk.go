@@ -2,16 +2,75 @@ package entity
 
 import (
 	"database/sql/driver"
+	"fmt"
+	"math"
 	"reflect"
+	"strings"
 )
 
 type pk struct {
-	Field    *FieldInfo
+	Fields   []*FieldInfo
 	Strategy PkStrategy
 }
 
-func (p *pk) FullDbAlias() string {
-	return p.Field.FullDbAlias
+// IsComposite - return true if primary key consists of more than one field.
+func (p *pk) IsComposite() bool {
+	return len(p.Fields) > 1
+}
+
+// DbAliases - return db column names of primary key fields.
+func (p *pk) DbAliases() []string {
+	result := make([]string, len(p.Fields))
+	for i, f := range p.Fields {
+		result[i] = f.DbAlias
+	}
+	return result
+}
+
+// FullDbAliases - return db column names (prefixed with table name) of primary key fields.
+func (p *pk) FullDbAliases() []string {
+	result := make([]string, len(p.Fields))
+	for i, f := range p.Fields {
+		result[i] = f.FullDbAlias
+	}
+	return result
+}
+
+// CompositeKey - value of primary key that consists of several fields.
+// It's comparable, so it can be used as map key.
+type CompositeKey string
+
+// NewCompositeKey - create composite key from values of primary key fields.
+func NewCompositeKey(values ...interface{}) CompositeKey {
+	parts := make([]string, len(values))
+	for i, val := range values {
+		if valuer, ok := val.(driver.Valuer); ok {
+			val, _ = valuer.Value()
+		}
+		val = NormalizeInt(val)
+
+		parts[i] = fmt.Sprintf("%T:%v", val, val)
+	}
+
+	return CompositeKey(strings.Join(parts, "|"))
+}
+
+// NormalizeInt - convert value of any integer type to int64 (uint64 if value overflows int64), so keys built
+// from entity fields and from fetched rows are equal regardless of integer width. Other values returned as is.
+func NormalizeInt(val interface{}) interface{} {
+	rv := reflect.ValueOf(val)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u := rv.Uint()
+		if u > math.MaxInt64 {
+			return u
+		}
+		return int64(u)
+	default:
+		return val
+	}
 }
 
 type KeyTpl struct {
@@ -32,31 +91,55 @@ func (k *KeyTpl) Key() []interface{} {
 	return res
 }
 
+// ExtractPkValue - return primary key value of entity. For composite primary keys CompositeKey returned.
+// If primary key not initialized yet nil returned.
 func (m *MetaInfo) ExtractPkValue(entity interface{}) (interface{}, error) {
-	val, err := m.Tools.ExtractField(entity, m.Pk.Field.Name)
+	values, err := m.ExtractPkValues(entity)
 	if err != nil {
 		return nil, err
 	}
 
-	if val, ok := val.(driver.Valuer); ok {
-		if pk, _ := val.Value(); pk == nil {
-			return nil, nil
+	for i := range values {
+		if val, ok := values[i].(driver.Valuer); ok {
+			if pk, _ := val.Value(); pk == nil {
+				return nil, nil
+			}
+		}
+	}
+
+	if !m.Pk.IsComposite() {
+		return values[0], nil
+	}
+
+	return NewCompositeKey(values...), nil
+}
+
+// ExtractPkValues - return values of all primary key fields, in order of fields declaration.
+func (m *MetaInfo) ExtractPkValues(entity interface{}) ([]interface{}, error) {
+	values := make([]interface{}, 0, len(m.Pk.Fields))
+	for _, f := range m.Pk.Fields {
+		val, err := m.Tools.ExtractField(entity, f.Name)
+		if err != nil {
+			return nil, err
 		}
+		values = append(values, val)
 	}
 
-	return val, nil
+	return values, nil
 }
 
 func (m *MetaInfo) CreateKeyTpl() *KeyTpl {
 	tpl := &KeyTpl{
-		values:     make([]reflect.Value, 0, 1),
-		projection: make([]interface{}, 0, 1),
+		values:     make([]reflect.Value, 0, len(m.Pk.Fields)),
+		projection: make([]interface{}, 0, len(m.Pk.Fields)),
 	}
 
-	value := reflect.New(reflect.PtrTo(m.Pk.Field.AssociatedType).Elem())
+	for _, f := range m.Pk.Fields {
+		value := reflect.New(reflect.PtrTo(f.AssociatedType).Elem())
 
-	tpl.values = append(tpl.values, value.Elem())
-	tpl.projection = append(tpl.projection, value.Elem().Addr().Interface())
+		tpl.values = append(tpl.values, value.Elem())
+		tpl.projection = append(tpl.projection, value.Elem().Addr().Interface())
+	}
 
 	return tpl
 }
@@ -31,6 +31,7 @@ func (r *MetaRegistry) makeDepInstaller(meta *MetaInfo, depName Name) promise {
 
 func (r *MetaRegistry) Add(entities ...interface{}) error {
 	var dependencyInstallers []promise
+	var added []*MetaInfo
 
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
@@ -40,6 +41,7 @@ func (r *MetaRegistry) Add(entities ...interface{}) error {
 			return err
 		}
 		r.metaMap[meta.EntityName] = meta
+		added = append(added, meta)
 
 		for _, entityName := range meta.Deps() {
 			dependencyInstallers = append(dependencyInstallers, r.makeDepInstaller(meta, entityName))
@@ -52,6 +54,12 @@ func (r *MetaRegistry) Add(entities ...interface{}) error {
 		}
 	}
 
+	for _, meta := range added {
+		if err := checkRelationColumns(meta); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
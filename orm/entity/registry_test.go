@@ -71,3 +71,29 @@ func TestRegistryGetMetaParallel(t *testing.T) {
 	assert.NotEmpty(t, meta1)
 	assert.NotEmpty(t, meta2)
 }
+
+type cmpOwner struct {
+	TenantID int32       `d3:"pk:manual"`
+	ID       int32       `d3:"pk:manual"`
+	Items    *Collection `d3:"one_to_many:<target_entity:cmpItem,join_on:owner_id,delete:nullable>,type:lazy"`
+}
+
+func (c *cmpOwner) D3Token() MetaToken {
+	return MetaToken{}
+}
+
+type cmpItem struct {
+	ID int32 `d3:"pk:manual"`
+}
+
+func (c *cmpItem) D3Token() MetaToken {
+	return MetaToken{}
+}
+
+func TestRegistryAddCheckRelationColumns(t *testing.T) {
+	registry := NewMetaRegistry()
+
+	err := registry.Add((*cmpOwner)(nil), (*cmpItem)(nil))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "relation Items")
+}
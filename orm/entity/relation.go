@@ -2,6 +2,9 @@ package entity
 
 import (
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 )
 
 type DeleteStrategy int
@@ -44,10 +47,62 @@ func relationTypeFromAlias(alias string) RelationType {
 	}
 }
 
+// columnsSeparator - separator of column names in join_on and reference_on relation properties.
+// Several columns using when related entity has a composite primary key, for example: join_on:order_id;order_tenant_id
+const columnsSeparator = ";"
+
+func splitColumns(columns string) []string {
+	if columns == "" {
+		return nil
+	}
+
+	result := strings.Split(columns, columnsSeparator)
+	for i := range result {
+		result[i] = strings.TrimSpace(result[i])
+	}
+	return result
+}
+
+// checkRelationColumns - check that count of join_on and reference_on columns of every relation of entity
+// equals to count of primary key fields of referenced entity, cause columns matched with primary key fields by position.
+func checkRelationColumns(meta *MetaInfo) error {
+	for name, rel := range meta.Relations {
+		var err error
+		switch r := rel.(type) {
+		case *OneToOne:
+			related := meta.RelatedMeta[r.RelatedWith()]
+			err = checkColumnsCount(r.JoinColumns(), related)
+			if refColumns := r.ReferenceColumns(); err == nil && len(refColumns) != 0 && len(refColumns) != len(r.JoinColumns()) {
+				err = fmt.Errorf("expected %d reference columns, got %d", len(r.JoinColumns()), len(refColumns))
+			}
+		case *OneToMany:
+			err = checkColumnsCount(r.JoinColumns(), meta)
+		case *ManyToMany:
+			err = checkColumnsCount(r.JoinColumns(), meta)
+			if err == nil {
+				err = checkColumnsCount(r.ReferenceColumns(), meta.RelatedMeta[r.RelatedWith()])
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("%s: relation %s: %w", meta.EntityName, name, err)
+		}
+	}
+	return nil
+}
+
+func checkColumnsCount(columns []string, referenced *MetaInfo) error {
+	if len(columns) != len(referenced.Pk.Fields) {
+		return fmt.Errorf("%s primary key has %d fields, got %d columns", referenced.EntityName, len(referenced.Pk.Fields), len(columns))
+	}
+	return nil
+}
+
 type Relation interface {
 	Type() RelationType
 	DeleteStrategy() DeleteStrategy
 	RelatedWith() Name
+	// BatchSize - count of lazy relations (of different owners) fetched in one query, 0 means no batching.
+	BatchSize() int
 
 	Field() *FieldInfo
 
@@ -59,9 +114,24 @@ type baseRelation struct {
 	relType        RelationType
 	deleteStrategy DeleteStrategy
 	targetEntity   Name
+	batchSize      int
 	field          *FieldInfo
 }
 
+// batchSizeFromTag - parse batch tag property, for example: batch:50. Invalid value means no batching.
+func batchSizeFromTag(tag *parsedTag) int {
+	prop, exists := tag.getProperty("batch")
+	if !exists {
+		return 0
+	}
+
+	size, err := strconv.Atoi(prop.val)
+	if err != nil || size < 0 {
+		return 0
+	}
+	return size
+}
+
 func (b *baseRelation) Type() RelationType {
 	return b.relType
 }
@@ -74,6 +144,10 @@ func (b *baseRelation) RelatedWith() Name {
 	return b.targetEntity
 }
 
+func (b *baseRelation) BatchSize() int {
+	return b.batchSize
+}
+
 func (b *baseRelation) Field() *FieldInfo {
 	return b.field
 }
@@ -107,11 +181,17 @@ func (o *OneToMany) fillFromTag(tag *parsedTag, parent *MetaInfo) {
 		relType:        relationTypeFromAlias(relType.val),
 		targetEntity:   nameFromTag(prop.getSubPropVal("target_entity"), parent.EntityName),
 		deleteStrategy: deleteStrategyFromAlias(prop.getSubPropVal("delete")),
+		batchSize:      batchSizeFromTag(tag),
 	}
 	o.JoinColumn = prop.getSubPropVal("join_on")
 	o.ReferenceColumn = prop.getSubPropVal("reference_on")
 }
 
+// JoinColumns - return columns in related entity table that reference owner primary key.
+func (o *OneToMany) JoinColumns() []string {
+	return splitColumns(o.JoinColumn)
+}
+
 func (o *OneToMany) ExtractCollection(ownerBox *Box) (*Collection, error) {
 	val, err := ownerBox.Meta.Tools.ExtractField(ownerBox.Entity, o.Field().Name)
 	if err != nil {
@@ -148,11 +228,22 @@ func (o *OneToOne) fillFromTag(tag *parsedTag, parent *MetaInfo) {
 		relType:        relationTypeFromAlias(relType.val),
 		targetEntity:   nameFromTag(prop.getSubPropVal("target_entity"), parent.EntityName),
 		deleteStrategy: deleteStrategyFromAlias(prop.getSubPropVal("delete")),
+		batchSize:      batchSizeFromTag(tag),
 	}
 	o.JoinColumn = prop.getSubPropVal("join_on")
 	o.ReferenceColumn = prop.getSubPropVal("reference_on")
 }
 
+// JoinColumns - return columns in owner table that reference related entity.
+func (o *OneToOne) JoinColumns() []string {
+	return splitColumns(o.JoinColumn)
+}
+
+// ReferenceColumns - return columns of related entity referenced by join columns.
+func (o *OneToOne) ReferenceColumns() []string {
+	return splitColumns(o.ReferenceColumn)
+}
+
 var nilCell *Cell
 
 func (o *OneToOne) Extract(ownerBox *Box) (*Cell, error) {
@@ -193,12 +284,23 @@ func (m *ManyToMany) fillFromTag(tag *parsedTag, parent *MetaInfo) {
 		relType:        relationTypeFromAlias(relType.val),
 		targetEntity:   nameFromTag(prop.getSubPropVal("target_entity"), parent.EntityName),
 		deleteStrategy: deleteStrategyFromAlias(prop.getSubPropVal("delete")),
+		batchSize:      batchSizeFromTag(tag),
 	}
 	m.JoinColumn = prop.getSubPropVal("join_on")
 	m.ReferenceColumn = prop.getSubPropVal("reference_on")
 	m.JoinTable = prop.getSubPropVal("join_table")
 }
 
+// JoinColumns - return columns in join table that reference owner primary key.
+func (m *ManyToMany) JoinColumns() []string {
+	return splitColumns(m.JoinColumn)
+}
+
+// ReferenceColumns - return columns in join table that reference related entity primary key.
+func (m *ManyToMany) ReferenceColumns() []string {
+	return splitColumns(m.ReferenceColumn)
+}
+
 var nilCollection *Collection
 
 func (m *ManyToMany) ExtractCollection(ownerBox *Box) (*Collection, error) {
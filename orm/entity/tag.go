@@ -70,23 +70,41 @@ func extractKVFromTag(tag string) map[string]string {
 
 	var i int
 	for tag != "" {
-		for i < len(tag) && tag[i] != ':' {
+		for i < len(tag) && tag[i] != ':' && tag[i] != ',' {
 			i++
 		}
-		if i >= len(tag) {
-			break
+		if i >= len(tag) || tag[i] == ',' {
+			// property without value, like `d3:"version"`
+			if name := strings.Trim(tag[:i], " ,"); name != "" {
+				result[name] = ""
+			}
+			if i >= len(tag) {
+				break
+			}
+			tag = tag[i+1:]
+			i = 0
+			continue
 		}
 
 		name := strings.Trim(tag[:i], " :,")
 		tag = tag[i+1:]
 		i = 0
 
-		for i < len(tag) && tag[i] != '<' {
+		for i < len(tag) && tag[i] == ' ' {
 			i++
 		}
-		if i >= len(tag) {
+		if i >= len(tag) || tag[i] != '<' {
+			// simple value, like `type:lazy`
+			for i < len(tag) && tag[i] != ',' {
+				i++
+			}
 			result[name] = strings.Trim(tag[:i], "\" ,")
-			break
+			if i >= len(tag) {
+				break
+			}
+			tag = tag[i+1:]
+			i = 0
+			continue
 		}
 
 		tag = tag[i+1:]
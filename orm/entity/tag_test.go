@@ -82,6 +82,30 @@ var testCases = map[string]*parsedTag{
 			subProperty: map[string]property{},
 		},
 	}},
+	"d3:\"column:ver,version\"": {properties: map[string]property{
+		"column": {
+			name:        "column",
+			val:         "ver",
+			subProperty: map[string]property{},
+		},
+		"version": {
+			name:        "version",
+			val:         "",
+			subProperty: map[string]property{},
+		},
+	}},
+	"d3:\"version, pk:manual\"": {properties: map[string]property{
+		"version": {
+			name:        "version",
+			val:         "",
+			subProperty: map[string]property{},
+		},
+		"pk": {
+			name:        "pk",
+			val:         "manual",
+			subProperty: map[string]property{},
+		},
+	}},
 }
 
 func TestTagParsing(t *testing.T) {
@@ -0,0 +1,139 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/godzie44/d3/orm/entity"
+)
+
+// Session event listeners. Listener registered by Orm.AddListener may implement any of this interfaces,
+// and will be notified about events of all sessions created by orm.
+type (
+	// FlushListener - OnFlush called before flush execution if there is something to flush,
+	// if error returned flush will be aborted.
+	FlushListener interface {
+		OnFlush(ctx context.Context, changeSet ChangeSet) error
+	}
+
+	// InsertListener - PostInsert called after entity inserted into database (but before commit).
+	InsertListener interface {
+		PostInsert(ctx context.Context, entity interface{})
+	}
+
+	// UpdateListener - PostUpdate called after entity updated in database (but before commit),
+	// changedFields contains names of changed entity fields.
+	UpdateListener interface {
+		PostUpdate(ctx context.Context, entity interface{}, changedFields []string)
+	}
+
+	// RemoveListener - PostRemove called after entity deleted from database (but before commit).
+	RemoveListener interface {
+		PostRemove(ctx context.Context, entity interface{})
+	}
+
+	// LoadListener - PostLoad called after entity fetched from database and hydrated.
+	LoadListener interface {
+		PostLoad(ctx context.Context, entity interface{})
+	}
+
+	// CommitListener - AfterCommit called after database transaction committed.
+	CommitListener interface {
+		AfterCommit(ctx context.Context)
+	}
+
+	// RollbackListener - AfterRollback called after database transaction rolled back.
+	RollbackListener interface {
+		AfterRollback(ctx context.Context)
+	}
+)
+
+// ChangeSet - entities which will be inserted, updated or deleted by flush.
+type ChangeSet struct {
+	Inserted []interface{}
+	Updated  []interface{}
+	Deleted  []interface{}
+}
+
+func (c ChangeSet) isEmpty() bool {
+	return len(c.Inserted) == 0 && len(c.Updated) == 0 && len(c.Deleted) == 0
+}
+
+var errUnknownListener = errors.New("listener must implement at least one of listener interfaces")
+
+type eventDispatcher struct {
+	listeners []interface{}
+}
+
+func (d *eventDispatcher) addListener(listener interface{}) error {
+	switch listener.(type) {
+	case FlushListener, InsertListener, UpdateListener, RemoveListener, LoadListener, CommitListener, RollbackListener, DomainEventHandler:
+		d.listeners = append(d.listeners, listener)
+		return nil
+	default:
+		return fmt.Errorf("%T: %w", listener, errUnknownListener)
+	}
+}
+
+func (d *eventDispatcher) onFlush(ctx context.Context, changeSet ChangeSet) error {
+	if changeSet.isEmpty() {
+		return nil
+	}
+
+	for _, l := range d.listeners {
+		if fl, ok := l.(FlushListener); ok {
+			if err := fl.OnFlush(ctx, changeSet); err != nil {
+				return fmt.Errorf("on flush: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+func (d *eventDispatcher) postInsert(ctx context.Context, box *entity.Box) {
+	for _, l := range d.listeners {
+		if il, ok := l.(InsertListener); ok {
+			il.PostInsert(ctx, box.Entity)
+		}
+	}
+}
+
+func (d *eventDispatcher) postUpdate(ctx context.Context, box *entity.Box, changedFields []string) {
+	for _, l := range d.listeners {
+		if ul, ok := l.(UpdateListener); ok {
+			ul.PostUpdate(ctx, box.Entity, changedFields)
+		}
+	}
+}
+
+func (d *eventDispatcher) postRemove(ctx context.Context, box *entity.Box) {
+	for _, l := range d.listeners {
+		if rl, ok := l.(RemoveListener); ok {
+			rl.PostRemove(ctx, box.Entity)
+		}
+	}
+}
+
+func (d *eventDispatcher) postLoad(ctx context.Context, box *entity.Box) {
+	for _, l := range d.listeners {
+		if ll, ok := l.(LoadListener); ok {
+			ll.PostLoad(ctx, box.Entity)
+		}
+	}
+}
+
+func (d *eventDispatcher) afterCommit(ctx context.Context) {
+	for _, l := range d.listeners {
+		if cl, ok := l.(CommitListener); ok {
+			cl.AfterCommit(ctx)
+		}
+	}
+}
+
+func (d *eventDispatcher) afterRollback(ctx context.Context) {
+	for _, l := range d.listeners {
+		if rl, ok := l.(RollbackListener); ok {
+			rl.AfterRollback(ctx)
+		}
+	}
+}
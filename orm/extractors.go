@@ -1,18 +1,23 @@
 package orm
 
 import (
+	"context"
 	"fmt"
 	d3entity "github.com/godzie44/d3/orm/entity"
 	"github.com/godzie44/d3/orm/query"
+	"strings"
 )
 
 type extractor func() *d3entity.Collection
 
-func (s *session) makeOneToOneExtractor(id interface{}, relatedMeta *d3entity.MetaInfo) extractor {
+func (s *session) makeOneToOneExtractor(ctx context.Context, id []interface{}, relatedMeta *d3entity.MetaInfo) extractor {
 	return func() *d3entity.Collection {
-		entities, err := s.execute(
-			query.New().ForEntity(relatedMeta).Where(relatedMeta.Pk.FullDbAlias(), "=", id), relatedMeta,
-		)
+		q := query.New().ForEntity(relatedMeta)
+		for i, pkCol := range relatedMeta.Pk.FullDbAliases() {
+			q.AndWhere(pkCol, "=", id[i])
+		}
+
+		entities, err := s.execute(ctx, q, relatedMeta)
 		if err != nil {
 			return nil
 		}
@@ -21,11 +26,14 @@ func (s *session) makeOneToOneExtractor(id interface{}, relatedMeta *d3entity.Me
 	}
 }
 
-func (s *session) makeOneToManyExtractor(joinId interface{}, relation *d3entity.OneToMany, relatedMeta *d3entity.MetaInfo) extractor {
+func (s *session) makeOneToManyExtractor(ctx context.Context, joinId []interface{}, relation *d3entity.OneToMany, relatedMeta *d3entity.MetaInfo) extractor {
 	return func() *d3entity.Collection {
-		entities, err := s.execute(
-			query.New().ForEntity(relatedMeta).Where(relatedMeta.FullColumnAlias(relation.JoinColumn), "=", joinId), relatedMeta,
-		)
+		q := query.New().ForEntity(relatedMeta)
+		for i, joinCol := range relation.JoinColumns() {
+			q.AndWhere(relatedMeta.FullColumnAlias(joinCol), "=", joinId[i])
+		}
+
+		entities, err := s.execute(ctx, q, relatedMeta)
 		if err != nil {
 			return nil
 		}
@@ -34,15 +42,23 @@ func (s *session) makeOneToManyExtractor(joinId interface{}, relation *d3entity.
 	}
 }
 
-func (s *session) makeManyToManyExtractor(id interface{}, rel *d3entity.ManyToMany, relatedMeta *d3entity.MetaInfo) extractor {
+func (s *session) makeManyToManyExtractor(ctx context.Context, id []interface{}, rel *d3entity.ManyToMany, relatedMeta *d3entity.MetaInfo) extractor {
 	return func() *d3entity.Collection {
-		entities, err := s.execute(
-			query.New().
-				ForEntity(relatedMeta).
-				Join(query.JoinInner, rel.JoinTable, fmt.Sprintf("%s.%s=%s", rel.JoinTable, rel.ReferenceColumn, relatedMeta.Pk.FullDbAlias())).
-				Where(fmt.Sprintf("%s.%s", rel.JoinTable, rel.JoinColumn), "=", id),
-			relatedMeta,
-		)
+		pkColumns := relatedMeta.Pk.FullDbAliases()
+
+		var joinOn []string
+		for i, refCol := range rel.ReferenceColumns() {
+			joinOn = append(joinOn, fmt.Sprintf("%s.%s=%s", rel.JoinTable, refCol, pkColumns[i]))
+		}
+
+		q := query.New().
+			ForEntity(relatedMeta).
+			Join(query.JoinInner, rel.JoinTable, strings.Join(joinOn, " AND "))
+		for i, joinCol := range rel.JoinColumns() {
+			q.AndWhere(fmt.Sprintf("%s.%s", rel.JoinTable, joinCol), "=", id[i])
+		}
+
+		entities, err := s.execute(ctx, q, relatedMeta)
 		if err != nil {
 			return nil
 		}
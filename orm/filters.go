@@ -0,0 +1,111 @@
+package orm
+
+import (
+	"errors"
+	"fmt"
+	"github.com/godzie44/d3/orm/entity"
+	"github.com/godzie44/d3/orm/persistence"
+	"github.com/godzie44/d3/orm/query"
+	"reflect"
+)
+
+var (
+	ErrFilterExists  = errors.New("filter already registered")
+	ErrUnknownFilter = errors.New("unknown filter")
+)
+
+// AddFilter - register named global query filter. Filter enabled in session (see session.EnableFilter) restricts
+// all queries of entities which have field with passed name (include lazy relations and relations joined by Query.With)
+// to entities with field value equal to filter parameter.
+// Besides, this field of inserted entities will be filled with filter parameter if it has zero value.
+// Filters must be registered before sessions created.
+//
+// Example (multi-tenancy):
+// orm.AddFilter("tenant", "TenantId")
+func (o *Orm) AddFilter(name, field string) error {
+	if _, exists := o.filters[name]; exists {
+		return fmt.Errorf("%s: %w", name, ErrFilterExists)
+	}
+
+	o.filters[name] = field
+	return nil
+}
+
+// sessionFilters - global filters registered in orm and filters parameters of session.
+type sessionFilters struct {
+	registered map[string]string
+	enabled    map[string]interface{}
+}
+
+func newSessionFilters(registered map[string]string) *sessionFilters {
+	return &sessionFilters{registered: registered, enabled: make(map[string]interface{})}
+}
+
+func (f *sessionFilters) enable(name string, param interface{}) error {
+	if _, exists := f.registered[name]; !exists {
+		return fmt.Errorf("%s: %w", name, ErrUnknownFilter)
+	}
+
+	f.enabled[name] = param
+	return nil
+}
+
+func (f *sessionFilters) disable(name string) {
+	delete(f.enabled, name)
+}
+
+// apply - add filter conditions of all enabled filters to query.
+func (f *sessionFilters) apply(q *query.Query) {
+	for name, param := range f.enabled {
+		q.Filter(f.registered[name], param)
+	}
+}
+
+// fields - return parameters of enabled filters by filtered field names.
+func (f *sessionFilters) fields() map[string]interface{} {
+	fields := make(map[string]interface{}, len(f.enabled))
+	for name, param := range f.enabled {
+		fields[f.registered[name]] = param
+	}
+	return fields
+}
+
+// fillInserted - fill filtered fields of inserted entities, return true if any field was filled.
+func (f *sessionFilters) fillInserted(graph *persistence.PersistGraph) (bool, error) {
+	var filled bool
+	for _, box := range graph.InsertedEntities() {
+		for name, param := range f.enabled {
+			fieldFilled, err := fillField(box, f.registered[name], param)
+			if err != nil {
+				return false, fmt.Errorf("filter %s: %w", name, err)
+			}
+			filled = filled || fieldFilled
+		}
+	}
+	return filled, nil
+}
+
+func fillField(box *entity.Box, field string, param interface{}) (bool, error) {
+	fieldInfo, exists := box.Meta.Fields[field]
+	if !exists {
+		return false, nil
+	}
+
+	val, err := box.Meta.Tools.ExtractField(box.Entity, field)
+	if err != nil {
+		return false, err
+	}
+
+	if !reflect.ValueOf(val).IsZero() {
+		return false, nil
+	}
+
+	if reflect.TypeOf(param) != fieldInfo.AssociatedType {
+		return false, fmt.Errorf("parameter type %T not match type of field %s", param, field)
+	}
+
+	if err := box.Meta.Tools.SetFieldVal(box.Entity, field, param); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -48,8 +48,8 @@ func ({{.receiver}} *{{.entity}}) __d3_makeComparator() entity.FieldComparator {
 	}
 
 	var fields []string
-	for i := 0; i < t.NumField(); i++ {
-		fields = append(fields, t.Field(i).Name)
+	for _, field := range entityFields(t) {
+		fields = append(fields, field.Path)
 	}
 
 	if err := tpl.Execute(c.out, map[string]interface{}{"receiver": receiver, "entity": name, "fields": fields}); err != nil {
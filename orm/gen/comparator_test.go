@@ -12,6 +12,7 @@ type testStruct struct {
 	num64   int64         //nolint
 	str     string        //nolint
 	nullInt sql.NullInt64 //nolint
+	events  []interface{} `d3:"-"` //nolint
 }
 
 var expectedComparatorCode = `func (t *testStruct) __d3_makeComparator() entity.FieldComparator {
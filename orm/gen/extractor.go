@@ -41,8 +41,8 @@ func ({{.receiver}} *{{.entity}}) __d3_makeFieldExtractor() entity.FieldExtracto
 	}
 
 	var fields []string
-	for i := 0; i < t.NumField(); i++ {
-		fields = append(fields, t.Field(i).Name)
+	for _, field := range entityFields(t) {
+		fields = append(fields, field.Path)
 	}
 
 	if err := tpl.Execute(e.out, map[string]interface{}{"receiver": receiver, "entity": name, "fields": fields}); err != nil {
@@ -1,6 +1,7 @@
 package gen
 
 import (
+	"github.com/godzie44/d3/orm/entity"
 	"reflect"
 	"strings"
 )
@@ -25,3 +26,29 @@ func extractTypeAndPackageName(t reflect.Type, currPkgName string) (string, stri
 
 	return name, pkgPath
 }
+
+// structField - field of entity with path from entity root, for fields of embedded value objects path is dotted (like Price.Amount).
+type structField struct {
+	reflect.StructField
+	Path string
+}
+
+// entityFields - return all entity fields except transient, including fields of embedded value objects.
+func entityFields(t reflect.Type) []structField {
+	var result []structField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if entity.IsTransient(field) {
+			continue
+		}
+		result = append(result, structField{StructField: field, Path: field.Name})
+
+		if entity.IsEmbedded(field) && field.Type.Kind() == reflect.Struct {
+			for _, embeddedField := range entityFields(field.Type) {
+				embeddedField.Path = field.Name + "." + embeddedField.Path
+				result = append(result, embeddedField)
+			}
+		}
+	}
+	return result
+}
@@ -72,21 +72,20 @@ func ({{.receiver}} *{{.entity}}) __d3_makeFieldSetter() entity.FieldSetter {
 	var customTypeFields []struct {
 		FieldName, TypeName, CustomTypeName string
 	}
-	for i := 0; i < t.NumField(); i++ {
-		field := t.Field(i)
+	for _, field := range entityFields(t) {
 		typeName, pkgName := extractTypeAndPackageName(field.Type, s.pkgPath)
 		kind := field.Type.Kind()
 		if reflect.PtrTo(field.Type).Implements(scannerType) {
-			scannerFields = append(scannerFields, struct{ FieldName, TypeName string }{FieldName: field.Name, TypeName: typeName})
+			scannerFields = append(scannerFields, struct{ FieldName, TypeName string }{FieldName: field.Path, TypeName: typeName})
 		} else {
 			if pkgName != "" && pkgName != s.pkgPath {
 				s.imports[pkgName] = struct{}{}
 			}
 
 			if kind != reflect.Ptr && kind != reflect.Struct && kind != reflect.Interface && kind.String() != typeName {
-				customTypeFields = append(customTypeFields, struct{ FieldName, TypeName, CustomTypeName string }{FieldName: field.Name, TypeName: kind.String(), CustomTypeName: typeName})
+				customTypeFields = append(customTypeFields, struct{ FieldName, TypeName, CustomTypeName string }{FieldName: field.Path, TypeName: kind.String(), CustomTypeName: typeName})
 			} else {
-				fields = append(fields, struct{ FieldName, TypeName string }{FieldName: field.Name, TypeName: typeName})
+				fields = append(fields, struct{ FieldName, TypeName string }{FieldName: field.Path, TypeName: typeName})
 			}
 		}
 	}
@@ -105,3 +105,52 @@ func TestSetterGeneration(t *testing.T) {
 	assert.Equal(t, []string{"database/sql/driver", "github.com/godzie44/d3/orm/gen",
 		"github.com/godzie44/d3/orm/query", "io", "time"}, imports)
 }
+
+type money struct {
+	amount   int64          //nolint
+	currency sql.NullString //nolint
+}
+
+type setterEmbeddedTestStruct struct {
+	price money `d3:"embedded:<prefix:price_>"` //nolint
+}
+
+var expectedEmbeddedSetter = `func (s *setterEmbeddedTestStruct) __d3_makeFieldSetter() entity.FieldSetter {
+	return func(s interface{}, name string, val interface{}) error {
+		eTyped, ok := s.(*setterEmbeddedTestStruct)
+		if !ok {
+			return fmt.Errorf("invalid entity type")
+		}
+		
+		switch name { 
+		case "price":
+			eTyped.price = val.(gen.money)
+			return nil 
+		case "price.amount":
+			eTyped.price.amount = val.(int64)
+			return nil 
+		
+		
+		case "price.currency":
+			if valuer, isValuer := val.(driver.Valuer); isValuer {
+				v, err := valuer.Value()
+				if err != nil {
+					return eTyped.price.currency.Scan(nil)
+				} 
+				return eTyped.price.currency.Scan(v)
+			}
+			return eTyped.price.currency.Scan(val) 
+		default:
+			return fmt.Errorf("field %s not found", name)
+		}
+	}
+}`
+
+func TestSetterGenerationWithEmbedded(t *testing.T) {
+	buff := &strings.Builder{}
+	gen := &setter{out: buff, imports: map[string]struct{}{}}
+
+	gen.handle(reflect.TypeOf(setterEmbeddedTestStruct{}))
+
+	assert.Equal(t, expectedEmbeddedSetter, strings.Trim(buff.String(), "\n"))
+}
@@ -1,6 +1,7 @@
 package orm
 
 import (
+	"context"
 	"fmt"
 	d3entity "github.com/godzie44/d3/orm/entity"
 	"github.com/godzie44/d3/orm/query"
@@ -10,10 +11,32 @@ import (
 type ScalarDataMapper func(data interface{}, into reflect.Kind) interface{}
 
 type hydrator struct {
+	// ctx - context of query, lazy relations of hydrated entities will be fetched within it
+	ctx                context.Context
 	session            *session
 	meta               *d3entity.MetaInfo
 	afterHydrateEntity func(b *d3entity.Box)
 	scalarMapper       ScalarDataMapper
+	// table - alias of entity table in fetched rows, empty string means that columns prefixed with entity table name.
+	table string
+}
+
+// columns - return names of entity columns in fetched rows.
+func (h *hydrator) columns(dbAliases []string) []string {
+	table := h.table
+	if table == "" {
+		table = h.meta.TableName
+	}
+
+	columns := make([]string, len(dbAliases))
+	for i := range dbAliases {
+		columns[i] = table + "." + dbAliases[i]
+	}
+	return columns
+}
+
+func (h *hydrator) column(dbAlias string) string {
+	return h.columns([]string{dbAlias})[0]
 }
 
 func (h *hydrator) hydrate(fetchedData []map[string]interface{}, plan *query.FetchPlan) (*d3entity.Collection, error) {
@@ -21,7 +44,7 @@ func (h *hydrator) hydrate(fetchedData []map[string]interface{}, plan *query.Fet
 	entityKeyIndexMap := make(map[interface{}]int)
 
 	for _, rowData := range fetchedData {
-		pkVal := rowData[h.meta.Pk.FullDbAlias()]
+		pkVal := keyFromRow(rowData, h.columns(h.meta.Pk.DbAliases()))
 
 		if ind, exists := entityKeyIndexMap[pkVal]; exists {
 			groupByEntityData[ind] = append(groupByEntityData[ind], rowData)
@@ -51,7 +74,7 @@ func (h *hydrator) hydrate(fetchedData []map[string]interface{}, plan *query.Fet
 
 func (h *hydrator) hydrateOne(entity interface{}, entityData []map[string]interface{}, plan *query.FetchPlan) error {
 	for _, field := range h.meta.Fields {
-		fieldValue, exists := entityData[0][field.FullDbAlias]
+		fieldValue, exists := entityData[0][h.column(field.DbAlias)]
 		if !exists {
 			continue
 		}
@@ -65,7 +88,7 @@ func (h *hydrator) hydrateOne(entity interface{}, entityData []map[string]interf
 		var fieldValue interface{}
 		var err error
 		if plan.CanFetchRelation(rel) {
-			if entityData[0][h.meta.Pk.FullDbAlias()] == nil {
+			if keyFromRow(entityData[0], h.columns(h.meta.Pk.DbAliases())) == nil {
 				fieldValue = nil
 			} else {
 				fieldValue, err = h.fetchRelation(rel, entityData, plan)
@@ -87,17 +110,21 @@ func (h *hydrator) hydrateOne(entity interface{}, entityData []map[string]interf
 
 func (h *hydrator) fetchRelation(relation d3entity.Relation, entityData []map[string]interface{}, plan *query.FetchPlan) (interface{}, error) {
 	relationMeta := h.meta.RelatedMeta[relation.RelatedWith()]
+	childPlan := plan.GetChildPlan(relation)
 
 	relationHydrator := &hydrator{
+		ctx:                h.ctx,
 		session:            h.session,
 		meta:               relationMeta,
 		afterHydrateEntity: h.afterHydrateEntity,
 		scalarMapper:       h.scalarMapper,
+		table:              childPlan.Alias(),
 	}
+	relationPkColumns := relationHydrator.columns(relationMeta.Pk.DbAliases())
 
 	switch relation.(type) {
 	case *d3entity.OneToOne:
-		relationPkVal := entityData[0][relationMeta.Pk.FullDbAlias()]
+		relationPkVal := keyFromRow(entityData[0], relationPkColumns)
 
 		var entity interface{}
 		if relationPkVal == nil {
@@ -105,7 +132,7 @@ func (h *hydrator) fetchRelation(relation d3entity.Relation, entityData []map[st
 		}
 
 		entity = relationMeta.Tools.NewInstance()
-		err := relationHydrator.hydrateOne(entity, entityData, plan.GetChildPlan(relation))
+		err := relationHydrator.hydrateOne(entity, entityData, childPlan)
 		if err != nil {
 			return nil, fmt.Errorf("hydration: %w", err)
 		}
@@ -119,7 +146,7 @@ func (h *hydrator) fetchRelation(relation d3entity.Relation, entityData []map[st
 		groupByEntity := make(map[interface{}][]map[string]interface{})
 
 		for _, entityData := range entityData {
-			pkVal := entityData[relationMeta.Pk.FullDbAlias()]
+			pkVal := keyFromRow(entityData, relationPkColumns)
 			if pkVal == nil {
 				continue
 			}
@@ -129,7 +156,7 @@ func (h *hydrator) fetchRelation(relation d3entity.Relation, entityData []map[st
 
 		for _, data := range groupByEntity {
 			entity := relationMeta.Tools.NewInstance()
-			err := relationHydrator.hydrateOne(entity, data, plan.GetChildPlan(relation))
+			err := relationHydrator.hydrateOne(entity, data, childPlan)
 			if err != nil {
 				return nil, fmt.Errorf("hydration: %w", err)
 			}
@@ -147,7 +174,7 @@ func (h *hydrator) fetchRelation(relation d3entity.Relation, entityData []map[st
 func (h *hydrator) createRelation(entity interface{}, relation d3entity.Relation, entityData map[string]interface{}) (interface{}, error) {
 	switch rel := relation.(type) {
 	case *d3entity.OneToOne:
-		relatedId, exists := entityData[h.meta.FullColumnAlias(rel.JoinColumn)]
+		relatedId, exists := columnValues(entityData, h.columns(rel.JoinColumns()))
 		if !exists {
 			return nil, fmt.Errorf("hydration: realated relation not exists")
 		}
@@ -156,10 +183,14 @@ func (h *hydrator) createRelation(entity interface{}, relation d3entity.Relation
 			return d3entity.NewCell(nil), nil
 		}
 
-		extractor := h.session.makeOneToOneExtractor(relatedId, h.meta.RelatedMeta[rel.RelatedWith()])
+		extractor := h.session.makeOneToOneExtractor(h.ctx, relatedId, h.meta.RelatedMeta[rel.RelatedWith()])
 
 		switch rel.Type() {
 		case d3entity.Lazy:
+			if rel.BatchSize() > 1 {
+				extractor = h.session.makeBatchExtractor(h.ctx, relatedId, rel, h.meta.RelatedMeta[rel.RelatedWith()])
+			}
+
 			lazy := d3entity.NewLazyWrappedEntity(extractor, func(cell *d3entity.Cell) {
 				h.session.uow.updateFieldOfOriginal(d3entity.NewBox(entity, h.meta), relation.Field().Name, cell)
 			})
@@ -173,21 +204,29 @@ func (h *hydrator) createRelation(entity interface{}, relation d3entity.Relation
 			return d3entity.NewCell(collection.Get(0)), nil
 		}
 	case *d3entity.OneToMany, *d3entity.ManyToMany:
-		relatedId, exists := entityData[h.meta.Pk.FullDbAlias()]
+		relatedId, exists := columnValues(entityData, h.columns(h.meta.Pk.DbAliases()))
 		if !exists {
 			return nil, fmt.Errorf("hydration: owner pk not exists")
 		}
 
+		if relatedId == nil {
+			return d3entity.NewCollection(), nil
+		}
+
 		var extractor extractor
 		switch rel := rel.(type) {
 		case *d3entity.OneToMany:
-			extractor = h.session.makeOneToManyExtractor(relatedId, rel, h.meta.RelatedMeta[rel.RelatedWith()])
+			extractor = h.session.makeOneToManyExtractor(h.ctx, relatedId, rel, h.meta.RelatedMeta[rel.RelatedWith()])
 		case *d3entity.ManyToMany:
-			extractor = h.session.makeManyToManyExtractor(relatedId, rel, h.meta.RelatedMeta[rel.RelatedWith()])
+			extractor = h.session.makeManyToManyExtractor(h.ctx, relatedId, rel, h.meta.RelatedMeta[rel.RelatedWith()])
 		}
 
 		switch rel.Type() {
 		case d3entity.Lazy:
+			if rel.BatchSize() > 1 {
+				extractor = h.session.makeBatchExtractor(h.ctx, relatedId, rel, h.meta.RelatedMeta[rel.RelatedWith()])
+			}
+
 			lazyCol := d3entity.NewLazyCollection(extractor, func(c *d3entity.Collection) {
 				h.session.uow.updateFieldOfOriginal(d3entity.NewBox(entity, h.meta), relation.Field().Name, c)
 			})
@@ -200,3 +239,38 @@ func (h *hydrator) createRelation(entity interface{}, relation d3entity.Relation
 
 	return nil, fmt.Errorf("hydration: unsupported relation type")
 }
+
+// keyFromRow - return key built from values of columns, for several columns CompositeKey returned.
+// If one of columns value is nil then nil returned.
+func keyFromRow(row map[string]interface{}, columns []string) interface{} {
+	values, _ := columnValues(row, columns)
+	if values == nil {
+		return nil
+	}
+
+	if len(values) == 1 {
+		return values[0]
+	}
+
+	return d3entity.NewCompositeKey(values...)
+}
+
+// columnValues - return values of columns, second value is false if some of columns not exists in row.
+// If one of columns value is nil then nil returned.
+func columnValues(row map[string]interface{}, columns []string) ([]interface{}, bool) {
+	values := make([]interface{}, 0, len(columns))
+	for _, col := range columns {
+		val, exists := row[col]
+		if !exists {
+			return nil, false
+		}
+
+		if val == nil {
+			return nil, true
+		}
+
+		values = append(values, val)
+	}
+
+	return values, true
+}
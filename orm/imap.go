@@ -1,9 +1,12 @@
 package orm
 
 import (
+	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"github.com/godzie44/d3/orm/entity"
 	"github.com/godzie44/d3/orm/query"
+	"reflect"
 	"sync"
 )
 
@@ -21,26 +24,65 @@ func newIdentityMap() *identityMap {
 
 // canApply check that only id in where clause.
 // query with joins not allowed, cause we don't know does entities in identityMap has related entities in memory.
+// locking query not allowed, cause rows must be locked in database.
 func (im *identityMap) canApply(plan *query.FetchPlan) bool {
-	return !plan.HasJoins() && len(plan.PKs()) != 0 && plan.NoNestedWhere() && plan.WhereExprCount() == 1
+	return !plan.HasJoins() && !plan.HasLock() && len(plan.PKs()) != 0 && plan.NoNestedWhere() && plan.WhereExprCount() == 1
 }
 
-func (im *identityMap) executePlan(plan *query.FetchPlan) (*entity.Collection, error) {
+// executePlan - return entities from identity map, errCantExecutePlan returned if one of entities not in map
+// or not matched to query filters or soft deleted (database not return such entity, so identity map mustn't too).
+func (im *identityMap) executePlan(plan *query.FetchPlan, meta *entity.MetaInfo) (*entity.Collection, error) {
 	im.RLock()
 	defer im.RUnlock()
 
 	collection := entity.NewCollection()
 	for _, id := range plan.PKs() {
-		if e, exists := im.get(plan.EntityName(), id); exists {
-			collection.Add(e)
-		} else {
+		e, exists := im.get(plan.EntityName(), id)
+		if !exists || !matchFilters(e, meta, plan.Filters()) || (!plan.WithDeleted() && isSoftDeleted(e, meta)) {
 			return nil, errCantExecutePlan
 		}
+		collection.Add(e)
 	}
 
 	return collection, nil
 }
 
+// matchFilters - true if entity fields equal to filter values, fields which entity doesn't have are ignored.
+func matchFilters(e interface{}, meta *entity.MetaInfo, filters map[string]interface{}) bool {
+	for field, value := range filters {
+		if _, exists := meta.Fields[field]; !exists {
+			continue
+		}
+
+		fieldVal, err := meta.Tools.ExtractField(e, field)
+		if err != nil || !reflect.DeepEqual(fieldVal, value) {
+			return false
+		}
+	}
+	return true
+}
+
+// isSoftDeleted - true if entity deletion time is set, entity with not extractable deletion time considered deleted.
+func isSoftDeleted(e interface{}, meta *entity.MetaInfo) bool {
+	if meta.SoftDelete == nil {
+		return false
+	}
+
+	deletedAt, err := meta.Tools.ExtractField(e, meta.SoftDelete.Name)
+	if err != nil {
+		return true
+	}
+	t, ok := deletedAt.(sql.NullTime)
+	return !ok || t.Valid
+}
+
+// find - return entity from identity map by primary key value.
+func (im *identityMap) find(name entity.Name, key interface{}) (interface{}, bool) {
+	im.RLock()
+	defer im.RUnlock()
+	return im.get(name, key)
+}
+
 func (im *identityMap) putEntities(meta *entity.MetaInfo, collection *entity.Collection) {
 	iter := collection.MakeIter()
 
@@ -64,21 +106,53 @@ func (im *identityMap) add(name entity.Name, key interface{}, e interface{}) {
 	im.data[name][normalizeKey(key)] = e
 }
 
+func (im *identityMap) remove(meta *entity.MetaInfo, e interface{}) {
+	pkVal, err := meta.ExtractPkValue(e)
+	if err != nil {
+		return
+	}
+
+	im.Lock()
+	defer im.Unlock()
+	delete(im.data[meta.EntityName], normalizeKey(pkVal))
+}
+
+// copyData - return copy of identity map content.
+func (im *identityMap) copyData() map[entity.Name]map[interface{}]interface{} {
+	im.RLock()
+	defer im.RUnlock()
+
+	data := make(map[entity.Name]map[interface{}]interface{}, len(im.data))
+	for name, entities := range im.data {
+		data[name] = make(map[interface{}]interface{}, len(entities))
+		for key, e := range entities {
+			data[name][key] = e
+		}
+	}
+	return data
+}
+
+// restoreData - replace identity map content with data returned by copyData.
+func (im *identityMap) restoreData(data map[entity.Name]map[interface{}]interface{}) {
+	im.Lock()
+	defer im.Unlock()
+	im.data = data
+}
+
 func (im *identityMap) get(name entity.Name, key interface{}) (interface{}, bool) {
 	e, exists := im.data[name][normalizeKey(key)]
 
 	return e, exists
 }
 
+// normalizeKey - bring key to one type regardless of source (entity field, query parameter or fetched row).
 func normalizeKey(key interface{}) interface{} {
-	switch k := key.(type) {
-	case int:
-		return int64(k)
-	case int32:
-		return int64(k)
-	case int64:
-		return k
-	default:
-		return k
+	if valuer, ok := key.(driver.Valuer); ok {
+		// values not usable as map key (like []byte) ignored
+		if val, err := valuer.Value(); err == nil && val != nil && reflect.TypeOf(val).Comparable() {
+			key = val
+		}
 	}
+
+	return entity.NormalizeInt(key)
 }
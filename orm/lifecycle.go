@@ -0,0 +1,80 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"github.com/godzie44/d3/orm/entity"
+)
+
+// Lifecycle callbacks. Entity may implement any of this interfaces for react on own lifecycle events.
+type (
+	// PrePersister - PrePersist called when entity passed to Repository.Persists,
+	// if error returned entity will not be persisted.
+	PrePersister interface {
+		PrePersist(ctx context.Context) error
+	}
+
+	// PostLoader - PostLoad called after entity fetched from database and hydrated.
+	PostLoader interface {
+		PostLoad(ctx context.Context)
+	}
+
+	// PreUpdater - PreUpdate called on flush before update of changed entity,
+	// if error returned flush will be aborted. Changes made in PreUpdate will be persisted in the same flush.
+	PreUpdater interface {
+		PreUpdate(ctx context.Context) error
+	}
+
+	// PreRemover - PreRemove called when entity passed to Repository.Delete,
+	// if error returned entity will not be deleted.
+	PreRemover interface {
+		PreRemove(ctx context.Context) error
+	}
+
+	// PostFlusher - PostFlush called after inserted or updated entity flushed into database.
+	PostFlusher interface {
+		PostFlush(ctx context.Context)
+	}
+)
+
+func callPrePersist(ctx context.Context, box *entity.Box) error {
+	if e, ok := box.Entity.(PrePersister); ok {
+		if err := e.PrePersist(ctx); err != nil {
+			return fmt.Errorf("pre persist %s: %w", box.GetEName(), err)
+		}
+	}
+	return nil
+}
+
+func callPostLoad(ctx context.Context, box *entity.Box) {
+	if e, ok := box.Entity.(PostLoader); ok {
+		e.PostLoad(ctx)
+	}
+}
+
+func callPreUpdate(ctx context.Context, box *entity.Box) (bool, error) {
+	e, ok := box.Entity.(PreUpdater)
+	if !ok {
+		return false, nil
+	}
+
+	if err := e.PreUpdate(ctx); err != nil {
+		return true, fmt.Errorf("pre update %s: %w", box.GetEName(), err)
+	}
+	return true, nil
+}
+
+func callPreRemove(ctx context.Context, box *entity.Box) error {
+	if e, ok := box.Entity.(PreRemover); ok {
+		if err := e.PreRemove(ctx); err != nil {
+			return fmt.Errorf("pre remove %s: %w", box.GetEName(), err)
+		}
+	}
+	return nil
+}
+
+func callPostFlush(ctx context.Context, box *entity.Box) {
+	if e, ok := box.Entity.(PostFlusher); ok {
+		e.PostFlush(ctx)
+	}
+}
@@ -2,9 +2,11 @@ package orm
 
 import (
 	"context"
+	"crypto/rand"
 	"fmt"
 	d3Entity "github.com/godzie44/d3/orm/entity"
 	"github.com/godzie44/d3/orm/schema"
+	"time"
 )
 
 type ctxKey string
@@ -15,15 +17,28 @@ const sessionKey ctxKey = "d3_session"
 type Orm struct {
 	storage      Driver
 	metaRegistry *d3Entity.MetaRegistry
+	events       *eventDispatcher
+	outboxMeta   *d3Entity.MetaInfo
+	filters      map[string]string
+	clock        func() time.Time
+	txRetries    int
+	cursorKey    []byte
 }
 
 // New - create an instance of d3 orm.
 //
 // driver - d3 wrapper on database driver. Find it in adapter package.
 func New(driver Driver) *Orm {
+	cursorKey := make([]byte, 32)
+	_, _ = rand.Read(cursorKey)
+
 	return &Orm{
 		storage:      driver,
 		metaRegistry: d3Entity.NewMetaRegistry(),
+		events:       &eventDispatcher{},
+		filters:      make(map[string]string),
+		clock:        time.Now,
+		cursorKey:    cursorKey,
 	}
 }
 
@@ -34,6 +49,32 @@ func (o *Orm) Register(entities ...interface{}) error {
 	return o.metaRegistry.Add(entities...)
 }
 
+// AddListener - register listener of session events. Listener must implement at least one of listener interfaces
+// (FlushListener, InsertListener, UpdateListener, RemoveListener, LoadListener, CommitListener, RollbackListener,
+// DomainEventHandler).
+// Listeners must be registered before sessions created.
+func (o *Orm) AddListener(listener interface{}) error {
+	return o.events.addListener(listener)
+}
+
+// SetClock - set source of current time used for entity timestamps (created_at, updated_at),
+// soft delete and outbox messages. Useful for deterministic tests. Must be called before sessions created.
+func (o *Orm) SetClock(clock func() time.Time) {
+	o.clock = clock
+}
+
+// SetTxRetries - set how many times session.Transactional repeats transaction after serialization failure or deadlock.
+// Zero (default) means no retries. Must be called before sessions created.
+func (o *Orm) SetTxRetries(retries int) {
+	o.txRetries = retries
+}
+
+// SetCursorKey - set secret key used to sign paginator cursors. By default random key generated for each orm instance,
+// so set the same key on all application instances if cursors passed between them. Must be called before repositories created.
+func (o *Orm) SetCursorKey(key []byte) {
+	o.cursorKey = key
+}
+
 // CtxWithSession append new session instance to context.
 func (o *Orm) CtxWithSession(ctx context.Context) context.Context {
 	return context.WithValue(ctx, sessionKey, o.MakeSession())
@@ -49,7 +90,14 @@ func Session(ctx context.Context) *session {
 
 // MakeSession - create new instance of session.
 func (o *Orm) MakeSession() *session {
-	return newSession(o.storage, newUOW(o.storage))
+	uow := newUOW(o.storage, o.events)
+	uow.outboxMeta = o.outboxMeta
+	uow.filters = newSessionFilters(o.filters)
+	uow.clock = o.clock
+
+	sess := newSession(o.storage, uow)
+	sess.txRetries = o.txRetries
+	return sess
 }
 
 // MakeRepository - create new repository for entity.
@@ -63,6 +111,7 @@ func (o *Orm) MakeRepository(entity interface{}) (*Repository, error) {
 
 	return &Repository{
 		entityMeta: entityMeta,
+		cursorKey:  o.cursorKey,
 	}, nil
 }
 
@@ -75,3 +124,13 @@ func (o *Orm) GenerateSchema() (string, error) {
 	}
 	return schema.NewBuilder(generator).Build(o.metaRegistry)
 }
+
+// GenerateSchemaDiff - create sql DDL for migrate current database schema to schema of registered entities.
+// May return error if driver nonsupport schema introspection.
+func (o *Orm) GenerateSchemaDiff() (string, error) {
+	inspector, adapterCanInspectSchema := o.storage.(schema.StorageSchemaInspector)
+	if !adapterCanInspectSchema {
+		return "", fmt.Errorf("adapter unsupport schema introspection")
+	}
+	return schema.NewDiffer(inspector).Diff(o.metaRegistry)
+}
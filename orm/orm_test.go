@@ -8,7 +8,7 @@ import (
 
 type testEntity1 struct {
 	Id  int64       `d3:"pk:auto"`
-	Rel interface{} `d3:"one_to_one:<target_entity:github.com/godzie44/d3/orm/testEntity2>"`
+	Rel interface{} `d3:"one_to_one:<target_entity:github.com/godzie44/d3/orm/testEntity2,join_on:rel_id>"`
 }
 
 func (t *testEntity1) D3Token() entity.MetaToken {
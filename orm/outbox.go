@@ -0,0 +1,184 @@
+package orm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/godzie44/d3/orm/entity"
+	"github.com/godzie44/d3/orm/outbox"
+	"github.com/godzie44/d3/orm/persistence"
+	"sort"
+)
+
+var errOutboxDisabled = errors.New("outbox disabled, call EnableOutbox first")
+
+// EnableOutbox - store domain events (see EventRecorder) in outbox table, in the same transaction as flush.
+// Outbox table created by GenerateSchema, use OutboxRelay for publish stored messages.
+// Must be called before sessions created.
+func (o *Orm) EnableOutbox() error {
+	if err := o.metaRegistry.Add((*outbox.Message)(nil)); err != nil {
+		return fmt.Errorf("enable outbox: %w", err)
+	}
+
+	meta, err := o.metaRegistry.GetMeta((*outbox.Message)(nil))
+	if err != nil {
+		return fmt.Errorf("enable outbox: %w", err)
+	}
+	o.outboxMeta = &meta
+
+	return nil
+}
+
+// writeOutbox - insert outbox message for every domain event.
+func (uow *unitOfWork) writeOutbox(ctx context.Context, pusher persistence.Pusher, events []recordedEvent) error {
+	if uow.outboxMeta == nil {
+		return nil
+	}
+
+	now := uow.clock()
+	for _, e := range events {
+		pk, err := e.aggregate.Meta.ExtractPkValues(e.aggregate.Entity)
+		if err != nil {
+			return fmt.Errorf("outbox: %w", err)
+		}
+
+		msg, err := outbox.NewMessage(e.event, pk, now)
+		if err != nil {
+			return fmt.Errorf("outbox: %w", err)
+		}
+
+		cols, values, err := insertValues(uow.outboxMeta, msg)
+		if err != nil {
+			return fmt.Errorf("outbox: %w", err)
+		}
+
+		if err := pusher.Insert(ctx, uow.outboxMeta.TableName, cols, values, persistence.Undefined); err != nil {
+			return fmt.Errorf("outbox: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// insertValues - return columns and values of all entity fields except auto generated primary key.
+func insertValues(meta *entity.MetaInfo, e interface{}) ([]string, []interface{}, error) {
+	generated := make(map[string]bool, len(meta.Pk.Fields))
+	if meta.Pk.Strategy == entity.Auto {
+		for _, f := range meta.Pk.Fields {
+			generated[f.Name] = true
+		}
+	}
+
+	names := make([]string, 0, len(meta.Fields))
+	for name := range meta.Fields {
+		if !generated[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	cols := make([]string, 0, len(names))
+	values := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		val, err := meta.Tools.ExtractField(e, name)
+		if err != nil {
+			return nil, nil, err
+		}
+		cols = append(cols, meta.Fields[name].DbAlias)
+		values = append(values, val)
+	}
+
+	return cols, values, nil
+}
+
+// RowLockDetector - driver may implement this interface to report whether it supports row locking clauses
+// (FOR UPDATE with SKIP LOCKED), drivers which not implement it considered supporting them.
+type RowLockDetector interface {
+	SupportsRowLock() bool
+}
+
+// OutboxRelay - publish unpublished outbox messages to sink and mark them as published.
+// Delivery is at-least-once: if messages published, but not marked (for example, transaction commit failed),
+// they published again by next Relay call, so sink consumers must be idempotent.
+// Fetched messages locked with FOR UPDATE SKIP LOCKED, so several relays can run concurrently and each
+// message published by one of them. If driver not supports row locking (see RowLockDetector), messages not locked
+// and only one relay must run at a time, otherwise messages published several times.
+type OutboxRelay struct {
+	orm        *Orm
+	repository *Repository
+	sink       outbox.Sink
+}
+
+// MakeOutboxRelay - create relay of outbox messages into sink.
+func (o *Orm) MakeOutboxRelay(sink outbox.Sink) (*OutboxRelay, error) {
+	if o.outboxMeta == nil {
+		return nil, errOutboxDisabled
+	}
+
+	return &OutboxRelay{
+		orm:        o,
+		repository: &Repository{entityMeta: *o.outboxMeta},
+		sink:       sink,
+	}, nil
+}
+
+// Relay - publish next page of unpublished messages (in order of creation) and mark them as published.
+// Return count of published messages, zero means there is nothing to publish.
+func (r *OutboxRelay) Relay(ctx context.Context, pageSize int) (int, error) {
+	ctx = r.orm.CtxWithSession(ctx)
+	sess := Session(ctx)
+
+	if err := sess.BeginTx(ctx); err != nil {
+		return 0, fmt.Errorf("outbox relay: %w", err)
+	}
+
+	published, err := r.relay(ctx, pageSize)
+	if err != nil {
+		_ = sess.RollbackTx()
+		return 0, fmt.Errorf("outbox relay: %w", err)
+	}
+
+	if err := sess.CommitTx(); err != nil {
+		return 0, fmt.Errorf("outbox relay: %w", err)
+	}
+	return published, nil
+}
+
+func (r *OutboxRelay) relay(ctx context.Context, pageSize int) (int, error) {
+	table := r.repository.entityMeta.TableName
+	q := r.repository.Select().
+		Where(table+".published_at", "IS NULL").
+		OrderBy(table + ".id").
+		Limit(pageSize)
+	if Session(ctx).supportsRowLock() {
+		q.ForUpdate().SkipLocked()
+	}
+
+	coll, err := r.repository.FindAll(ctx, q)
+	if err != nil {
+		return 0, err
+	}
+	if coll.Count() == 0 {
+		return 0, nil
+	}
+
+	messages := make([]*outbox.Message, 0, coll.Count())
+	for _, el := range coll.ToSlice() {
+		messages = append(messages, el.(*outbox.Message))
+	}
+
+	if err := r.sink.Publish(ctx, messages); err != nil {
+		return 0, err
+	}
+
+	now := r.orm.clock()
+	for _, msg := range messages {
+		msg.PublishedAt = sql.NullTime{Time: now, Valid: true}
+	}
+
+	if err := Session(ctx).Flush(ctx); err != nil {
+		return 0, err
+	}
+	return len(messages), nil
+}
@@ -0,0 +1,18 @@
+package outbox
+
+import (
+	"database/sql"
+	"time"
+)
+
+//d3:entity
+//d3_table:d3_outbox
+//d3_index:d3_outbox_published_at_idx(published_at)
+type Message struct {
+	Id          sql.NullInt64 `d3:"pk:auto"`
+	Type        string
+	Payload     string
+	AggregateId string
+	CreatedAt   time.Time
+	PublishedAt sql.NullTime
+}
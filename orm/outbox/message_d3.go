@@ -0,0 +1,165 @@
+// Code generated by d3. DO NOT EDIT.
+
+package outbox
+
+import "github.com/godzie44/d3/orm/entity"
+import "time"
+import "database/sql/driver"
+import "fmt"
+
+func (m *Message) D3Token() entity.MetaToken {
+	return entity.MetaToken{
+		Tpl:       (*Message)(nil),
+		TableName: "d3_outbox",
+		Tools: entity.InternalTools{
+			ExtractField:  m.__d3_makeFieldExtractor(),
+			SetFieldVal:   m.__d3_makeFieldSetter(),
+			CompareFields: m.__d3_makeComparator(),
+			NewInstance:   m.__d3_makeInstantiator(),
+			Copy:          m.__d3_makeCopier(),
+		},
+		Indexes: []entity.Index{
+
+			{Name: "d3_outbox_published_at_idx", Columns: []string{"published_at"}, Unique: false},
+		},
+	}
+}
+
+func (m *Message) __d3_makeFieldExtractor() entity.FieldExtractor {
+	return func(s interface{}, name string) (interface{}, error) {
+		sTyped, ok := s.(*Message)
+		if !ok {
+			return nil, fmt.Errorf("invalid entity type")
+		}
+
+		switch name {
+
+		case "Id":
+			return sTyped.Id, nil
+
+		case "Type":
+			return sTyped.Type, nil
+
+		case "Payload":
+			return sTyped.Payload, nil
+
+		case "AggregateId":
+			return sTyped.AggregateId, nil
+
+		case "CreatedAt":
+			return sTyped.CreatedAt, nil
+
+		case "PublishedAt":
+			return sTyped.PublishedAt, nil
+
+		default:
+			return nil, fmt.Errorf("field %s not found", name)
+		}
+	}
+}
+
+func (m *Message) __d3_makeInstantiator() entity.Instantiator {
+	return func() interface{} {
+		return &Message{}
+	}
+}
+
+func (m *Message) __d3_makeFieldSetter() entity.FieldSetter {
+	return func(s interface{}, name string, val interface{}) error {
+		eTyped, ok := s.(*Message)
+		if !ok {
+			return fmt.Errorf("invalid entity type")
+		}
+
+		switch name {
+		case "Type":
+			eTyped.Type = val.(string)
+			return nil
+		case "Payload":
+			eTyped.Payload = val.(string)
+			return nil
+		case "AggregateId":
+			eTyped.AggregateId = val.(string)
+			return nil
+		case "CreatedAt":
+			eTyped.CreatedAt = val.(time.Time)
+			return nil
+
+		case "Id":
+			if valuer, isValuer := val.(driver.Valuer); isValuer {
+				v, err := valuer.Value()
+				if err != nil {
+					return eTyped.Id.Scan(nil)
+				}
+				return eTyped.Id.Scan(v)
+			}
+			return eTyped.Id.Scan(val)
+		case "PublishedAt":
+			if valuer, isValuer := val.(driver.Valuer); isValuer {
+				v, err := valuer.Value()
+				if err != nil {
+					return eTyped.PublishedAt.Scan(nil)
+				}
+				return eTyped.PublishedAt.Scan(v)
+			}
+			return eTyped.PublishedAt.Scan(val)
+		default:
+			return fmt.Errorf("field %s not found", name)
+		}
+	}
+}
+
+func (m *Message) __d3_makeCopier() entity.Copier {
+	return func(src interface{}) interface{} {
+		srcTyped, ok := src.(*Message)
+		if !ok {
+			return fmt.Errorf("invalid entity type")
+		}
+
+		copy := &Message{}
+
+		copy.Id = srcTyped.Id
+		copy.Type = srcTyped.Type
+		copy.Payload = srcTyped.Payload
+		copy.AggregateId = srcTyped.AggregateId
+		copy.CreatedAt = srcTyped.CreatedAt
+		copy.PublishedAt = srcTyped.PublishedAt
+
+		return copy
+	}
+}
+
+func (m *Message) __d3_makeComparator() entity.FieldComparator {
+	return func(e1, e2 interface{}, fName string) bool {
+		if e1 == nil || e2 == nil {
+			return e1 == e2
+		}
+
+		e1Typed, ok := e1.(*Message)
+		if !ok {
+			return false
+		}
+		e2Typed, ok := e2.(*Message)
+		if !ok {
+			return false
+		}
+
+		switch fName {
+
+		case "Id":
+			return e1Typed.Id == e2Typed.Id
+		case "Type":
+			return e1Typed.Type == e2Typed.Type
+		case "Payload":
+			return e1Typed.Payload == e2Typed.Payload
+		case "AggregateId":
+			return e1Typed.AggregateId == e2Typed.AggregateId
+		case "CreatedAt":
+			return e1Typed.CreatedAt == e2Typed.CreatedAt
+		case "PublishedAt":
+			return e1Typed.PublishedAt == e2Typed.PublishedAt
+		default:
+			return false
+		}
+	}
+}
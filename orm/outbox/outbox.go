@@ -0,0 +1,86 @@
+// Package outbox contains message entity of transactional outbox (see Orm.EnableOutbox) and sinks for outbox relay.
+package outbox
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Named - event may implement this interface for define own message type, otherwise go type of event used.
+type Named interface {
+	EventName() string
+}
+
+// NewMessage - create outbox message for event, payload is a JSON representation of event.
+//
+// aggregatePk - primary key values of aggregate recorded event.
+func NewMessage(event interface{}, aggregatePk []interface{}, createdAt time.Time) (*Message, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("marshal event %T: %w", event, err)
+	}
+
+	eventType := fmt.Sprintf("%T", event)
+	if named, ok := event.(Named); ok {
+		eventType = named.EventName()
+	}
+
+	aggregateID, err := aggregateID(aggregatePk)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Message{
+		Type:        eventType,
+		Payload:     string(payload),
+		AggregateId: aggregateID,
+		CreatedAt:   createdAt,
+	}, nil
+}
+
+func aggregateID(pk []interface{}) (string, error) {
+	parts := make([]string, 0, len(pk))
+	for _, val := range pk {
+		if valuer, ok := val.(driver.Valuer); ok {
+			v, err := valuer.Value()
+			if err != nil {
+				return "", fmt.Errorf("aggregate id: %w", err)
+			}
+			val = v
+		}
+		parts = append(parts, fmt.Sprint(val))
+	}
+	return strings.Join(parts, ","), nil
+}
+
+// Sink - destination of outbox messages, like message broker.
+type Sink interface {
+	// Publish - publish messages, if error returned messages will not be marked as published.
+	Publish(ctx context.Context, messages []*Message) error
+}
+
+// MemorySink - sink that keeps published messages in memory. Useful for tests.
+type MemorySink struct {
+	mu       sync.Mutex
+	messages []*Message
+}
+
+// Publish - store messages in memory.
+func (s *MemorySink) Publish(_ context.Context, messages []*Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages = append(s.messages, messages...)
+	return nil
+}
+
+// Messages - return all published messages.
+func (s *MemorySink) Messages() []*Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*Message(nil), s.messages...)
+}
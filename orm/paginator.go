@@ -0,0 +1,281 @@
+package orm
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	d3entity "github.com/godzie44/d3/orm/entity"
+	"github.com/godzie44/d3/orm/query"
+	"reflect"
+	"strings"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// SortField - field of paginator sort specification.
+type SortField struct {
+	// Field - name of entity field.
+	Field string
+	Desc  bool
+}
+
+// Asc - sort by entity field in ascending order.
+func Asc(field string) SortField {
+	return SortField{Field: field}
+}
+
+// Desc - sort by entity field in descending order.
+func Desc(field string) SortField {
+	return SortField{Field: field, Desc: true}
+}
+
+// Page - page of entities returned by Paginator.
+type Page struct {
+	Entities *d3entity.Collection
+	// NextCursor - cursor of next page, empty if there is no next page.
+	NextCursor string
+	// PrevCursor - cursor of previous page, empty if there is no previous page.
+	PrevCursor string
+}
+
+// Paginator - keyset (cursor) pagination over entities of repository. Instead of OFFSET paginator remember sort field values
+// of last (or first) entity of page in cursor and fetch next page with comparison, for example: WHERE (created_at, id) > ($1, $2).
+type Paginator struct {
+	repository *Repository
+	limit      int
+	sort       []sortField
+}
+
+type sortField struct {
+	field *d3entity.FieldInfo
+	desc  bool
+}
+
+const (
+	cursorNext = "n"
+	cursorPrev = "p"
+)
+
+type cursor struct {
+	Direction string            `json:"d"`
+	Values    []json.RawMessage `json:"v"`
+}
+
+// MakePaginator - create paginator with pages of limit size, sorted by sort fields.
+// Primary key fields appended to sort specification if not present, so order of entities is always deterministic.
+// Sort fields must be not nullable.
+func (r *Repository) MakePaginator(limit int, sort ...SortField) (*Paginator, error) {
+	if limit <= 0 {
+		return nil, fmt.Errorf("paginator limit must be positive")
+	}
+
+	p := &Paginator{repository: r, limit: limit}
+
+	var lastDesc bool
+	for _, s := range sort {
+		field, exists := r.entityMeta.Fields[s.Field]
+		if !exists {
+			return nil, fmt.Errorf("paginator: unknown field %s", s.Field)
+		}
+		p.sort = append(p.sort, sortField{field: field, desc: s.Desc})
+		lastDesc = s.Desc
+	}
+
+	for _, pkField := range r.entityMeta.Pk.Fields {
+		if !p.hasField(pkField) {
+			p.sort = append(p.sort, sortField{field: pkField, desc: lastDesc})
+		}
+	}
+
+	return p, nil
+}
+
+func (p *Paginator) hasField(field *d3entity.FieldInfo) bool {
+	for _, s := range p.sort {
+		if s.field.Name == field.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// Page - fetch page of entities matched by query. Empty cursor means first page.
+// Query modified by paginator: sort condition, ORDER BY and LIMIT clauses are added to it.
+func (p *Paginator) Page(ctx context.Context, q *query.Query, cursorStr string) (*Page, error) {
+	backward := false
+	if cursorStr != "" {
+		c, values, err := p.decodeCursor(cursorStr)
+		if err != nil {
+			return nil, err
+		}
+		backward = c.Direction == cursorPrev
+		p.addCondition(q, values, backward)
+	}
+
+	q.OrderBy(p.orderBy(backward)...).Limit(p.limit + 1)
+
+	coll, err := p.repository.FindAll(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	entities := coll.ToSlice()
+	hasMore := len(entities) > p.limit
+	if hasMore {
+		entities = entities[:p.limit]
+	}
+	if backward {
+		for i, j := 0, len(entities)-1; i < j; i, j = i+1, j-1 {
+			entities[i], entities[j] = entities[j], entities[i]
+		}
+	}
+
+	page := &Page{Entities: d3entity.NewCollection(entities...)}
+	if len(entities) == 0 {
+		return page, nil
+	}
+
+	hasNext, hasPrev := hasMore, cursorStr != ""
+	if backward {
+		hasNext, hasPrev = true, hasMore
+	}
+
+	if hasNext {
+		if page.NextCursor, err = p.encodeCursor(cursorNext, entities[len(entities)-1]); err != nil {
+			return nil, err
+		}
+	}
+	if hasPrev {
+		if page.PrevCursor, err = p.encodeCursor(cursorPrev, entities[0]); err != nil {
+			return nil, err
+		}
+	}
+
+	return page, nil
+}
+
+func (p *Paginator) orderBy(backward bool) []string {
+	stmts := make([]string, len(p.sort))
+	for i, s := range p.sort {
+		if s.desc != backward {
+			stmts[i] = s.field.FullDbAlias + " DESC"
+		} else {
+			stmts[i] = s.field.FullDbAlias + " ASC"
+		}
+	}
+	return stmts
+}
+
+func (p *Paginator) comparison(s sortField, backward bool) string {
+	if s.desc != backward {
+		return "<"
+	}
+	return ">"
+}
+
+// addCondition - add condition which select entities after (or before if backward) cursor values.
+// If all fields sorted in the same direction tuple comparison used: (a, b) > (?, ?),
+// otherwise expanded form: a > ? OR (a = ? AND b < ?).
+func (p *Paginator) addCondition(q *query.Query, values []interface{}, backward bool) {
+	sameDirection := true
+	for _, s := range p.sort {
+		sameDirection = sameDirection && s.desc == p.sort[0].desc
+	}
+
+	if sameDirection {
+		columns := make([]string, len(p.sort))
+		for i, s := range p.sort {
+			columns[i] = s.field.FullDbAlias
+		}
+		q.AndWhere("("+strings.Join(columns, ", ")+")", p.comparison(p.sort[0], backward), values...)
+		return
+	}
+
+	q.AndNestedWhere(func(q *query.Query) {
+		for i := range p.sort {
+			i := i
+			q.OrNestedWhere(func(q *query.Query) {
+				for j := 0; j < i; j++ {
+					q.AndWhere(p.sort[j].field.FullDbAlias, "=", values[j])
+				}
+				q.AndWhere(p.sort[i].field.FullDbAlias, p.comparison(p.sort[i], backward), values[i])
+			})
+		}
+	})
+}
+
+func (p *Paginator) encodeCursor(direction string, entity interface{}) (string, error) {
+	c := cursor{Direction: direction, Values: make([]json.RawMessage, len(p.sort))}
+	for i, s := range p.sort {
+		val, err := p.repository.entityMeta.Tools.ExtractField(entity, s.field.Name)
+		if err != nil {
+			return "", err
+		}
+
+		if c.Values[i], err = json.Marshal(val); err != nil {
+			return "", fmt.Errorf("encode cursor: %w", err)
+		}
+	}
+
+	payload, err := json.Marshal(c)
+	if err != nil {
+		return "", fmt.Errorf("encode cursor: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(p.sign(payload)), nil
+}
+
+func (p *Paginator) decodeCursor(cursorStr string) (*cursor, []interface{}, error) {
+	parts := strings.Split(cursorStr, ".")
+	if len(parts) != 2 {
+		return nil, nil, ErrInvalidCursor
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, nil, ErrInvalidCursor
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || !hmac.Equal(signature, p.sign(payload)) {
+		return nil, nil, ErrInvalidCursor
+	}
+
+	c := &cursor{}
+	if err := json.Unmarshal(payload, c); err != nil || len(c.Values) != len(p.sort) {
+		return nil, nil, ErrInvalidCursor
+	}
+	if c.Direction != cursorNext && c.Direction != cursorPrev {
+		return nil, nil, ErrInvalidCursor
+	}
+
+	values := make([]interface{}, len(p.sort))
+	for i, s := range p.sort {
+		val := reflect.New(s.field.AssociatedType)
+		if err := json.Unmarshal(c.Values[i], val.Interface()); err != nil {
+			return nil, nil, ErrInvalidCursor
+		}
+		values[i] = val.Elem().Interface()
+	}
+
+	return c, values, nil
+}
+
+// sign - sign cursor payload with key of repository, also sort specification signed,
+// so cursor of one paginator can't be used with another.
+func (p *Paginator) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, p.repository.cursorKey)
+	for _, s := range p.sort {
+		mac.Write([]byte(s.field.FullDbAlias))
+		if s.desc {
+			mac.Write([]byte(" DESC,"))
+		} else {
+			mac.Write([]byte(" ASC,"))
+		}
+	}
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
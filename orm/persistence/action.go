@@ -1,6 +1,7 @@
 package persistence
 
 import (
+	"context"
 	"fmt"
 	"github.com/godzie44/d3/orm/entity"
 )
@@ -23,7 +24,7 @@ type (
 
 	executableAction interface {
 		wasExecuted() bool
-		exec(pusher Pusher) error
+		exec(ctx context.Context, pusher Pusher) error
 	}
 
 	waiter interface {
@@ -60,9 +61,58 @@ type baseAction struct {
 	subscriptions []executableAction
 
 	childrenActions []CompositeAction
+
+	// entityFields - names of entity fields by columns, values of this columns set into entity after action executed.
+	entityFields map[string]string
+	// applied - changes of entity fields made after action executed.
+	applied []FieldChange
+}
+
+// FieldChange - value of entity field before action set new value into it.
+type FieldChange struct {
+	Field string
+	Prev  interface{}
+}
+
+// AppliedChanges - return changes of entity fields (version, timestamps, deletion time) made after action executed,
+// they must be reverted if transaction rolled back.
+func (b *baseAction) AppliedChanges() []FieldChange {
+	return b.applied
+}
+
+// setEntityField - set column value and remember that value must be set into entity field after action executed.
+func (b *baseAction) setEntityField(column, field string, val interface{}) {
+	if b.entityFields == nil {
+		b.entityFields = make(map[string]string)
+	}
+	b.entityFields[column] = field
+	b.Values[column] = val
+}
+
+// applyEntityFields - set values of entity fields registered by setEntityField into entity.
+func (b *baseAction) applyEntityFields(box *persistBox) error {
+	for column, field := range b.entityFields {
+		if err := b.applyField(box, field, b.Values[column]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyField - set field value into entity and remember previous value.
+func (b *baseAction) applyField(box *persistBox, field string, val interface{}) error {
+	prev, err := box.Meta.Tools.ExtractField(box.Entity, field)
+	if err != nil {
+		return err
+	}
+	if err := box.Meta.Tools.SetFieldVal(box.Entity, field, val); err != nil {
+		return err
+	}
+	b.applied = append(b.applied, FieldChange{Field: field, Prev: prev})
+	return nil
 }
 
-func (b *baseAction) exec(_ Pusher) error {
+func (b *baseAction) exec(_ context.Context, _ Pusher) error {
 	b.executed = true
 	return nil
 }
@@ -205,7 +255,7 @@ func NewInsertAction(pkHydrator func([]interface{}) error, box *persistBox) *Ins
 
 	if box != nil {
 		action.pkGenStrategy = box.Meta.Pk.Strategy
-		action.pkCols = []string{box.Meta.Pk.Field.DbAlias}
+		action.pkCols = box.Meta.Pk.DbAliases()
 	}
 
 	return action
@@ -218,7 +268,7 @@ func (i *InsertAction) Box() *entity.Box {
 	return i.box.Box
 }
 
-func (i *InsertAction) exec(pusher Pusher) error {
+func (i *InsertAction) exec(ctx context.Context, pusher Pusher) error {
 	if i.pkGenStrategy == entity.Auto {
 		for _, pkCol := range i.pkCols {
 			delete(i.Values, pkCol)
@@ -233,7 +283,7 @@ func (i *InsertAction) exec(pusher Pusher) error {
 
 	if i.pkHydrateFn != nil && i.pkGenStrategy == entity.Auto {
 		tpl := i.box.Meta.CreateKeyTpl()
-		err := pusher.InsertWithReturn(i.TableName, columns, values, i.pkCols, func(scanner Scanner) error {
+		err := pusher.InsertWithReturn(ctx, i.TableName, columns, values, i.pkCols, func(scanner Scanner) error {
 			return scanner.Scan(tpl.Projection()...)
 		})
 		if err != nil {
@@ -244,13 +294,19 @@ func (i *InsertAction) exec(pusher Pusher) error {
 			return err
 		}
 	} else {
-		err := pusher.Insert(i.TableName, columns, values, i.onConflict)
+		err := pusher.Insert(ctx, i.TableName, columns, values, i.onConflict)
 		if err != nil {
 			return err
 		}
 	}
 
-	return i.baseAction.exec(pusher)
+	if i.box != nil {
+		if err := i.applyEntityFields(i.box); err != nil {
+			return fmt.Errorf("insert execution failed: %w", err)
+		}
+	}
+
+	return i.baseAction.exec(ctx, pusher)
 }
 
 func (i *InsertAction) equalTo(agr CompositeAction) bool {
@@ -270,21 +326,37 @@ func (i *InsertAction) equalTo(agr CompositeAction) bool {
 type UpdateAction struct {
 	baseAction
 	identityCondition map[string]interface{}
+
+	box *persistBox
+	// softDelete - true if action set deletion time of soft deleted entity.
+	softDelete bool
 }
 
 func NewUpdateAction(identityCondition map[string]interface{}) *UpdateAction {
 	return &UpdateAction{identityCondition: identityCondition, baseAction: baseAction{Values: make(map[string]interface{})}}
 }
 
-func (u *UpdateAction) exec(pusher Pusher) error {
+// Box - return updated entity, nil if action update not an entity itself (like join columns of related entities).
+func (u *UpdateAction) Box() *entity.Box {
+	if u.box == nil {
+		return nil
+	}
+	return u.box.Box
+}
+
+// IsSoftDelete - return true if action soft deletes entity instead of update.
+func (u *UpdateAction) IsSoftDelete() bool {
+	return u.softDelete
+}
+
+func (u *UpdateAction) exec(ctx context.Context, pusher Pusher) error {
 	if len(u.Values) == 0 {
-		return u.baseAction.exec(pusher)
+		return u.baseAction.exec(ctx, pusher)
 	}
 
 	if err := u.prepareValues(); err != nil {
 		return fmt.Errorf("update execution failed: %w", err)
 	}
-	columns, values := u.splitValues()
 
 	for col, val := range u.identityCondition {
 		if valPromise, ok := val.(*promise); ok {
@@ -296,11 +368,52 @@ func (u *UpdateAction) exec(pusher Pusher) error {
 		}
 	}
 
-	if err := pusher.Update(u.TableName, columns, values, u.identityCondition); err != nil {
+	var currVersion, nextVersion interface{}
+	versionField := u.versionField()
+	if versionField != nil {
+		var err error
+		if currVersion, err = u.box.Meta.Tools.ExtractField(u.box.Entity, versionField.Name); err != nil {
+			return fmt.Errorf("update execution failed: %w", err)
+		}
+		if nextVersion, err = incrementVersion(currVersion); err != nil {
+			return fmt.Errorf("update execution failed: %w", err)
+		}
+
+		u.identityCondition[versionField.DbAlias] = currVersion
+		u.Values[versionField.DbAlias] = nextVersion
+	}
+
+	columns, values := u.splitValues()
+
+	affected, err := pusher.Update(ctx, u.TableName, columns, values, u.identityCondition)
+	if err != nil {
 		return err
 	}
 
-	return u.baseAction.exec(pusher)
+	if versionField != nil {
+		if affected == 0 {
+			return &OptimisticLockError{Entity: u.box.Meta.EntityName, Version: currVersion}
+		}
+
+		if err := u.applyField(u.box, versionField.Name, nextVersion); err != nil {
+			return fmt.Errorf("update execution failed: %w", err)
+		}
+	}
+
+	if u.box != nil {
+		if err := u.applyEntityFields(u.box); err != nil {
+			return fmt.Errorf("update execution failed: %w", err)
+		}
+	}
+
+	return u.baseAction.exec(ctx, pusher)
+}
+
+func (u *UpdateAction) versionField() *entity.FieldInfo {
+	if u.box == nil {
+		return nil
+	}
+	return u.box.Meta.Version
 }
 
 func (u *UpdateAction) equalTo(act CompositeAction) bool {
@@ -316,12 +429,22 @@ func (u *UpdateAction) equalTo(act CompositeAction) bool {
 type DeleteAction struct {
 	baseAction
 	deleteCondition map[string]interface{}
+
+	box *persistBox
 }
 
 func NewDeleteAction(deleteCondition map[string]interface{}) *DeleteAction {
 	return &DeleteAction{deleteCondition: deleteCondition}
 }
 
+// Box - return deleted entity, nil if action delete not an entity (like rows of join table).
+func (d *DeleteAction) Box() *entity.Box {
+	if d.box == nil {
+		return nil
+	}
+	return d.box.Box
+}
+
 func (d *DeleteAction) equalTo(act CompositeAction) bool {
 	if action, ok := act.(*DeleteAction); ok {
 		if action.TableName == d.TableName && mapEquals(d.deleteCondition, action.deleteCondition) {
@@ -332,11 +455,11 @@ func (d *DeleteAction) equalTo(act CompositeAction) bool {
 	return false
 }
 
-func (d *DeleteAction) exec(pusher Pusher) error {
-	err := pusher.Remove(d.TableName, d.deleteCondition)
+func (d *DeleteAction) exec(ctx context.Context, pusher Pusher) error {
+	err := pusher.Remove(ctx, d.TableName, d.deleteCondition)
 	if err != nil {
 		return err
 	}
 
-	return d.baseAction.exec(pusher)
+	return d.baseAction.exec(ctx, pusher)
 }
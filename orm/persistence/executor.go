@@ -1,5 +1,7 @@
 package persistence
 
+import "context"
+
 type executor struct {
 	storage     Pusher
 	afterExecFn func(act CompositeAction)
@@ -9,14 +11,14 @@ func NewExecutor(storage Pusher, afterExec func(act CompositeAction)) *executor
 	return &executor{storage, afterExec}
 }
 
-func (e *executor) Exec(graph *PersistGraph) error {
+func (e *executor) Exec(ctx context.Context, graph *PersistGraph) error {
 	nodes := graph.filterRoots()
 
 	var allNodesExecuted bool
 	for !allNodesExecuted {
 		allNodesExecuted = true
 		for _, startNode := range nodes {
-			if execRes, err := e.execNode(startNode); err != nil {
+			if execRes, err := e.execNode(ctx, startNode); err != nil {
 				return err
 			} else {
 				allNodesExecuted = execRes && allNodesExecuted
@@ -27,17 +29,17 @@ func (e *executor) Exec(graph *PersistGraph) error {
 	return nil
 }
 
-func (e *executor) execNode(node CompositeAction) (bool, error) {
+func (e *executor) execNode(ctx context.Context, node CompositeAction) (bool, error) {
 	var result bool
 	if result = node.subscriptionsResolved(); result && !node.wasExecuted() {
-		if err := node.exec(e.storage); err != nil {
+		if err := node.exec(ctx, e.storage); err != nil {
 			return false, err
 		}
 		e.afterExecFn(node)
 	}
 
 	for _, childNode := range node.children() {
-		if execRes, err := e.execNode(childNode); err != nil {
+		if execRes, err := e.execNode(ctx, childNode); err != nil {
 			return false, err
 		} else {
 			result = result && execRes
@@ -1,6 +1,7 @@
 package persistence
 
 import (
+	"context"
 	"github.com/godzie44/d3/orm/entity"
 	"github.com/stretchr/testify/assert"
 	"reflect"
@@ -29,7 +30,7 @@ type queryStub struct {
 	values    map[string]interface{}
 }
 
-func (s *pusherStub) Insert(table string, cols []string, values []interface{}, _ OnConflict) error {
+func (s *pusherStub) Insert(_ context.Context, table string, cols []string, values []interface{}, _ OnConflict) error {
 	qValues := map[string]interface{}{}
 	for i, val := range values {
 		if fn, ok := val.(func() (interface{}, error)); ok {
@@ -48,7 +49,7 @@ func (s *pusherStub) Insert(table string, cols []string, values []interface{}, _
 	return nil
 }
 
-func (s *pusherStub) InsertWithReturn(table string, cols []string, values []interface{}, _ []string, withReturned func(scanner Scanner) error) error {
+func (s *pusherStub) InsertWithReturn(_ context.Context, table string, cols []string, values []interface{}, _ []string, withReturned func(scanner Scanner) error) error {
 	qValues := map[string]interface{}{}
 	for i, val := range values {
 		if fn, ok := val.(func() (interface{}, error)); ok {
@@ -67,11 +68,11 @@ func (s *pusherStub) InsertWithReturn(table string, cols []string, values []inte
 	return withReturned(&scannerStub{ret: []interface{}{values[0]}})
 }
 
-func (s *pusherStub) Update(_ string, _ []string, _ []interface{}, _ map[string]interface{}) error {
-	return nil
+func (s *pusherStub) Update(_ context.Context, _ string, _ []string, _ []interface{}, _ map[string]interface{}) (int64, error) {
+	return 1, nil
 }
 
-func (s *pusherStub) Remove(_ string, _ map[string]interface{}) error {
+func (s *pusherStub) Remove(_ context.Context, _ string, _ map[string]interface{}) error {
 	return nil
 }
 
@@ -109,7 +110,7 @@ func TestExecuteSimpleGraph(t *testing.T) {
 
 	_ = graph.ProcessEntity(entity.NewBox(shop, &meta))
 
-	err = executor.Exec(graph)
+	err = executor.Exec(context.Background(), graph)
 	assert.NoError(t, err)
 
 	testPusher.assertQueryAfter(t, queryStub{
@@ -163,7 +164,7 @@ func TestExecuteComplexGraph(t *testing.T) {
 
 	testStorage := &pusherStub{}
 	executor := NewExecutor(testStorage, func(act CompositeAction) {})
-	err := executor.Exec(graph)
+	err := executor.Exec(context.Background(), graph)
 	assert.NoError(t, err)
 
 	testStorage.assertQueryAfter(t, queryStub{tableName: "shop", values: map[string]interface{}{"id": 1, "profile_id": 1}},
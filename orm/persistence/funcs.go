@@ -1,5 +1,7 @@
 package persistence
 
+import "fmt"
+
 func revertIntoMap(slice []interface{}) map[interface{}]struct{} {
 	result := make(map[interface{}]struct{}, len(slice))
 	for _, e := range slice {
@@ -50,3 +52,16 @@ func mapEquals(m1, m2 map[string]interface{}) bool {
 	}
 	return true
 }
+
+func incrementVersion(version interface{}) (interface{}, error) {
+	switch v := version.(type) {
+	case int:
+		return v + 1, nil
+	case int32:
+		return v + 1, nil
+	case int64:
+		return v + 1, nil
+	default:
+		return nil, fmt.Errorf("unsupported version type %T", version)
+	}
+}
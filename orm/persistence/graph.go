@@ -1,9 +1,11 @@
 package persistence
 
 import (
+	"database/sql"
 	"fmt"
 	d3entity "github.com/godzie44/d3/orm/entity"
 	"math"
+	"time"
 )
 
 type state int
@@ -69,25 +71,36 @@ func (s *state) toProcessed() {
 
 type persistBox struct {
 	*d3entity.Box
-	entityPk  interface{}
+	pkValues  []interface{}
 	action    CompositeAction
 	currState state
 	original  interface{}
 }
 
 func newPersistBox(b *d3entity.Box, original interface{}) (*persistBox, error) {
-	pk, err := b.ExtractPk()
+	pkValues, err := b.Meta.ExtractPkValues(b.Entity)
 	if err != nil {
 		return nil, err
 	}
 
-	return &persistBox{Box: b, currState: create, entityPk: pk, original: original}, nil
+	return &persistBox{Box: b, currState: create, pkValues: pkValues, original: original}, nil
+}
+
+func (p *persistBox) pkChanged() bool {
+	for _, f := range p.Meta.Pk.Fields {
+		if !p.Meta.Tools.CompareFields(p.original, p.Entity, f.Name) {
+			return true
+		}
+	}
+	return false
 }
 
 func (p *persistBox) makeAction() CompositeAction {
 	switch {
 	case p.currState.isUpdate():
-		return makeUpdateAction(p)
+		a := makeUpdateAction(p)
+		a.box = p
+		return a
 	case p.currState.isCreate():
 		return makeInsertAction(p)
 	default:
@@ -96,15 +109,24 @@ func (p *persistBox) makeAction() CompositeAction {
 }
 
 func makeUpdateAction(box *persistBox) *UpdateAction {
-	a := NewUpdateAction(map[string]interface{}{box.Meta.Pk.Field.Name: createIDPromise(box)})
+	identityCond := make(map[string]interface{}, len(box.Meta.Pk.Fields))
+	for _, f := range box.Meta.Pk.Fields {
+		identityCond[f.DbAlias] = createIDPromise(box, f)
+	}
+
+	a := NewUpdateAction(identityCond)
 	a.setTableName(box.Meta.TableName)
 	return a
 }
 
 func makeInsertAction(box *persistBox) *InsertAction {
 	a := NewInsertAction(func(pk []interface{}) error {
-		//currently only 1 pk
-		return box.Meta.Tools.SetFieldVal(box.Entity, box.Meta.Pk.Field.Name, pk[0])
+		for i, f := range box.Meta.Pk.Fields {
+			if err := box.Meta.Tools.SetFieldVal(box.Entity, f.Name, pk[i]); err != nil {
+				return err
+			}
+		}
+		return nil
 	}, box)
 	a.setTableName(box.Meta.TableName)
 	return a
@@ -152,6 +174,7 @@ func (p *pBoxContainer) flattActions() []CompositeAction {
 //PersistGraph graph of database actions derived from entities.
 type PersistGraph struct {
 	knownBoxes *pBoxContainer
+	clock      func() time.Time
 }
 
 //NewPersistGraph create new graph.
@@ -161,9 +184,15 @@ func NewPersistGraph(
 ) *PersistGraph {
 	return &PersistGraph{
 		knownBoxes: &pBoxContainer{boxes: map[interface{}]*persistBox{}, stateInitializer: checkInDirty, originalFactory: originalFactory},
+		clock:      time.Now,
 	}
 }
 
+// SetClock - set source of current time for timestamps and soft delete, time.Now used by default.
+func (p *PersistGraph) SetClock(clock func() time.Time) {
+	p.clock = clock
+}
+
 //ProcessEntity process entity and all related entities into database actions.
 func (p *PersistGraph) ProcessEntity(box *d3entity.Box) error {
 	pb, err := p.knownBoxes.get(box)
@@ -191,6 +220,10 @@ func (p *PersistGraph) processBox(box *persistBox) error {
 
 	box.action.setFields(extractedFields...)
 
+	if err := p.touchTimestamps(box, len(extractedFields) != 0); err != nil {
+		return err
+	}
+
 	for _, rel := range box.Meta.OneToOneRelations() {
 		if err := p.persistOneToOneRel(box, rel); err != nil {
 			return err
@@ -235,23 +268,28 @@ func (p *PersistGraph) persistOneToOneRel(ownerBox *persistBox, relation *d3enti
 		return nil
 	case relatedEntity.IsNil():
 		// if new relation is nil then delete relation
-		ownerBox.action.mergeFields(ActionField(relation.JoinColumn, nil))
+		ownerBox.action.mergeFields(nullActionFields(relation.JoinColumns())...)
 	default:
 		relatedBox, err := p.knownBoxes.getRaw(relatedEntity.Unwrap(), ownerBox.GetRelatedMeta(relation.RelatedWith()))
 		if err != nil {
 			return err
 		}
 
+		fields, err := pkActionFields(relation.JoinColumns(), relatedBox)
+		if err != nil {
+			return err
+		}
+
 		//split here, cycle detected
 		if relatedBox.currState.isProcessed() || relatedBox.currState.isInProcess() {
-			doSplit(ownerBox.action, relatedBox.action, ownerBox, relation.JoinColumn, createIDPromise(relatedBox))
+			doSplit(ownerBox.action, relatedBox.action, ownerBox, fields...)
 		} else {
 			if err := p.processBox(relatedBox); err != nil {
 				return err
 			}
 			relatedBox.action.addChild(ownerBox.action)
-			if !relatedBox.Meta.Tools.CompareFields(relatedBox.original, relatedBox.Entity, relatedBox.Meta.Pk.Field.Name) {
-				ownerBox.action.mergeFields(ActionField(relation.JoinColumn, createIDPromise(relatedBox)))
+			if relatedBox.pkChanged() {
+				ownerBox.action.mergeFields(fields...)
 			}
 		}
 	}
@@ -282,28 +320,36 @@ func (p *PersistGraph) persistOneToManyRel(ownerBox *persistBox, relation *d3ent
 			return err
 		}
 
+		fields, err := pkActionFields(relation.JoinColumns(), ownerBox)
+		if err != nil {
+			return err
+		}
+
 		//split here, cycle detected
 		if relatedBox.currState.isProcessed() || relatedBox.currState.isInProcess() {
-			doSplit(relatedBox.action, ownerBox.action, relatedBox, relation.JoinColumn, createIDPromise(ownerBox))
+			doSplit(relatedBox.action, ownerBox.action, relatedBox, fields...)
 		} else {
 			if err := p.processBox(relatedBox); err != nil {
 				return err
 			}
 			ownerBox.action.addChild(relatedBox.action)
-			relatedBox.action.mergeFields(ActionField(relation.JoinColumn, createIDPromise(ownerBox)))
+			relatedBox.action.mergeFields(fields...)
 		}
 	}
 
 	for _, origRelatedEntity := range mapKeyDiff(origRelatedEntities, relatedEntities) {
-		updPk, err := relatedMeta.ExtractPkValue(origRelatedEntity)
+		updPk, err := relatedMeta.ExtractPkValues(origRelatedEntity)
 		if err != nil {
 			return err
 		}
 
-		updAction := NewUpdateAction(map[string]interface{}{
-			relatedMeta.Pk.Field.Name: updPk,
-		})
-		updAction.setFields(ActionField(relation.JoinColumn, nil))
+		cond, err := pkCondition(relatedMeta.Pk.DbAliases(), updPk)
+		if err != nil {
+			return err
+		}
+
+		updAction := NewUpdateAction(cond)
+		updAction.setFields(nullActionFields(relation.JoinColumns())...)
 		updAction.setTableName(relatedMeta.TableName)
 
 		ownerBox.action.addChild(updAction)
@@ -339,12 +385,19 @@ func (p *PersistGraph) persistManyToManyRel(ownerBox *persistBox, relation *d3en
 			return err
 		}
 
+		ownerFields, err := pkActionFields(relation.JoinColumns(), ownerBox)
+		if err != nil {
+			return err
+		}
+		relatedFields, err := pkActionFields(relation.ReferenceColumns(), relatedBox)
+		if err != nil {
+			return err
+		}
+
 		linkTableInsertAction := NewInsertAction(nil, nil)
 		linkTableInsertAction.setTableName(relation.JoinTable)
-		linkTableInsertAction.setFields(
-			ActionField(relation.JoinColumn, createIDPromise(ownerBox)),
-			ActionField(relation.ReferenceColumn, createIDPromise(relatedBox)),
-		)
+		linkTableInsertAction.setFields(ownerFields...)
+		linkTableInsertAction.setFields(relatedFields...)
 		linkTableInsertAction.onConflict = DoNothing
 
 		if !relatedBox.action.hasChild(linkTableInsertAction) && !ownerBox.action.hasChild(linkTableInsertAction) {
@@ -353,21 +406,30 @@ func (p *PersistGraph) persistManyToManyRel(ownerBox *persistBox, relation *d3en
 		}
 	}
 
-	pk, err := ownerBox.ExtractPk()
+	pk, err := ownerBox.Meta.ExtractPkValues(ownerBox.Entity)
 	if err != nil {
 		return err
 	}
 
 	for _, origRelatedEntity := range mapKeyDiff(origRelatedEntities, relatedEntities) {
-		relPk, err := relatedMeta.ExtractPkValue(origRelatedEntity)
+		relPk, err := relatedMeta.ExtractPkValues(origRelatedEntity)
+		if err != nil {
+			return err
+		}
+
+		delCondition, err := pkCondition(relation.JoinColumns(), pk)
 		if err != nil {
 			return err
 		}
+		relCondition, err := pkCondition(relation.ReferenceColumns(), relPk)
+		if err != nil {
+			return err
+		}
+		for col, val := range relCondition {
+			delCondition[col] = val
+		}
 
-		delAction := NewDeleteAction(map[string]interface{}{
-			relation.ReferenceColumn: relPk,
-			relation.JoinColumn:      pk,
-		})
+		delAction := NewDeleteAction(delCondition)
 		delAction.setTableName(relation.JoinTable)
 
 		ownerBox.action.addChild(delAction)
@@ -376,6 +438,43 @@ func (p *PersistGraph) persistManyToManyRel(ownerBox *persistBox, relation *d3en
 	return nil
 }
 
+// touchTimestamps - set creation and update time of inserted entity (if not set yet)
+// and update time of updated entity with changed fields into action, entity fields set after action executed.
+func (p *PersistGraph) touchTimestamps(box *persistBox, changed bool) error {
+	var fields []*d3entity.FieldInfo
+	var action *baseAction
+	switch a := box.action.(type) {
+	case *InsertAction:
+		action = &a.baseAction
+		for _, field := range []*d3entity.FieldInfo{box.Meta.CreatedAt, box.Meta.UpdatedAt} {
+			if field == nil {
+				continue
+			}
+
+			val, err := box.Meta.Tools.ExtractField(box.Entity, field.Name)
+			if err != nil {
+				return err
+			}
+
+			if val.(time.Time).IsZero() {
+				fields = append(fields, field)
+			}
+		}
+	case *UpdateAction:
+		action = &a.baseAction
+		if changed && box.Meta.UpdatedAt != nil {
+			fields = append(fields, box.Meta.UpdatedAt)
+		}
+	}
+
+	now := p.clock()
+	for _, field := range fields {
+		action.setEntityField(field.DbAlias, field.Name, now)
+	}
+
+	return nil
+}
+
 func extractSimpleFields(box *persistBox) ([]*actionField, error) {
 	fields := make([]*actionField, 0, len(box.Meta.Fields))
 	for _, field := range box.Meta.Fields {
@@ -394,6 +493,82 @@ func extractSimpleFields(box *persistBox) ([]*actionField, error) {
 	return fields, nil
 }
 
+// InsertedEntities - return entities which will be inserted on graph execution.
+func (p *PersistGraph) InsertedEntities() []*d3entity.Box {
+	var result []*d3entity.Box
+	for _, pb := range p.knownBoxes.boxes {
+		if act, ok := pb.action.(*InsertAction); ok && act.box == pb {
+			result = append(result, pb.Box)
+		}
+	}
+	return result
+}
+
+// UpdatedEntities - return entities with changed fields, which will be updated on graph execution.
+func (p *PersistGraph) UpdatedEntities() []*d3entity.Box {
+	var result []*d3entity.Box
+	for _, pb := range p.knownBoxes.boxes {
+		if act, ok := pb.action.(*UpdateAction); ok && act.box == pb && !act.softDelete && len(act.Values) > 0 {
+			result = append(result, pb.Box)
+		}
+	}
+	return result
+}
+
+// DeletedEntities - return entities which will be deleted on graph execution.
+func (p *PersistGraph) DeletedEntities() []*d3entity.Box {
+	var result []*d3entity.Box
+	for _, pb := range p.knownBoxes.boxes {
+		switch act := pb.action.(type) {
+		case *DeleteAction:
+			if act.box == pb {
+				result = append(result, pb.Box)
+			}
+		case *UpdateAction:
+			if act.box == pb && act.softDelete {
+				result = append(result, pb.Box)
+			}
+		}
+	}
+	return result
+}
+
+// HasChanges - return true if graph execution will change storage (graph contains not empty actions).
+func (p *PersistGraph) HasChanges() bool {
+	visited := make(map[CompositeAction]bool)
+
+	var hasChanges func(act CompositeAction) bool
+	hasChanges = func(act CompositeAction) bool {
+		if visited[act] {
+			return false
+		}
+		visited[act] = true
+
+		switch a := act.(type) {
+		case *InsertAction, *DeleteAction:
+			return true
+		case *UpdateAction:
+			if len(a.Values) != 0 {
+				return true
+			}
+		}
+
+		for _, child := range act.children() {
+			if hasChanges(child) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, act := range p.knownBoxes.flattActions() {
+		if hasChanges(act) {
+			return true
+		}
+	}
+	return false
+}
+
 func (p *PersistGraph) filterRoots() []CompositeAction {
 	actions := p.knownBoxes.flattActions()
 
@@ -426,16 +601,34 @@ func (p *PersistGraph) filterRoots() []CompositeAction {
 	return result
 }
 
+// ProcessDeletedEntity - process entity and related entities (if cascade delete) into database actions.
+// Entity with soft delete field will not be deleted physically, only time of deletion will be set.
+// Delete mode chosen for every entity independently, so cascade delete of related entities respect their own mode.
+// Relation rows of soft deleted entity stay untouched, cause entity still exists in database.
 func (p *PersistGraph) ProcessDeletedEntity(box *d3entity.Box) error {
 	pb, err := p.knownBoxes.get(box)
 	if err != nil {
 		return err
 	}
 
-	pb.action = NewDeleteAction(map[string]interface{}{
-		pb.Meta.Pk.FullDbAlias(): pb.entityPk,
-	})
-	pb.action.setTableName(pb.Meta.TableName)
+	soft := pb.Meta.SoftDelete != nil
+	if soft {
+		cond, err := pkCondition(pb.Meta.Pk.DbAliases(), pb.pkValues)
+		if err != nil {
+			return err
+		}
+		pb.action = makeSoftDeleteAction(pb, cond, p.clock())
+	} else {
+		cond, err := pkCondition(pb.Meta.Pk.FullDbAliases(), pb.pkValues)
+		if err != nil {
+			return err
+		}
+
+		delAction := NewDeleteAction(cond)
+		delAction.setTableName(pb.Meta.TableName)
+		delAction.box = pb
+		pb.action = delAction
+	}
 
 	for _, rel := range pb.Meta.OneToOneRelations() {
 		if err := p.deleteOneToOneRel(pb, rel); err != nil {
@@ -444,13 +637,13 @@ func (p *PersistGraph) ProcessDeletedEntity(box *d3entity.Box) error {
 	}
 
 	for _, rel := range pb.Meta.OneToManyRelations() {
-		if err := p.deleteOneToManyRel(pb, rel); err != nil {
+		if err := p.deleteOneToManyRel(pb, rel, soft); err != nil {
 			return err
 		}
 	}
 
 	for _, rel := range pb.Meta.ManyToManyRelations() {
-		if err := p.deleteManyToManyRel(pb, rel); err != nil {
+		if err := p.deleteManyToManyRel(pb, rel, soft); err != nil {
 			return err
 		}
 	}
@@ -458,6 +651,17 @@ func (p *PersistGraph) ProcessDeletedEntity(box *d3entity.Box) error {
 	return nil
 }
 
+// makeSoftDeleteAction - create action which set deletion time of entity, version of versioned entity
+// checked and incremented as in any other entity update (see UpdateAction.exec).
+func makeSoftDeleteAction(box *persistBox, identityCondition map[string]interface{}, now time.Time) *UpdateAction {
+	a := NewUpdateAction(identityCondition)
+	a.setTableName(box.Meta.TableName)
+	a.setEntityField(box.Meta.SoftDelete.DbAlias, box.Meta.SoftDelete.Name, sql.NullTime{Time: now, Valid: true})
+	a.box = box
+	a.softDelete = true
+	return a
+}
+
 func (p *PersistGraph) deleteOneToOneRel(ownerBox *persistBox, relation *d3entity.OneToOne) error {
 	switch relation.DeleteStrategy() {
 	case d3entity.None, d3entity.Nullable:
@@ -472,23 +676,44 @@ func (p *PersistGraph) deleteOneToOneRel(ownerBox *persistBox, relation *d3entit
 			return nil
 		}
 
-		return p.ProcessDeletedEntity(d3entity.NewBox(relatedEntity.Unwrap(), ownerBox.GetRelatedMeta(relation.RelatedWith())))
+		relatedBox := d3entity.NewBox(relatedEntity.Unwrap(), ownerBox.GetRelatedMeta(relation.RelatedWith()))
+		if err := p.ProcessDeletedEntity(relatedBox); err != nil {
+			return err
+		}
+
+		// owner references related entity, so owner deleted first (storage may check foreign keys immediately)
+		relatedPb, err := p.knownBoxes.get(relatedBox)
+		if err != nil {
+			return err
+		}
+		if !ownerBox.action.hasChild(relatedPb.action) {
+			ownerBox.action.addChild(relatedPb.action)
+		}
+		return nil
 	default:
 		return nil
 	}
 }
 
-func (p *PersistGraph) deleteOneToManyRel(ownerBox *persistBox, relation *d3entity.OneToMany) error {
+func (p *PersistGraph) deleteOneToManyRel(ownerBox *persistBox, relation *d3entity.OneToMany, soft bool) error {
 	switch relation.DeleteStrategy() {
 	case d3entity.None:
 		return nil
 	case d3entity.Nullable:
+		// soft deleted owner still exists, so related entities keep reference to it
+		if soft {
+			return nil
+		}
+
 		relatedMeta := ownerBox.GetRelatedMeta(relation.RelatedWith())
 
-		updAction := NewUpdateAction(map[string]interface{}{
-			relation.JoinColumn: ownerBox.entityPk,
-		})
-		updAction.setFields(ActionField(relation.JoinColumn, nil))
+		cond, err := pkCondition(relation.JoinColumns(), ownerBox.pkValues)
+		if err != nil {
+			return err
+		}
+
+		updAction := NewUpdateAction(cond)
+		updAction.setFields(nullActionFields(relation.JoinColumns())...)
 		updAction.setTableName(relatedMeta.TableName)
 		ownerBox.action.addChild(updAction)
 	case d3entity.Cascade:
@@ -508,12 +733,17 @@ func (p *PersistGraph) deleteOneToManyRel(ownerBox *persistBox, relation *d3enti
 	return nil
 }
 
-func (p *PersistGraph) deleteManyToManyRel(ownerBox *persistBox, relation *d3entity.ManyToMany) error {
-	act := NewDeleteAction(map[string]interface{}{
-		relation.JoinColumn: ownerBox.entityPk,
-	})
-	act.setTableName(relation.JoinTable)
-	ownerBox.action.addChild(act)
+func (p *PersistGraph) deleteManyToManyRel(ownerBox *persistBox, relation *d3entity.ManyToMany, soft bool) error {
+	if !soft {
+		cond, err := pkCondition(relation.JoinColumns(), ownerBox.pkValues)
+		if err != nil {
+			return err
+		}
+
+		act := NewDeleteAction(cond)
+		act.setTableName(relation.JoinTable)
+		ownerBox.action.addChild(act)
+	}
 
 	switch relation.DeleteStrategy() {
 	case d3entity.None, d3entity.Nullable:
@@ -554,19 +784,55 @@ func (p *promise) equal(e equaler) bool {
 	return false
 }
 
-func createIDPromise(box *persistBox) *promise {
+func createIDPromise(box *persistBox, pkField *d3entity.FieldInfo) *promise {
 	return &promise{
 		executable: func() (interface{}, error) {
-			return box.ExtractPk()
+			return box.Meta.Tools.ExtractField(box.Entity, pkField.Name)
 		},
 		box:   box,
-		field: box.Meta.Pk.Field.Name,
+		field: pkField.Name,
+	}
+}
+
+// pkActionFields create fields for columns which reference primary key of box entity.
+// Columns matched with primary key fields by position, so count of columns must be equal to count of primary key fields.
+func pkActionFields(columns []string, box *persistBox) ([]*actionField, error) {
+	if len(columns) != len(box.Meta.Pk.Fields) {
+		return nil, fmt.Errorf("%s: expected %d primary key columns, got %d", box.Meta.EntityName, len(box.Meta.Pk.Fields), len(columns))
+	}
+
+	fields := make([]*actionField, len(columns))
+	for i, pkField := range box.Meta.Pk.Fields {
+		fields[i] = ActionField(columns[i], createIDPromise(box, pkField))
+	}
+	return fields, nil
+}
+
+func nullActionFields(columns []string) []*actionField {
+	fields := make([]*actionField, len(columns))
+	for i, col := range columns {
+		fields[i] = ActionField(col, nil)
+	}
+	return fields
+}
+
+// pkCondition create identity condition, where columns matched with primary key values by position,
+// so count of columns must be equal to count of primary key values.
+func pkCondition(columns []string, pkValues []interface{}) (map[string]interface{}, error) {
+	if len(columns) != len(pkValues) {
+		return nil, fmt.Errorf("expected %d primary key columns, got %d", len(pkValues), len(columns))
+	}
+
+	cond := make(map[string]interface{}, len(columns))
+	for i, col := range columns {
+		cond[col] = pkValues[i]
 	}
+	return cond, nil
 }
 
-func doSplit(from, to CompositeAction, source *persistBox, col string, val interface{}) {
+func doSplit(from, to CompositeAction, source *persistBox, fields ...*actionField) {
 	splitAction := makeUpdateAction(source)
-	splitAction.setFields(ActionField(col, val))
+	splitAction.setFields(fields...)
 
 	if from.hasChild(splitAction) || to.hasChild(splitAction) {
 		return
@@ -921,3 +921,12 @@ func getOrderedChildrenBy(act CompositeAction, sortField string) []CompositeActi
 	})
 	return children
 }
+
+func TestPkConditionColumnsMismatch(t *testing.T) {
+	cond, err := pkCondition([]string{"tenant_id", "id"}, []interface{}{1, 2})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"tenant_id": 1, "id": 2}, cond)
+
+	_, err = pkCondition([]string{"id"}, []interface{}{1, 2})
+	assert.Error(t, err)
+}
@@ -1,15 +1,23 @@
 package persistence
 
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/godzie44/d3/orm/entity"
+)
+
 type Scanner interface {
 	Scan(...interface{}) error
 }
 
 type (
 	Pusher interface {
-		Insert(table string, cols []string, values []interface{}, onConflict OnConflict) error
-		InsertWithReturn(table string, cols []string, values []interface{}, returnCols []string, withReturned func(scanner Scanner) error) error
-		Update(table string, cols []string, values []interface{}, identityCond map[string]interface{}) error
-		Remove(table string, identityCond map[string]interface{}) error
+		Insert(ctx context.Context, table string, cols []string, values []interface{}, onConflict OnConflict) error
+		InsertWithReturn(ctx context.Context, table string, cols []string, values []interface{}, returnCols []string, withReturned func(scanner Scanner) error) error
+		// Update - update rows matched by identityCond, return count of affected rows.
+		Update(ctx context.Context, table string, cols []string, values []interface{}, identityCond map[string]interface{}) (int64, error)
+		Remove(ctx context.Context, table string, identityCond map[string]interface{}) error
 	}
 
 	OnConflict int
@@ -20,3 +28,20 @@ const (
 	Undefined
 	DoNothing
 )
+
+// ErrOptimisticLock - returned when versioned entity was changed or deleted by another transaction since it was fetched.
+var ErrOptimisticLock = errors.New("optimistic lock failed")
+
+// OptimisticLockError - error with details about entity that can't be updated because of version mismatch.
+type OptimisticLockError struct {
+	Entity  entity.Name
+	Version interface{}
+}
+
+func (e *OptimisticLockError) Error() string {
+	return fmt.Sprintf("%s: entity %s with version %v not found", ErrOptimisticLock, e.Entity, e.Version)
+}
+
+func (e *OptimisticLockError) Unwrap() error {
+	return ErrOptimisticLock
+}
@@ -17,12 +17,19 @@ type Union struct {
 	Q *Query
 }
 
+// Where - WHERE expression, params may contain Ref or *Query (subquery) besides values.
 type Where struct {
 	Field  string
 	Op     string
 	Params []interface{}
 }
 
+// Ref - reference to column used as param of WHERE expression, rendered as is instead of placeholder.
+// Useful in correlated subquery for reference to column of outer query.
+// Example:
+// query.New().Select("1").From("book b").Where("b.shop_id", "=", query.Ref("shop.id"))
+type Ref string
+
 type AndWhere struct {
 	Where
 }
@@ -59,35 +66,91 @@ const (
 )
 
 type Join struct {
-	Join string
-	On   string
-	Type JoinType
+	// Join - joined table, or alias of subquery if Subquery not nil.
+	Join     string
+	Subquery *Query
+	On       string
+	Params   []interface{}
+	Type     JoinType
+
+	// meta - meta of joined entity, nil if joined table is not an entity table.
+	meta *entity.MetaInfo
+	// alias - alias of joined entity table, empty if table joined without alias.
+	alias string
+}
+
+type LockMode int
+
+const (
+	_ LockMode = iota
+	LockForUpdate
+	LockForShare
+)
+
+// Lock - row locking clause (FOR UPDATE, FOR SHARE) of query.
+type Lock struct {
+	Mode LockMode
+	// SkipLocked - skip rows locked by other transactions instead of waiting.
+	SkipLocked bool
+	// NoWait - return error instead of waiting if any row locked by other transaction.
+	NoWait bool
 }
 
 type GroupBy string
 type From string
+
+// FromSubquery - subquery in FROM query section, selected with alias.
+type FromSubquery struct {
+	Q     *Query
+	Alias string
+}
+
+// Distinct - DISTINCT option of SELECT query section.
+type Distinct struct{}
+
 type Limit int
 type Offset int
 
 type Columns []string
 type Order []string
 
+// SubqueryColumn - subquery in SELECT query section, selected with alias.
+type SubqueryColumn struct {
+	Q     *Query
+	Alias string
+}
+
+// AliasedColumn - expression in SELECT query section, selected with alias.
+type AliasedColumn struct {
+	Expr  string
+	Alias string
+}
+
 type Query struct {
 	mainMeta      *entity.MetaInfo
 	relationsMeta map[entity.Name]*entity.MetaInfo
 	withList      map[entity.Name]struct{}
-
-	columns Columns
-	from    From
-	where   []interface{}
-	having  []*Having
-	join    []*Join
-	union   []*Union
-	group   GroupBy
-	orderBy Order
+	withPaths     []*pathNode
+
+	columns      Columns
+	aliasColumns []*AliasedColumn
+	subColumns   []*SubqueryColumn
+	from         From
+	fromSub      *FromSubquery
+	distinct     bool
+	where        []interface{}
+	having       []*Having
+	join         []*Join
+	union        []*Union
+	group        GroupBy
+	orderBy      Order
 
 	limit  Limit
 	offset Offset
+	lock   *Lock
+
+	withDeleted bool
+	filters     map[string]interface{}
 }
 
 // ForEntity - create new query.
@@ -95,6 +158,7 @@ func New() *Query {
 	return &Query{
 		relationsMeta: make(map[entity.Name]*entity.MetaInfo),
 		withList:      make(map[entity.Name]struct{}),
+		filters:       make(map[string]interface{}),
 	}
 }
 
@@ -102,7 +166,7 @@ func New() *Query {
 func (q *Query) ForEntity(targetEntityMeta *entity.MetaInfo) *Query {
 	q.mainMeta = targetEntityMeta
 	q.From(targetEntityMeta.TableName).
-		addEntityFieldsToSelect(targetEntityMeta)
+		addEntityFieldsToSelect(targetEntityMeta, targetEntityMeta.TableName)
 	return q
 }
 
@@ -112,17 +176,51 @@ func (q *Query) From(tableName string) *Query {
 	return q
 }
 
+// FromSubquery - set subquery with alias in FROM query section.
+// Example:
+// q.FromSubquery(query.New().Select("shop_id").From("book").Distinct(), "b")
+// - generate sql: FROM (SELECT DISTINCT shop_id FROM book) AS b
+func (q *Query) FromSubquery(sub *Query, alias string) *Query {
+	q.fromSub = &FromSubquery{Q: sub, Alias: alias}
+	return q
+}
+
+// Distinct - remove duplicate rows from query result.
+func (q *Query) Distinct() *Query {
+	q.distinct = true
+	return q
+}
+
 // Select - add columns to SELECT query section.
 func (q *Query) Select(columns ...string) *Query {
 	q.columns = append(q.columns, columns...)
 	return q
 }
 
+// SelectAs - add expression selected with alias to SELECT query section.
+// Example:
+// q.SelectAs("shop.total", "value")
+// - generate sql: SELECT shop.total as "value"
+func (q *Query) SelectAs(expr, alias string) *Query {
+	q.aliasColumns = append(q.aliasColumns, &AliasedColumn{Expr: expr, Alias: alias})
+	return q
+}
+
+// SelectSubquery - add subquery to SELECT query section, result of subquery selected with alias.
+// Example:
+// q.SelectSubquery(query.New().Select("COUNT(*)").From("book").Where("book.shop_id", "=", query.Ref("shop.id")), "books_count")
+// - generate sql: SELECT (SELECT COUNT(*) FROM book WHERE book.shop_id = shop.id) as "books_count"
+func (q *Query) SelectSubquery(sub *Query, alias string) *Query {
+	q.subColumns = append(q.subColumns, &SubqueryColumn{Q: sub, Alias: alias})
+	return q
+}
+
 func (q *Query) ownerMeta() *entity.MetaInfo {
 	return q.mainMeta
 }
 
-func (q *Query) addEntityFieldsToSelect(meta *entity.MetaInfo) {
+// addEntityFieldsToSelect - add columns of entity to SELECT query section, table is a name or alias of entity table.
+func (q *Query) addEntityFieldsToSelect(meta *entity.MetaInfo, table string) {
 	fields := make([]*entity.FieldInfo, 0, len(meta.Fields))
 	for _, field := range meta.Fields {
 		fields = append(fields, field)
@@ -133,10 +231,10 @@ func (q *Query) addEntityFieldsToSelect(meta *entity.MetaInfo) {
 	})
 
 	for _, f := range fields {
-		q.Select(f.FullDbAlias)
+		q.Select(table + "." + f.DbAlias)
 	}
 	for _, rel := range meta.OneToOneRelations() {
-		q.Select(meta.FullColumnAlias(rel.JoinColumn))
+		q.Select(prefixColumns(table, rel.JoinColumns())...)
 	}
 }
 
@@ -184,6 +282,20 @@ func (q *Query) OrWhere(field, operator string, params ...interface{}) *Query {
 	return q
 }
 
+// Exists - join WHERE EXISTS (subquery) expression in select query with AND operator.
+// For join with OR operator use q.OrWhere("", "EXISTS", sub).
+// Example:
+// q.Exists(query.New().Select("1").From("book").Where("book.shop_id", "=", query.Ref("shop.id")))
+// - generate sql: WHERE EXISTS (SELECT 1 FROM book WHERE book.shop_id = shop.id)
+func (q *Query) Exists(sub *Query) *Query {
+	return q.AndWhere("", "EXISTS", sub)
+}
+
+// NotExists - join WHERE NOT EXISTS (subquery) expression in select query with AND operator.
+func (q *Query) NotExists(sub *Query) *Query {
+	return q.AndWhere("", "NOT EXISTS", sub)
+}
+
 // AndNestedWhere join nested WHERE expression in select query with AND operator.
 // Example:
 // q.AndWhere("a", "=", 1).AndNestedWhere(func(q *Query){
@@ -204,6 +316,22 @@ func (q *Query) OrNestedWhere(f func(q *Query)) *Query {
 	return q
 }
 
+// WithDeleted - include soft deleted entities in query result. By default soft deleted entities
+// (entities with soft_delete field, see entity tags) of main entity and of joined relations are filtered out.
+func (q *Query) WithDeleted() *Query {
+	q.withDeleted = true
+	return q
+}
+
+// Filter - restrict main entity and entities of joined relations to entities which field equal to value.
+// Filter applies only to entities which have field with this name, other entities ignore it.
+// Example:
+// q.Filter("TenantId", 1) - generate sql: WHERE entity.tenant_id = ?
+func (q *Query) Filter(field string, value interface{}) *Query {
+	q.filters[field] = value
+	return q
+}
+
 // GroupBy - add GROUP BY clause to query.
 func (q *Query) GroupBy(expr string) *Query {
 	q.group = GroupBy(expr)
@@ -232,6 +360,19 @@ func (q *Query) Join(joinType JoinType, table string, on string) *Query {
 	return q
 }
 
+// JoinSubquery - add JOIN clause with subquery, joined subquery available by alias.
+// Example:
+// q.JoinSubquery(JoinInner, query.New().Select("shop_id", "COUNT(*) AS cnt").From("book").GroupBy("shop_id"), "b", "b.shop_id=shop.id")
+func (q *Query) JoinSubquery(joinType JoinType, sub *Query, alias string, on string) *Query {
+	q.join = append(q.join, &Join{
+		Join:     alias,
+		Subquery: sub,
+		On:       on,
+		Type:     joinType,
+	})
+	return q
+}
+
 // Union - add UNION operator to query.
 // Example:
 // q.Union(q2.AndWhere("a=?", 1))
@@ -260,6 +401,232 @@ func (q *Query) OrderBy(stmts ...string) *Query {
 	return q
 }
 
+// ForUpdate - add FOR UPDATE clause to query, fetched rows locked until end of transaction.
+// Lock hold until transaction end, so query must be executed in manually started transaction.
+func (q *Query) ForUpdate() *Query {
+	q.lockRows(LockForUpdate)
+	return q
+}
+
+// ForShare - add FOR SHARE clause to query, fetched rows can't be changed by other transactions until end of transaction.
+func (q *Query) ForShare() *Query {
+	q.lockRows(LockForShare)
+	return q
+}
+
+// SkipLocked - add SKIP LOCKED option to locking clause (FOR UPDATE if locking clause not set),
+// rows locked by other transactions skipped. Useful for job queues.
+func (q *Query) SkipLocked() *Query {
+	q.lockRows(0)
+	q.lock.SkipLocked, q.lock.NoWait = true, false
+	return q
+}
+
+// NoWait - add NOWAIT option to locking clause (FOR UPDATE if locking clause not set),
+// query fails if any row locked by other transaction.
+func (q *Query) NoWait() *Query {
+	q.lockRows(0)
+	q.lock.NoWait, q.lock.SkipLocked = true, false
+	return q
+}
+
+func (q *Query) lockRows(mode LockMode) {
+	if q.lock == nil {
+		q.lock = &Lock{Mode: LockForUpdate}
+	}
+	if mode != 0 {
+		q.lock.Mode = mode
+	}
+}
+
+// Copy - create copy of query, clauses of copy may be changed without affecting original query.
+func (q *Query) Copy() *Query {
+	c := *q
+	c.relationsMeta = make(map[entity.Name]*entity.MetaInfo, len(q.relationsMeta))
+	for name, meta := range q.relationsMeta {
+		c.relationsMeta[name] = meta
+	}
+	c.withList = make(map[entity.Name]struct{}, len(q.withList))
+	for name := range q.withList {
+		c.withList[name] = struct{}{}
+	}
+	c.filters = make(map[string]interface{}, len(q.filters))
+	for field, value := range q.filters {
+		c.filters[field] = value
+	}
+
+	c.withPaths = append([]*pathNode{}, q.withPaths...)
+	c.columns = append(Columns{}, q.columns...)
+	c.aliasColumns = append([]*AliasedColumn{}, q.aliasColumns...)
+	c.subColumns = append([]*SubqueryColumn{}, q.subColumns...)
+	c.where = append([]interface{}{}, q.where...)
+	c.having = append([]*Having{}, q.having...)
+	c.join = append([]*Join{}, q.join...)
+	c.union = append([]*Union{}, q.union...)
+	c.orderBy = append(Order{}, q.orderBy...)
+	return &c
+}
+
+// ScalarQuery - create copy of query which select only expr (for example aggregate function: COUNT(*)).
+// ORDER BY, LIMIT, OFFSET and locking clauses not copied, cause they are meaningless or forbidden for aggregation.
+func (q *Query) ScalarQuery(expr string) *Query {
+	scalar := q.Copy()
+	scalar.columns = Columns{expr}
+	scalar.aliasColumns = nil
+	scalar.subColumns = nil
+	scalar.orderBy = nil
+	scalar.limit = 0
+	scalar.offset = 0
+	scalar.lock = nil
+	return scalar
+}
+
+// CountQuery - create query which select count of main entities matched by query.
+// If query has joins distinct entities counted, for entity with composite primary key
+// it's a count of rows of SELECT DISTINCT subquery of primary key columns.
+func (q *Query) CountQuery() *Query {
+	if !q.HasJoins() || q.mainMeta == nil {
+		return q.ScalarQuery("COUNT(*)")
+	}
+
+	pkColumns := q.mainMeta.Pk.FullDbAliases()
+	if len(pkColumns) == 1 {
+		return q.ScalarQuery("COUNT(DISTINCT " + pkColumns[0] + ")")
+	}
+
+	distinct := q.ScalarQuery(pkColumns[0]).Select(pkColumns[1:]...).Distinct()
+	return New().Select("COUNT(*)").FromSubquery(distinct, "roots")
+}
+
+// AggregateQuery - create query which select aggregate function fn (for example SUM) of expr over main entities matched by query.
+// If query has joins, rows of main entity duplicated by joins, so expr aggregated over rows of
+// SELECT DISTINCT subquery of primary key columns and expr, each entity aggregated once.
+func (q *Query) AggregateQuery(fn, expr string) *Query {
+	if !q.HasJoins() || q.mainMeta == nil {
+		return q.ScalarQuery(fn + "(" + expr + ")")
+	}
+
+	pkColumns := q.mainMeta.Pk.FullDbAliases()
+	distinct := q.ScalarQuery(pkColumns[0]).Select(pkColumns[1:]...).SelectAs(expr, "value").Distinct()
+	return New().Select(fn+"(roots.value)").FromSubquery(distinct, "roots")
+}
+
+// RootsQuery - create copy of query which select only primary keys of main entity (without duplicates),
+// ORDER BY, LIMIT and OFFSET clauses are kept, so it selects keys of main entities of requested page.
+// Rows grouped by primary key, so ORDER BY expressions replaced with MIN (for ascending order) or MAX (for descending order)
+// of expression, it allows ordering by columns of joined collections. Query must not have GROUP BY and HAVING clauses (see HasGroupBy).
+func (q *Query) RootsQuery() *Query {
+	pkColumns := q.mainMeta.Pk.FullDbAliases()
+
+	roots := q.Copy()
+	roots.columns = append(Columns{}, pkColumns...)
+	roots.aliasColumns = nil
+	roots.subColumns = nil
+	roots.lock = nil
+	roots.group = GroupBy(strings.Join(pkColumns, ", "))
+	roots.orderBy = nil
+	for _, stmt := range q.orderBy {
+		for _, expr := range splitOrder(stmt) {
+			if expr == "" {
+				continue
+			}
+			roots.orderBy = append(roots.orderBy, aggregateOrder(expr))
+		}
+	}
+	return roots
+}
+
+// splitOrder - split ORDER BY statement into expressions separated by commas (commas in parentheses ignored).
+func splitOrder(stmt string) []string {
+	var exprs []string
+	var depth, start int
+	for i, r := range stmt {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				exprs = append(exprs, strings.TrimSpace(stmt[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(exprs, strings.TrimSpace(stmt[start:]))
+}
+
+// aggregateOrder - wrap ORDER BY expression into MIN or MAX function depending on order direction,
+// for example: "name DESC NULLS LAST" - "MAX(name) DESC NULLS LAST".
+func aggregateOrder(expr string) string {
+	parts := strings.Fields(expr)
+	fn, end := "MIN", len(parts)
+	if end > 2 && strings.EqualFold(parts[end-2], "NULLS") {
+		end -= 2
+	}
+	if end > 1 {
+		switch strings.ToUpper(parts[end-1]) {
+		case "DESC":
+			fn = "MAX"
+			end--
+		case "ASC":
+			end--
+		}
+	}
+	return strings.TrimSpace(fn + "(" + strings.Join(parts[:end], " ") + ") " + strings.Join(parts[end:], " "))
+}
+
+// ForRoots - create copy of query without LIMIT and OFFSET clauses, restricted to main entities with primary keys from pks.
+// Every element of pks is a slice of primary key field values.
+func (q *Query) ForRoots(pks [][]interface{}) *Query {
+	forRoots := *q
+	forRoots.where = append([]interface{}{}, q.where...)
+	forRoots.limit = 0
+	forRoots.offset = 0
+
+	return forRoots.AndWhereIn(q.mainMeta.Pk.FullDbAliases(), pks)
+}
+
+// AndWhereIn - add WHERE expression which restrict columns to one of values tuples.
+// Every element of values is a slice of values of columns.
+// Example:
+// q.AndWhereIn([]string{"a"}, [][]interface{}{{1}, {2}}) - generate sql: WHERE a IN (?,?)
+//
+// q.AndWhereIn([]string{"a", "b"}, [][]interface{}{{1, 2}, {3, 4}}) - generate sql: WHERE ((a = ? AND b = ?) OR (a = ? AND b = ?))
+func (q *Query) AndWhereIn(columns []string, values [][]interface{}) *Query {
+	if len(columns) == 1 {
+		params := make([]interface{}, len(values))
+		for i := range values {
+			params[i] = values[i][0]
+		}
+		if len(params) == 1 {
+			return q.AndWhere(columns[0], "=", params[0])
+		}
+		return q.AndWhere(columns[0], "IN", params...)
+	}
+
+	return q.AndNestedWhere(func(nested *Query) {
+		for _, tuple := range values {
+			tuple := tuple
+			nested.OrNestedWhere(func(nested *Query) {
+				for i, col := range columns {
+					nested.AndWhere(col, "=", tuple[i])
+				}
+			})
+		}
+	})
+}
+
+// HasGroupBy - true if query has GROUP BY or HAVING clauses.
+func (q *Query) HasGroupBy() bool {
+	return q.group != "" || len(q.having) != 0
+}
+
+// HasJoins - true if query has JOIN clauses (added by Join or With), so rows of main entity may be duplicated.
+func (q *Query) HasJoins() bool {
+	return len(q.join) != 0
+}
+
 // With - d3 will load with main entity related entities in same query.
 // Example:
 // q.With("myPkg/Entity2")
@@ -306,48 +673,189 @@ func (q *Query) joinEntity(name entity.Name, ownerMeta *entity.MetaInfo) error {
 
 	switch rel := relation.(type) {
 	case *entity.OneToOne:
-		q.Join(JoinLeft, relatedEntityMeta.TableName, fmt.Sprintf(
-			"%s = %s",
-			ownerMeta.FullColumnAlias(rel.JoinColumn), relatedEntityMeta.FullColumnAlias(rel.ReferenceColumn),
+		referenceColumns := rel.ReferenceColumns()
+		if len(referenceColumns) == 0 {
+			referenceColumns = relatedEntityMeta.Pk.DbAliases()
+		}
+
+		q.Join(JoinLeft, relatedEntityMeta.TableName, joinCondition(
+			prefixColumns(ownerMeta.TableName, rel.JoinColumns()), prefixColumns(relatedEntityMeta.TableName, referenceColumns),
 		))
 
 	case *entity.OneToMany:
-		q.Join(JoinLeft, relatedEntityMeta.TableName, fmt.Sprintf(
-			"%s = %s",
-			ownerMeta.Pk.FullDbAlias(), relatedEntityMeta.FullColumnAlias(rel.JoinColumn),
+		q.Join(JoinLeft, relatedEntityMeta.TableName, joinCondition(
+			ownerMeta.Pk.FullDbAliases(), prefixColumns(relatedEntityMeta.TableName, rel.JoinColumns()),
 		))
 
 	case *entity.ManyToMany:
 		q.
-			Join(JoinLeft, rel.JoinTable, fmt.Sprintf(
-				"%s = %s.%s",
-				ownerMeta.Pk.FullDbAlias(), rel.JoinTable, rel.JoinColumn,
+			Join(JoinLeft, rel.JoinTable, joinCondition(
+				ownerMeta.Pk.FullDbAliases(), prefixColumns(rel.JoinTable, rel.JoinColumns()),
 			)).
-			Join(JoinLeft, relatedEntityMeta.TableName, fmt.Sprintf(
-				"%s.%s = %s",
-				rel.JoinTable, rel.ReferenceColumn, relatedEntityMeta.Pk.FullDbAlias(),
+			Join(JoinLeft, relatedEntityMeta.TableName, joinCondition(
+				prefixColumns(rel.JoinTable, rel.ReferenceColumns()), relatedEntityMeta.Pk.FullDbAliases(),
 			))
 	}
 
-	q.addEntityFieldsToSelect(relatedEntityMeta)
+	q.join[len(q.join)-1].meta = relatedEntityMeta
+
+	q.addEntityFieldsToSelect(relatedEntityMeta, relatedEntityMeta.TableName)
 	q.relationsMeta[name] = relatedEntityMeta
 
 	return nil
 }
 
+// pathNode - relation joined by WithPath, nodes of all paths form a tree with main entity in root.
+type pathNode struct {
+	relation entity.Relation
+	meta     *entity.MetaInfo
+	// alias - alias of joined entity table.
+	alias    string
+	children []*pathNode
+}
+
+// WithPath - d3 will load with main entity related entities in same query, related entities are set by path of relation fields.
+// Tables of related entities joined with aliases, so different relations to the same entity may be loaded together.
+// Example:
+// q.WithPath("Books.Authors") - load books of main entity and authors of this books
+//
+// if err := q.WithPath("BillingAddress"); err != nil {
+//     return err
+// }
+// if err := q.WithPath("ShippingAddress"); err != nil {
+//     return err
+// } - load two relations with the same target entity
+func (q *Query) WithPath(path string) error {
+	if q.mainMeta == nil {
+		return fmt.Errorf("%w: query not bound to entity", ErrRelatedFieldNotFound)
+	}
+
+	ownerMeta, ownerTable, nodes := q.mainMeta, q.mainMeta.TableName, &q.withPaths
+	for _, fieldName := range strings.Split(path, ".") {
+		node := findPathNode(*nodes, fieldName)
+		if node == nil {
+			relation, exists := ownerMeta.Relations[fieldName]
+			if !exists {
+				return fmt.Errorf("%w: %s in path %s", ErrRelatedFieldNotFound, fieldName, path)
+			}
+
+			relatedMeta, exists := ownerMeta.RelatedMeta[relation.RelatedWith()]
+			if !exists {
+				return fmt.Errorf("%w: %s", ErrRelatedEntityNotFound, relation.RelatedWith())
+			}
+
+			node = &pathNode{relation: relation, meta: relatedMeta}
+			q.joinPathNode(node, ownerMeta, ownerTable)
+			*nodes = append(*nodes, node)
+		}
+
+		ownerMeta, ownerTable, nodes = node.meta, node.alias, &node.children
+	}
+
+	return nil
+}
+
+func findPathNode(nodes []*pathNode, fieldName string) *pathNode {
+	for _, node := range nodes {
+		if node.relation.Field().Name == fieldName {
+			return node
+		}
+	}
+	return nil
+}
+
+// joinPathNode - join table of node entity to owner table (name or alias), table joined with unique alias.
+func (q *Query) joinPathNode(node *pathNode, ownerMeta *entity.MetaInfo, ownerTable string) {
+	relatedMeta := node.meta
+	node.alias = q.nextTableAlias(relatedMeta.TableName)
+
+	switch rel := node.relation.(type) {
+	case *entity.OneToOne:
+		referenceColumns := rel.ReferenceColumns()
+		if len(referenceColumns) == 0 {
+			referenceColumns = relatedMeta.Pk.DbAliases()
+		}
+
+		q.Join(JoinLeft, relatedMeta.TableName+" AS "+node.alias, joinCondition(
+			prefixColumns(ownerTable, rel.JoinColumns()), prefixColumns(node.alias, referenceColumns),
+		))
+
+	case *entity.OneToMany:
+		q.Join(JoinLeft, relatedMeta.TableName+" AS "+node.alias, joinCondition(
+			prefixColumns(ownerTable, ownerMeta.Pk.DbAliases()), prefixColumns(node.alias, rel.JoinColumns()),
+		))
+
+	case *entity.ManyToMany:
+		joinTableAlias := q.nextTableAlias(rel.JoinTable)
+		q.
+			Join(JoinLeft, rel.JoinTable+" AS "+joinTableAlias, joinCondition(
+				prefixColumns(ownerTable, ownerMeta.Pk.DbAliases()), prefixColumns(joinTableAlias, rel.JoinColumns()),
+			)).
+			Join(JoinLeft, relatedMeta.TableName+" AS "+node.alias, joinCondition(
+				prefixColumns(joinTableAlias, rel.ReferenceColumns()), prefixColumns(node.alias, relatedMeta.Pk.DbAliases()),
+			))
+	}
+
+	q.join[len(q.join)-1].meta = relatedMeta
+	q.join[len(q.join)-1].alias = node.alias
+
+	q.addEntityFieldsToSelect(relatedMeta, node.alias)
+}
+
+// nextTableAlias - return unique alias for table joined to query.
+func (q *Query) nextTableAlias(table string) string {
+	return fmt.Sprintf("%s_%d", table, len(q.join)+1)
+}
+
+func prefixColumns(table string, columns []string) []string {
+	result := make([]string, len(columns))
+	for i := range columns {
+		result[i] = table + "." + columns[i]
+	}
+	return result
+}
+
+func joinCondition(left, right []string) string {
+	conditions := make([]string, 0, len(left))
+	for i := range left {
+		if i >= len(right) {
+			break
+		}
+		conditions = append(conditions, fmt.Sprintf("%s = %s", left[i], right[i]))
+	}
+	return strings.Join(conditions, " AND ")
+}
+
 func Visit(q *Query, visitor func(pred interface{})) {
-	visitor(q.from)
+	if q.fromSub != nil {
+		visitor(q.fromSub)
+	} else {
+		visitor(q.from)
+	}
+	if q.distinct {
+		visitor(Distinct{})
+	}
 	visitor(q.columns)
+	for _, col := range q.aliasColumns {
+		visitor(col)
+	}
+	for _, col := range q.subColumns {
+		visitor(col)
+	}
 	visitor(q.orderBy)
 
 	for _, where := range q.where {
 		visitor(where)
 	}
+	// entity conditions joined with AND operator to all other WHERE expressions, so visited last
+	for _, cond := range q.entityConditions(q.mainMeta, "") {
+		visitor(&AndWhere{cond})
+	}
 	for _, having := range q.having {
 		visitor(having)
 	}
 	for _, join := range q.join {
-		visitor(join)
+		visitor(q.restrictJoin(join))
 	}
 	for _, union := range q.union {
 		visitor(union)
@@ -361,4 +869,56 @@ func Visit(q *Query, visitor func(pred interface{})) {
 	if int(q.offset) != 0 {
 		visitor(q.offset)
 	}
+	if q.lock != nil {
+		visitor(q.lock)
+	}
+}
+
+// entityConditions - return conditions that filter out soft deleted entities and entities not matched to query filters.
+// Table is an alias of entity table, empty table means entity table joined without alias.
+func (q *Query) entityConditions(meta *entity.MetaInfo, table string) []Where {
+	if meta == nil {
+		return nil
+	}
+	if table == "" {
+		table = meta.TableName
+	}
+
+	var conditions []Where
+	if !q.withDeleted && meta.SoftDelete != nil {
+		conditions = append(conditions, Where{Field: table + "." + meta.SoftDelete.DbAlias, Op: "IS NULL"})
+	}
+
+	fields := make([]string, 0, len(q.filters))
+	for field := range q.filters {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		if f, exists := meta.Fields[field]; exists {
+			conditions = append(conditions, Where{Field: table + "." + f.DbAlias, Op: "=", Params: []interface{}{q.filters[field]}})
+		}
+	}
+
+	return conditions
+}
+
+// restrictJoin - add entity conditions of joined entity to join condition, so restricted entities not joined.
+func (q *Query) restrictJoin(join *Join) *Join {
+	conditions := q.entityConditions(join.meta, join.alias)
+	if len(conditions) == 0 {
+		return join
+	}
+
+	restricted := &Join{Join: join.Join, Subquery: join.Subquery, On: join.On, Params: join.Params, Type: join.Type}
+	for _, cond := range conditions {
+		restricted.On = fmt.Sprintf("%s AND %s %s", restricted.On, cond.Field, cond.Op)
+		if len(cond.Params) != 0 {
+			restricted.On += " ?"
+			restricted.Params = append(restricted.Params, cond.Params...)
+		}
+	}
+
+	return restricted
 }
@@ -12,7 +12,7 @@ func (preprocessor) MakeFetchPlan(q *Query) *FetchPlan {
 	return &FetchPlan{
 		query:         q,
 		pks:           extractIdsIfPossible(q),
-		fetchWithList: getFetchList(q.mainMeta, q),
+		fetchWithList: append(getFetchList(q.mainMeta, q), getPathFetchList(q.withPaths)...),
 	}
 }
 
@@ -38,8 +38,23 @@ func extractIdsIfPossible(q *Query) []interface{} {
 }
 
 func canExtractIdsFromWhere(w Where, meta *entity.MetaInfo) bool {
-	return (w.Field == meta.Pk.FullDbAlias() || w.Field == meta.Pk.Field.DbAlias) &&
-		(w.Op == "=" || w.Op == "IN")
+	if meta.Pk.IsComposite() {
+		return false
+	}
+
+	return (w.Field == meta.Pk.Fields[0].FullDbAlias || w.Field == meta.Pk.Fields[0].DbAlias) &&
+		(w.Op == "=" || w.Op == "IN") && hasOnlyValueParams(w)
+}
+
+// hasOnlyValueParams - false if where params contain column references or subqueries.
+func hasOnlyValueParams(w Where) bool {
+	for _, param := range w.Params {
+		switch param.(type) {
+		case Ref, *Query:
+			return false
+		}
+	}
+	return true
 }
 
 func getFetchList(meta *entity.MetaInfo, q *Query) []*executeWith {
@@ -60,10 +75,27 @@ func getFetchList(meta *entity.MetaInfo, q *Query) []*executeWith {
 	return result
 }
 
+// getPathFetchList - return fetch list of relations joined by WithPath.
+func getPathFetchList(nodes []*pathNode) []*executeWith {
+	var result []*executeWith
+	for _, node := range nodes {
+		result = append(result, &executeWith{
+			entityMeta: node.meta,
+			relation:   node.relation,
+			alias:      node.alias,
+			withList:   getPathFetchList(node.children),
+		})
+	}
+
+	return result
+}
+
 type FetchPlan struct {
 	query         *Query
 	pks           []interface{}
 	fetchWithList []*executeWith
+	// alias - alias of entity table in fetched rows, empty if entity table not aliased.
+	alias string
 }
 
 func (e *FetchPlan) NoNestedWhere() bool {
@@ -92,12 +124,59 @@ func (e *FetchPlan) PKs() []interface{} {
 	return e.pks
 }
 
+// Filters - filters of query (see Query.Filter), map of field name to field value.
+func (e *FetchPlan) Filters() map[string]interface{} {
+	if e.query == nil {
+		return nil
+	}
+	return e.query.filters
+}
+
+// WithDeleted - true if query includes soft deleted entities (see Query.WithDeleted).
+func (e *FetchPlan) WithDeleted() bool {
+	return e.query != nil && e.query.withDeleted
+}
+
 type executeWith struct {
 	entityMeta *entity.MetaInfo
 	relation   entity.Relation
+	alias      string
 	withList   []*executeWith
 }
 
+// Alias - alias of entity table in fetched rows, empty string means that columns prefixed with entity table name.
+func (e *FetchPlan) Alias() string {
+	return e.alias
+}
+
+// HasLock - true if query locks fetched rows, such query must be executed in database.
+func (e *FetchPlan) HasLock() bool {
+	return e.query != nil && e.query.lock != nil
+}
+
+// NeedTwoPhaseFetch - true if query joins collection relation (one to many, many to many) and has LIMIT or OFFSET,
+// so LIMIT and OFFSET applied to joined rows instead of main entities. Such query must be executed in two phases:
+// fetch primary keys of main entities (see RootsQuery), then fetch entities by primary keys (see ForRoots).
+func (e *FetchPlan) NeedTwoPhaseFetch() bool {
+	if e.query == nil || (e.query.limit == 0 && e.query.offset == 0) {
+		return false
+	}
+	return hasCollectionJoins(e.fetchWithList)
+}
+
+func hasCollectionJoins(withList []*executeWith) bool {
+	for _, with := range withList {
+		switch with.relation.(type) {
+		case *entity.OneToMany, *entity.ManyToMany:
+			return true
+		}
+		if hasCollectionJoins(with.withList) {
+			return true
+		}
+	}
+	return false
+}
+
 func (e *FetchPlan) HasJoins() bool {
 	return len(e.fetchWithList) != 0
 }
@@ -115,7 +194,7 @@ func (e *FetchPlan) CanFetchRelation(rel entity.Relation) bool {
 func (e *FetchPlan) GetChildPlan(rel entity.Relation) *FetchPlan {
 	for _, with := range e.fetchWithList {
 		if rel == with.relation {
-			return &FetchPlan{fetchWithList: with.withList}
+			return &FetchPlan{fetchWithList: with.withList, alias: with.alias}
 		}
 	}
 
@@ -3,8 +3,10 @@ package orm
 import (
 	"context"
 	"errors"
+	"fmt"
 	d3entity "github.com/godzie44/d3/orm/entity"
 	"github.com/godzie44/d3/orm/query"
+	"reflect"
 )
 
 var (
@@ -14,6 +16,8 @@ var (
 
 type Repository struct {
 	entityMeta d3entity.MetaInfo
+	// cursorKey - secret key for sign paginator cursors.
+	cursorKey []byte
 }
 
 // FindOne - return one entity fetched by query. If entity not found ErrEntityNotFound will returned.
@@ -23,7 +27,7 @@ func (r *Repository) FindOne(ctx context.Context, q *query.Query) (interface{},
 		return nil, err
 	}
 
-	coll, err := session.execute(q, &r.entityMeta)
+	coll, err := session.execute(ctx, q, &r.entityMeta)
 	if err != nil {
 		return nil, err
 	}
@@ -42,7 +46,82 @@ func (r *Repository) FindAll(ctx context.Context, q *query.Query) (*d3entity.Col
 		return nil, err
 	}
 
-	return session.execute(q, &r.entityMeta)
+	return session.execute(ctx, q, &r.entityMeta)
+}
+
+// Count - return count of entities matched by query, entities not fetched and not hydrated.
+// If query has joins (see query.With) distinct entities counted.
+func (r *Repository) Count(ctx context.Context, q *query.Query) (int64, error) {
+	session, err := sessionFromCtx(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	val, err := session.executeScalar(ctx, q, (*query.Query).CountQuery)
+	if err != nil {
+		return 0, err
+	}
+
+	count, ok := session.storage.MakeScalarDataMapper()(val, reflect.Int64).(int64)
+	if !ok {
+		return 0, fmt.Errorf("unexpected count value: %v", val)
+	}
+	return count, nil
+}
+
+// Exists - return true if at least one entity matched by query, entities not fetched and not hydrated.
+func (r *Repository) Exists(ctx context.Context, q *query.Query) (bool, error) {
+	session, err := sessionFromCtx(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	val, err := session.executeScalar(ctx, q, func(q *query.Query) *query.Query {
+		return q.ScalarQuery("1").Limit(1)
+	})
+	if err != nil {
+		return false, err
+	}
+	return val != nil, nil
+}
+
+// Sum - return sum of field values of entities matched by query. Field is entity field name or column expression.
+// Value of entity field mapped into field type if possible, value of expression returned as database driver returns it,
+// nil returned if no entities matched. If query has joins, value of each entity summed once (see query.AggregateQuery).
+func (r *Repository) Sum(ctx context.Context, q *query.Query, field string) (interface{}, error) {
+	return r.aggregate(ctx, q, "SUM", field)
+}
+
+// Min - return minimal field value of entities matched by query. Field is entity field name or column expression.
+// Value mapped the same way as in Sum.
+func (r *Repository) Min(ctx context.Context, q *query.Query, field string) (interface{}, error) {
+	return r.aggregate(ctx, q, "MIN", field)
+}
+
+// Max - return maximal field value of entities matched by query. Field is entity field name or column expression.
+// Value mapped the same way as in Sum.
+func (r *Repository) Max(ctx context.Context, q *query.Query, field string) (interface{}, error) {
+	return r.aggregate(ctx, q, "MAX", field)
+}
+
+func (r *Repository) aggregate(ctx context.Context, q *query.Query, fn, field string) (interface{}, error) {
+	session, err := sessionFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	f, isEntityField := r.entityMeta.Fields[field]
+	if isEntityField {
+		field = f.FullDbAlias
+	}
+
+	val, err := session.executeScalar(ctx, q, func(q *query.Query) *query.Query {
+		return q.AggregateQuery(fn, field)
+	})
+	if err != nil || val == nil || !isEntityField {
+		return val, err
+	}
+	return session.storage.MakeScalarDataMapper()(val, f.AssociatedType.Kind()), nil
 }
 
 // Persists - add entities to repository.
@@ -53,7 +132,7 @@ func (r *Repository) Persists(ctx context.Context, entities ...interface{}) erro
 	}
 
 	for _, entity := range entities {
-		if err := session.uow.registerNew(d3entity.NewBox(entity, &r.entityMeta)); err != nil {
+		if err := session.uow.registerNew(ctx, d3entity.NewBox(entity, &r.entityMeta)); err != nil {
 			return err
 		}
 	}
@@ -68,7 +147,7 @@ func (r *Repository) Delete(ctx context.Context, entities ...interface{}) error
 	}
 
 	for _, e := range entities {
-		if err := session.uow.registerRemove(d3entity.NewBox(e, &r.entityMeta)); err != nil {
+		if err := session.uow.registerRemove(ctx, d3entity.NewBox(e, &r.entityMeta)); err != nil {
 			return err
 		}
 	}
@@ -0,0 +1,92 @@
+package orm
+
+import (
+	"fmt"
+	"github.com/godzie44/d3/orm/entity"
+)
+
+// savepoint - savepoint of nested transaction and state of unit of work at the moment of savepoint creation.
+type savepoint struct {
+	name string
+
+	newEntities      map[entity.Name][]*entity.Box
+	dirtyEntities    map[entity.Name]map[interface{}]*dirtyEl
+	deletedEntities  map[entity.Name]map[interface{}]*entity.Box
+	identityMap      map[entity.Name]map[interface{}]interface{}
+	pendingEventsLen int
+	txChangesLen     int
+}
+
+func (uow *unitOfWork) beginSavepoint() error {
+	sp := uow.makeSavepoint(fmt.Sprintf("d3_savepoint_%d", len(uow.savepoints)+1))
+	if err := uow.currentTx.Savepoint(sp.name); err != nil {
+		return err
+	}
+
+	uow.savepoints = append(uow.savepoints, sp)
+	return nil
+}
+
+func (uow *unitOfWork) releaseSavepoint() error {
+	sp := uow.popSavepoint()
+	return uow.currentTx.ReleaseSavepoint(sp.name)
+}
+
+func (uow *unitOfWork) rollbackToSavepoint() error {
+	sp := uow.popSavepoint()
+	if err := uow.currentTx.RollbackToSavepoint(sp.name); err != nil {
+		return err
+	}
+
+	uow.restoreFields(uow.txChanges[sp.txChangesLen:])
+	uow.restoreSavepoint(sp)
+	return nil
+}
+
+func (uow *unitOfWork) popSavepoint() *savepoint {
+	sp := uow.savepoints[len(uow.savepoints)-1]
+	uow.savepoints = uow.savepoints[:len(uow.savepoints)-1]
+	return sp
+}
+
+// makeSavepoint - copy state of unit of work, originals of dirty entities copied too, cause they may be changed in place.
+func (uow *unitOfWork) makeSavepoint(name string) *savepoint {
+	sp := &savepoint{
+		name:             name,
+		newEntities:      make(map[entity.Name][]*entity.Box, len(uow.newEntities)),
+		dirtyEntities:    make(map[entity.Name]map[interface{}]*dirtyEl, len(uow.dirtyEntities)),
+		deletedEntities:  make(map[entity.Name]map[interface{}]*entity.Box, len(uow.deletedEntities)),
+		identityMap:      uow.identityMap.copyData(),
+		pendingEventsLen: len(uow.pendingEvents),
+		txChangesLen:     len(uow.txChanges),
+	}
+
+	for name, boxes := range uow.newEntities {
+		sp.newEntities[name] = append([]*entity.Box(nil), boxes...)
+	}
+
+	for name, elements := range uow.dirtyEntities {
+		sp.dirtyEntities[name] = make(map[interface{}]*dirtyEl, len(elements))
+		for pk, el := range elements {
+			sp.dirtyEntities[name][pk] = &dirtyEl{box: el.box, original: el.box.Meta.Tools.Copy(el.original)}
+		}
+	}
+
+	for name, boxes := range uow.deletedEntities {
+		sp.deletedEntities[name] = make(map[interface{}]*entity.Box, len(boxes))
+		for pk, box := range boxes {
+			sp.deletedEntities[name][pk] = box
+		}
+	}
+
+	return sp
+}
+
+func (uow *unitOfWork) restoreSavepoint(sp *savepoint) {
+	uow.newEntities = sp.newEntities
+	uow.dirtyEntities = sp.dirtyEntities
+	uow.deletedEntities = sp.deletedEntities
+	uow.identityMap.restoreData(sp.identityMap)
+	uow.pendingEvents = uow.pendingEvents[:sp.pendingEventsLen]
+	uow.txChanges = uow.txChanges[:sp.txChangesLen]
+}
@@ -2,9 +2,11 @@ package schema
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"github.com/godzie44/d3/orm/entity"
 	"reflect"
+	"sort"
 	"strings"
 	"time"
 )
@@ -77,9 +79,20 @@ func toNotNullEquivalent(t ColumnType) ColumnType {
 	return t
 }
 
+// ForeignKey - foreign key constraint, columns reference primary key of other table.
+type ForeignKey struct {
+	Columns    []string
+	RefTable   string
+	RefColumns []string
+	OnDelete   entity.DeleteStrategy
+}
+
 type StorageSchemaGenerator interface {
-	CreateTableSql(name string, columns map[string]ColumnType, pkColumns []string, pkStrategy entity.PkStrategy) string
+	CreateTableSql(name string, columns map[string]ColumnType, pkColumns []string, pkStrategy entity.PkStrategy, foreignKeys []ForeignKey) string
 	CreateIndexSql(name string, unique bool, table string, columns ...string) string
+	// CreateForeignKeySql - return sql that add foreign key into existing table,
+	// or ErrAlterUnsupported if storage allows foreign keys only in table definition.
+	CreateForeignKeySql(table string, fk ForeignKey) (string, error)
 }
 
 type Builder struct {
@@ -96,24 +109,97 @@ func (b *Builder) Build(registry *entity.MetaRegistry) (string, error) {
 		return "", err
 	}
 
-	var res strings.Builder
-	for _, cmd := range createTableCommands {
-		res.WriteString(b.schemaBuilder.CreateTableSql(cmd.tableName, cmd.columns, cmd.pkColumns, cmd.pkStrategy))
+	return createTablesSql(b.schemaBuilder, sortByDependencies(createTableCommands), map[string]bool{})
+}
 
+type newTableCmd struct {
+	tableName   string
+	columns     map[string]ColumnType
+	pkColumns   []string
+	pkStrategy  entity.PkStrategy
+	indexes     []entity.Index
+	foreignKeys []ForeignKey
+}
+
+// createTablesSql - create tables in given order. Foreign keys that reference not created yet tables
+// (circular references) added after all tables created.
+func createTablesSql(generator StorageSchemaGenerator, commands []*newTableCmd, existingTables map[string]bool) (string, error) {
+	created := make(map[string]bool, len(existingTables)+len(commands))
+	for table := range existingTables {
+		created[table] = true
+	}
+
+	var res, deferredFks strings.Builder
+	for _, cmd := range commands {
+		var foreignKeys []ForeignKey
+		for _, fk := range cmd.foreignKeys {
+			if fk.RefTable == cmd.tableName || created[fk.RefTable] {
+				foreignKeys = append(foreignKeys, fk)
+				continue
+			}
+
+			fkSql, err := generator.CreateForeignKeySql(cmd.tableName, fk)
+			if errors.Is(err, ErrAlterUnsupported) {
+				foreignKeys = append(foreignKeys, fk)
+				continue
+			}
+			if err != nil {
+				return "", err
+			}
+			deferredFks.WriteString(fkSql)
+		}
+
+		res.WriteString(generator.CreateTableSql(cmd.tableName, cmd.columns, cmd.pkColumns, cmd.pkStrategy, foreignKeys))
 		for _, ind := range cmd.indexes {
-			res.WriteString(b.schemaBuilder.CreateIndexSql(ind.Name, ind.Unique, cmd.tableName, ind.Columns...))
+			res.WriteString(generator.CreateIndexSql(ind.Name, ind.Unique, cmd.tableName, ind.Columns...))
 		}
+
+		created[cmd.tableName] = true
 	}
 
+	res.WriteString(deferredFks.String())
 	return res.String(), nil
 }
 
-type newTableCmd struct {
-	tableName  string
-	columns    map[string]ColumnType
-	pkColumns  []string
-	pkStrategy entity.PkStrategy
-	indexes    []entity.Index
+// sortByDependencies - sort commands so that referenced tables created before tables that reference them.
+func sortByDependencies(commands map[entity.Name]*newTableCmd) []*newTableCmd {
+	byTable := make(map[string]*newTableCmd, len(commands))
+	tables := make([]string, 0, len(commands))
+	for _, cmd := range commands {
+		byTable[cmd.tableName] = cmd
+		tables = append(tables, cmd.tableName)
+	}
+	sort.Strings(tables)
+
+	const (
+		inProgress = iota + 1
+		done
+	)
+	state := make(map[string]int, len(commands))
+	result := make([]*newTableCmd, 0, len(commands))
+
+	var visit func(table string)
+	visit = func(table string) {
+		if state[table] != 0 {
+			return
+		}
+		state[table] = inProgress
+
+		for _, fk := range byTable[table].foreignKeys {
+			if _, exists := byTable[fk.RefTable]; exists {
+				visit(fk.RefTable)
+			}
+		}
+
+		state[table] = done
+		result = append(result, byTable[table])
+	}
+
+	for _, table := range tables {
+		visit(table)
+	}
+
+	return result
 }
 
 func (b *Builder) createNewTableCommands(registry *entity.MetaRegistry) (map[entity.Name]*newTableCmd, error) {
@@ -139,22 +225,42 @@ func (b *Builder) createNewTableCommands(registry *entity.MetaRegistry) (map[ent
 			createTableCommand.columns[field.DbAlias] = colType
 		}
 
-		createTableCommand.pkColumns = []string{meta.Pk.Field.DbAlias}
+		createTableCommand.pkColumns = meta.Pk.DbAliases()
 		createTableCommand.pkStrategy = meta.Pk.Strategy
 
 		for _, rel := range meta.OneToOneRelations() {
 			relatedMeta := meta.RelatedMeta[rel.RelatedWith()]
-			colType, err := reflectTypeToDbType(relatedMeta.Pk.Field.AssociatedType)
+			colTypes, err := referenceColumnTypes(rel.JoinColumns(), relatedMeta)
 			if err != nil {
 				return err
 			}
 
-			switch rel.DeleteStrategy() {
-			case entity.None, entity.Cascade:
-				createTableCommand.columns[rel.JoinColumn] = colType
-			case entity.Nullable:
-				createTableCommand.columns[rel.JoinColumn] = toNullEquivalent(colType)
+			for col, colType := range colTypes {
+				switch rel.DeleteStrategy() {
+				case entity.None, entity.Cascade:
+					createTableCommand.columns[col] = colType
+				case entity.Nullable:
+					createTableCommand.columns[col] = toNullEquivalent(colType)
+				}
+			}
+
+			refColumns := rel.ReferenceColumns()
+			if len(refColumns) == 0 {
+				refColumns = relatedMeta.Pk.DbAliases()
+			}
+
+			// one to one cascade means that related entity deleted with owner, ORM do it itself,
+			// foreign key on owner side must not delete owner with related entity
+			onDelete := entity.None
+			if rel.DeleteStrategy() == entity.Nullable {
+				onDelete = entity.Nullable
 			}
+			createTableCommand.foreignKeys = append(createTableCommand.foreignKeys, ForeignKey{
+				Columns:    rel.JoinColumns(),
+				RefTable:   relatedMeta.TableName,
+				RefColumns: refColumns,
+				OnDelete:   onDelete,
+			})
 		}
 
 		for _, rel := range meta.OneToManyRelations() {
@@ -164,17 +270,26 @@ func (b *Builder) createNewTableCommands(registry *entity.MetaRegistry) (map[ent
 				}
 			}
 
-			colType, err := reflectTypeToDbType(meta.Pk.Field.AssociatedType)
+			colTypes, err := referenceColumnTypes(rel.JoinColumns(), meta)
 			if err != nil {
 				return err
 			}
 
-			switch rel.DeleteStrategy() {
-			case entity.None, entity.Cascade:
-				createTableCmdQueue[rel.RelatedWith()].columns[rel.JoinColumn] = colType
-			case entity.Nullable:
-				createTableCmdQueue[rel.RelatedWith()].columns[rel.JoinColumn] = toNullEquivalent(colType)
+			for col, colType := range colTypes {
+				switch rel.DeleteStrategy() {
+				case entity.None, entity.Cascade:
+					createTableCmdQueue[rel.RelatedWith()].columns[col] = colType
+				case entity.Nullable:
+					createTableCmdQueue[rel.RelatedWith()].columns[col] = toNullEquivalent(colType)
+				}
 			}
+
+			createTableCmdQueue[rel.RelatedWith()].foreignKeys = append(createTableCmdQueue[rel.RelatedWith()].foreignKeys, ForeignKey{
+				Columns:    rel.JoinColumns(),
+				RefTable:   meta.TableName,
+				RefColumns: meta.Pk.DbAliases(),
+				OnDelete:   rel.DeleteStrategy(),
+			})
 		}
 
 		for _, rel := range meta.ManyToManyRelations() {
@@ -182,29 +297,68 @@ func (b *Builder) createNewTableCommands(registry *entity.MetaRegistry) (map[ent
 				continue
 			}
 
-			joinColType, err := reflectTypeToDbType(meta.Pk.Field.AssociatedType)
+			joinColTypes, err := referenceColumnTypes(rel.JoinColumns(), meta)
 			if err != nil {
 				return err
 			}
 
-			refColType, err := reflectTypeToDbType(meta.RelatedMeta[rel.RelatedWith()].Pk.Field.AssociatedType)
+			refColTypes, err := referenceColumnTypes(rel.ReferenceColumns(), meta.RelatedMeta[rel.RelatedWith()])
 			if err != nil {
 				return err
 			}
 
+			columns := make(map[string]ColumnType, len(joinColTypes)+len(refColTypes))
+			for col, colType := range joinColTypes {
+				columns[col] = toNotNullEquivalent(colType)
+			}
+			for col, colType := range refColTypes {
+				columns[col] = toNotNullEquivalent(colType)
+			}
+
+			relatedMeta := meta.RelatedMeta[rel.RelatedWith()]
 			createTableCmdQueue[entity.Name(rel.JoinTable)] = &newTableCmd{
 				tableName: rel.JoinTable,
-				columns:   map[string]ColumnType{rel.JoinColumn: toNotNullEquivalent(joinColType), rel.ReferenceColumn: toNotNullEquivalent(refColType)},
-				pkColumns: []string{rel.JoinColumn, rel.ReferenceColumn},
+				columns:   columns,
+				pkColumns: append(rel.JoinColumns(), rel.ReferenceColumns()...),
+				// rows of join table has no meaning without related entities, so always cascade
+				foreignKeys: []ForeignKey{
+					{Columns: rel.JoinColumns(), RefTable: meta.TableName, RefColumns: meta.Pk.DbAliases(), OnDelete: entity.Cascade},
+					{Columns: rel.ReferenceColumns(), RefTable: relatedMeta.TableName, RefColumns: relatedMeta.Pk.DbAliases(), OnDelete: entity.Cascade},
+				},
 			}
 		}
 
 		return nil
 	})
 
+	for _, cmd := range createTableCmdQueue {
+		sort.Slice(cmd.foreignKeys, func(i, j int) bool {
+			return strings.Join(cmd.foreignKeys[i].Columns, ",") < strings.Join(cmd.foreignKeys[j].Columns, ",")
+		})
+	}
+
 	return createTableCmdQueue, err
 }
 
+// referenceColumnTypes return types of columns which reference primary key of entity.
+// Columns matched with primary key fields by position.
+func referenceColumnTypes(columns []string, referencedMeta *entity.MetaInfo) (map[string]ColumnType, error) {
+	if len(columns) != len(referencedMeta.Pk.Fields) {
+		return nil, fmt.Errorf("%s: expected %d reference columns, got %d", referencedMeta.EntityName, len(referencedMeta.Pk.Fields), len(columns))
+	}
+
+	result := make(map[string]ColumnType, len(columns))
+	for i, col := range columns {
+		colType, err := reflectTypeToDbType(referencedMeta.Pk.Fields[i].AssociatedType)
+		if err != nil {
+			return nil, err
+		}
+		result[col] = colType
+	}
+
+	return result, nil
+}
+
 func reflectTypeToDbType(t reflect.Type) (ColumnType, error) {
 	if t.Name() == "UUID" {
 		return UUID, nil
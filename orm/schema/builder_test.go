@@ -135,6 +135,9 @@ func TestCreateTables(t *testing.T) {
 		indexes: []entity.Index{
 			{Name: "name_idx", Unique: false, Columns: []string{"name"}},
 		},
+		foreignKeys: []ForeignKey{
+			{Columns: []string{"profile_uuid"}, RefTable: "profile", RefColumns: []string{"uuid"}, OnDelete: entity.None},
+		},
 	}, commands["github.com/godzie44/d3/orm/schema/shop"])
 	assert.Equal(t, &newTableCmd{
 		tableName:  "profile",
@@ -147,6 +150,9 @@ func TestCreateTables(t *testing.T) {
 		columns:    map[string]ColumnType{"id": NullInt32, "shop_id": NullInt32, "name": String},
 		pkColumns:  []string{"id"},
 		pkStrategy: entity.Auto,
+		foreignKeys: []ForeignKey{
+			{Columns: []string{"shop_id"}, RefTable: "shop", RefColumns: []string{"id"}, OnDelete: entity.Nullable},
+		},
 	}, commands["github.com/godzie44/d3/orm/schema/book"])
 	assert.Equal(t, &newTableCmd{
 		tableName:  "author",
@@ -158,5 +164,105 @@ func TestCreateTables(t *testing.T) {
 		tableName: "book_author",
 		columns:   map[string]ColumnType{"book_id": Int32, "author_id": Int32},
 		pkColumns: []string{"book_id", "author_id"},
+		foreignKeys: []ForeignKey{
+			{Columns: []string{"author_id"}, RefTable: "author", RefColumns: []string{"id"}, OnDelete: entity.Cascade},
+			{Columns: []string{"book_id"}, RefTable: "book", RefColumns: []string{"id"}, OnDelete: entity.Cascade},
+		},
 	}, commands["book_author"])
 }
+
+type article struct {
+	Id      sql.NullInt32 `d3:"pk:auto"`
+	Version int64         `d3:"version"`
+}
+
+func (a *article) D3Token() entity.MetaToken {
+	return entity.MetaToken{}
+}
+
+func TestCreateTableWithVersion(t *testing.T) {
+	registry := entity.NewMetaRegistry()
+	assert.NoError(t, registry.Add(&article{}))
+
+	commands, err := (&Builder{}).createNewTableCommands(registry)
+	assert.NoError(t, err)
+
+	assert.Equal(t, &newTableCmd{
+		tableName:  "article",
+		columns:    map[string]ColumnType{"id": NullInt32, "version": Int64},
+		pkColumns:  []string{"id"},
+		pkStrategy: entity.Auto,
+	}, commands["github.com/godzie44/d3/orm/schema/article"])
+}
+
+type post struct {
+	Id        sql.NullInt32 `d3:"pk:auto"`
+	CreatedAt time.Time     `d3:"created_at"`
+	UpdatedAt time.Time     `d3:"updated_at"`
+}
+
+func (p *post) D3Token() entity.MetaToken {
+	return entity.MetaToken{}
+}
+
+func TestCreateTableWithTimestamps(t *testing.T) {
+	registry := entity.NewMetaRegistry()
+	assert.NoError(t, registry.Add(&post{}))
+
+	commands, err := (&Builder{}).createNewTableCommands(registry)
+	assert.NoError(t, err)
+
+	assert.Equal(t, &newTableCmd{
+		tableName:  "post",
+		columns:    map[string]ColumnType{"id": NullInt32, "created_at": Time, "updated_at": Time},
+		pkColumns:  []string{"id"},
+		pkStrategy: entity.Auto,
+	}, commands["github.com/godzie44/d3/orm/schema/post"])
+}
+
+func TestCreateTablesInDependencyOrder(t *testing.T) {
+	registry := entity.NewMetaRegistry()
+	assert.NoError(t, registry.Add(
+		&shop{},
+		&profile{},
+		&book{},
+		&author{},
+	))
+
+	sql, err := NewBuilder(&inspectorStub{}).Build(registry)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "CREATE author;CREATE profile;CREATE shop FK(profile_uuid);CREATE INDEX name_idx ON shop;"+
+		"CREATE book FK(shop_id);CREATE book_author FK(author_id) FK(book_id);", sql)
+}
+
+type node struct {
+	Id   sql.NullInt32 `d3:"pk:auto"`
+	Next *entity.Cell  `d3:"one_to_one:<target_entity:github.com/godzie44/d3/orm/schema/nodeLink,join_on:link_id,delete:nullable>,type:lazy"`
+}
+
+func (n *node) D3Token() entity.MetaToken {
+	return entity.MetaToken{}
+}
+
+type nodeLink struct {
+	Id   sql.NullInt32 `d3:"pk:auto"`
+	Node *entity.Cell  `d3:"one_to_one:<target_entity:github.com/godzie44/d3/orm/schema/node,join_on:node_id>,type:lazy"`
+}
+
+func (n *nodeLink) D3Token() entity.MetaToken {
+	return entity.MetaToken{}
+}
+
+func TestCreateTablesWithCircularReferences(t *testing.T) {
+	registry := entity.NewMetaRegistry()
+	assert.NoError(t, registry.Add(&node{}, &nodeLink{}))
+
+	sql, err := NewBuilder(&inspectorStub{}).Build(registry)
+	assert.NoError(t, err)
+	assert.Equal(t, "CREATE nodelink;CREATE node FK(link_id);FK nodelink(node_id) REFERENCES node;", sql)
+
+	sql, err = NewBuilder(&inspectorStub{fkUnsupported: true}).Build(registry)
+	assert.NoError(t, err)
+	assert.Equal(t, "CREATE nodelink FK(node_id);CREATE node FK(link_id);", sql)
+}
@@ -0,0 +1,252 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"github.com/godzie44/d3/orm/entity"
+	"sort"
+	"strings"
+)
+
+// ErrAlterUnsupported - returned by StorageSchemaInspector if storage can't change column in place,
+// in this case table will be rebuilt.
+var ErrAlterUnsupported = errors.New("alter column unsupported")
+
+// ColumnDefinition - storage representation of column.
+type ColumnDefinition struct {
+	Type     string
+	Nullable bool
+}
+
+// TableInfo - current state of table in database.
+type TableInfo struct {
+	Columns map[string]ColumnDefinition
+	Indexes []entity.Index
+	// ForeignKeys - foreign keys of table, OnDelete of inspected foreign keys is not compared with expected.
+	ForeignKeys []ForeignKey
+}
+
+// StorageSchemaInspector - driver that can introspect current database schema and generate sql for change it.
+type StorageSchemaInspector interface {
+	StorageSchemaGenerator
+	// TableInfo - return current state of table, nil if table not exists.
+	TableInfo(name string) (*TableInfo, error)
+	// ColumnDefinition - return definition of column as storage reports it in TableInfo.
+	ColumnDefinition(ctype ColumnType, isPk bool, pkStrategy entity.PkStrategy) ColumnDefinition
+	AddColumnSql(table, column string, def ColumnDefinition) string
+	DropColumnSql(table, column string) (string, error)
+	// AlterColumnSql - return sql that change type and nullability of column, or ErrAlterUnsupported.
+	AlterColumnSql(table, column string, def ColumnDefinition) (string, error)
+	DropIndexSql(table, name string) string
+}
+
+// Differ - generate sql DDL for migrate current database schema to schema of registered entities.
+type Differ struct {
+	inspector StorageSchemaInspector
+}
+
+func NewDiffer(inspector StorageSchemaInspector) *Differ {
+	return &Differ{inspector: inspector}
+}
+
+// Diff - create sql DDL that add new tables, columns, indexes and foreign keys, drop removed columns and indexes,
+// change types and nullability of changed columns. Tables not presented in registry stay untouched.
+// Removed foreign keys and foreign keys with changed ON DELETE action are not dropped or changed.
+func (d *Differ) Diff(registry *entity.MetaRegistry) (string, error) {
+	commands, err := (&Builder{}).createNewTableCommands(registry)
+	if err != nil {
+		return "", err
+	}
+
+	existingTables := make(map[string]bool)
+	var newTables []*newTableCmd
+
+	var res, fkRes strings.Builder
+	for _, cmd := range sortByDependencies(commands) {
+		table, err := d.inspector.TableInfo(cmd.tableName)
+		if err != nil {
+			return "", fmt.Errorf("inspect table %s: %w", cmd.tableName, err)
+		}
+
+		if table == nil {
+			newTables = append(newTables, cmd)
+			continue
+		}
+		existingTables[cmd.tableName] = true
+
+		tableDiff, fkDiff, err := d.tableDiff(cmd, table)
+		if err != nil {
+			return "", err
+		}
+		res.WriteString(tableDiff)
+		fkRes.WriteString(fkDiff)
+	}
+
+	createSql, err := createTablesSql(d.inspector, newTables, existingTables)
+	if err != nil {
+		return "", err
+	}
+	res.WriteString(createSql)
+	// foreign keys of existing tables may reference new tables, so added last
+	res.WriteString(fkRes.String())
+
+	return res.String(), nil
+}
+
+// tableDiff - return sql that change existing table and sql that add new foreign keys into it.
+func (d *Differ) tableDiff(cmd *newTableCmd, table *TableInfo) (string, string, error) {
+	var res strings.Builder
+
+	for _, col := range sortedColumns(cmd.columns) {
+		if _, exists := table.Columns[col]; !exists {
+			res.WriteString(d.inspector.AddColumnSql(cmd.tableName, col, d.columnDefinition(cmd, col)))
+		}
+	}
+
+	var removedColumns []string
+	for col := range table.Columns {
+		if _, exists := cmd.columns[col]; !exists {
+			removedColumns = append(removedColumns, col)
+		}
+	}
+	sort.Strings(removedColumns)
+
+	for _, col := range removedColumns {
+		sql, err := d.inspector.DropColumnSql(cmd.tableName, col)
+		if errors.Is(err, ErrAlterUnsupported) {
+			res.WriteString(d.rebuildTableSql(cmd))
+			return res.String(), "", nil
+		}
+		if err != nil {
+			return "", "", err
+		}
+		res.WriteString(sql)
+	}
+
+	for _, col := range sortedColumns(cmd.columns) {
+		current, exists := table.Columns[col]
+		if !exists {
+			continue
+		}
+
+		expected := d.columnDefinition(cmd, col)
+		if strings.EqualFold(current.Type, expected.Type) && current.Nullable == expected.Nullable {
+			continue
+		}
+
+		sql, err := d.inspector.AlterColumnSql(cmd.tableName, col, expected)
+		if errors.Is(err, ErrAlterUnsupported) {
+			res.WriteString(d.rebuildTableSql(cmd))
+			return res.String(), "", nil
+		}
+		if err != nil {
+			return "", "", err
+		}
+		res.WriteString(sql)
+	}
+
+	currentIndexes := make(map[string]entity.Index, len(table.Indexes))
+	for _, ind := range table.Indexes {
+		currentIndexes[ind.Name] = ind
+	}
+
+	expectedIndexes := make(map[string]entity.Index, len(cmd.indexes))
+	for _, ind := range cmd.indexes {
+		expectedIndexes[ind.Name] = ind
+	}
+
+	for _, ind := range table.Indexes {
+		if expected, exists := expectedIndexes[ind.Name]; !exists || !sameIndex(ind, expected) {
+			res.WriteString(d.inspector.DropIndexSql(cmd.tableName, ind.Name))
+		}
+	}
+
+	var fkRes strings.Builder
+	for _, fk := range cmd.foreignKeys {
+		if hasForeignKey(table.ForeignKeys, fk) {
+			continue
+		}
+
+		sql, err := d.inspector.CreateForeignKeySql(cmd.tableName, fk)
+		if errors.Is(err, ErrAlterUnsupported) {
+			res.WriteString(d.rebuildTableSql(cmd))
+			return res.String(), "", nil
+		}
+		if err != nil {
+			return "", "", err
+		}
+		fkRes.WriteString(sql)
+	}
+
+	for _, ind := range cmd.indexes {
+		if current, exists := currentIndexes[ind.Name]; !exists || !sameIndex(ind, current) {
+			res.WriteString(d.inspector.CreateIndexSql(ind.Name, ind.Unique, cmd.tableName, ind.Columns...))
+		}
+	}
+
+	return res.String(), fkRes.String(), nil
+}
+
+func hasForeignKey(foreignKeys []ForeignKey, fk ForeignKey) bool {
+	for _, current := range foreignKeys {
+		if current.RefTable == fk.RefTable &&
+			strings.Join(current.Columns, ",") == strings.Join(fk.Columns, ",") &&
+			strings.Join(current.RefColumns, ",") == strings.Join(fk.RefColumns, ",") {
+			return true
+		}
+	}
+	return false
+}
+
+// rebuildTableSql - create new table, copy data from old one, then replace old table with new.
+// Used when storage can't alter table in place, new columns must be already added into old table.
+func (d *Differ) rebuildTableSql(cmd *newTableCmd) string {
+	tmpName := cmd.tableName + "__d3_tmp"
+	columns := strings.Join(sortedColumns(cmd.columns), ",")
+
+	var res strings.Builder
+	res.WriteString(d.inspector.CreateTableSql(tmpName, cmd.columns, cmd.pkColumns, cmd.pkStrategy, cmd.foreignKeys))
+	res.WriteString(fmt.Sprintf("INSERT INTO %s(%s) SELECT %s FROM %s;\n", tmpName, columns, columns, cmd.tableName))
+	res.WriteString(fmt.Sprintf("DROP TABLE %s;\n", cmd.tableName))
+	res.WriteString(fmt.Sprintf("ALTER TABLE %s RENAME TO %s;\n", tmpName, cmd.tableName))
+	for _, ind := range cmd.indexes {
+		res.WriteString(d.inspector.CreateIndexSql(ind.Name, ind.Unique, cmd.tableName, ind.Columns...))
+	}
+
+	return res.String()
+}
+
+func (d *Differ) columnDefinition(cmd *newTableCmd, col string) ColumnDefinition {
+	var isPk bool
+	for _, pkCol := range cmd.pkColumns {
+		if pkCol == col {
+			isPk = true
+		}
+	}
+
+	return d.inspector.ColumnDefinition(cmd.columns[col], isPk, cmd.pkStrategy)
+}
+
+func sameIndex(i1, i2 entity.Index) bool {
+	if i1.Unique != i2.Unique || len(i1.Columns) != len(i2.Columns) {
+		return false
+	}
+
+	for i := range i1.Columns {
+		if i1.Columns[i] != i2.Columns[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func sortedColumns(columns map[string]ColumnType) []string {
+	result := make([]string, 0, len(columns))
+	for col := range columns {
+		result = append(result, col)
+	}
+
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,155 @@
+package schema
+
+import (
+	"fmt"
+	"github.com/godzie44/d3/orm/entity"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+type inspectorStub struct {
+	tables           map[string]*TableInfo
+	alterUnsupported bool
+	fkUnsupported    bool
+}
+
+func (i *inspectorStub) CreateTableSql(name string, _ map[string]ColumnType, _ []string, _ entity.PkStrategy, foreignKeys []ForeignKey) string {
+	var fks string
+	for _, fk := range foreignKeys {
+		fks += fmt.Sprintf(" FK(%s)", strings.Join(fk.Columns, ","))
+	}
+	return fmt.Sprintf("CREATE %s%s;", name, fks)
+}
+
+func (i *inspectorStub) CreateForeignKeySql(table string, fk ForeignKey) (string, error) {
+	if i.fkUnsupported {
+		return "", ErrAlterUnsupported
+	}
+	return fmt.Sprintf("FK %s(%s) REFERENCES %s;", table, strings.Join(fk.Columns, ","), fk.RefTable), nil
+}
+
+func (i *inspectorStub) CreateIndexSql(name string, _ bool, table string, _ ...string) string {
+	return fmt.Sprintf("CREATE INDEX %s ON %s;", name, table)
+}
+
+func (i *inspectorStub) TableInfo(name string) (*TableInfo, error) {
+	return i.tables[name], nil
+}
+
+func (i *inspectorStub) ColumnDefinition(ctype ColumnType, isPk bool, _ entity.PkStrategy) ColumnDefinition {
+	return ColumnDefinition{Type: string(toNotNullEquivalent(ctype)), Nullable: toNotNullEquivalent(ctype) != ctype && !isPk}
+}
+
+func (i *inspectorStub) AddColumnSql(table, column string, def ColumnDefinition) string {
+	return fmt.Sprintf("ADD %s.%s %s;", table, column, def.Type)
+}
+
+func (i *inspectorStub) DropColumnSql(table, column string) (string, error) {
+	if i.alterUnsupported {
+		return "", ErrAlterUnsupported
+	}
+	return fmt.Sprintf("DROP %s.%s;", table, column), nil
+}
+
+func (i *inspectorStub) AlterColumnSql(table, column string, def ColumnDefinition) (string, error) {
+	if i.alterUnsupported {
+		return "", ErrAlterUnsupported
+	}
+	return fmt.Sprintf("ALTER %s.%s %s %t;", table, column, def.Type, def.Nullable), nil
+}
+
+func (i *inspectorStub) DropIndexSql(table, name string) string {
+	return fmt.Sprintf("DROP INDEX %s;", name)
+}
+
+func TestDiff(t *testing.T) {
+	registry := entity.NewMetaRegistry()
+	assert.NoError(t, registry.Add(&shop{}, &profile{}, &book{}, &author{}))
+
+	inspector := &inspectorStub{tables: map[string]*TableInfo{
+		"shop": {
+			Columns: map[string]ColumnDefinition{
+				"id":      {Type: "int32"},
+				"name":    {Type: "string", Nullable: true},
+				"address": {Type: "string"},
+			},
+			Indexes: []entity.Index{
+				{Name: "name_idx", Unique: true, Columns: []string{"name"}},
+				{Name: "address_idx", Columns: []string{"address"}},
+			},
+		},
+	}}
+
+	sql, err := NewDiffer(inspector).Diff(registry)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "ADD shop.profile_uuid string;"+
+		"DROP shop.address;"+
+		"ALTER shop.name string false;"+
+		"DROP INDEX name_idx;"+
+		"DROP INDEX address_idx;"+
+		"CREATE INDEX name_idx ON shop;"+
+		"CREATE author;CREATE profile;CREATE book FK(shop_id);CREATE book_author FK(author_id) FK(book_id);"+
+		"FK shop(profile_uuid) REFERENCES profile;", sql)
+}
+
+func TestDiffForeignKeys(t *testing.T) {
+	registry := entity.NewMetaRegistry()
+	assert.NoError(t, registry.Add(&shop{}, &profile{}, &book{}, &author{}))
+
+	tables := map[string]*TableInfo{
+		"shop": {
+			Columns: map[string]ColumnDefinition{"id": {Type: "int32"}, "name": {Type: "string"}, "profile_uuid": {Type: "string"}},
+			Indexes: []entity.Index{{Name: "name_idx", Columns: []string{"name"}}},
+			ForeignKeys: []ForeignKey{
+				{Columns: []string{"profile_uuid"}, RefTable: "profile", RefColumns: []string{"uuid"}},
+			},
+		},
+		"profile": {Columns: map[string]ColumnDefinition{"uuid": {Type: "string"}, "description": {Type: "string"}}},
+		"author":  {Columns: map[string]ColumnDefinition{"id": {Type: "int32"}, "name": {Type: "string"}}},
+		"book": {
+			Columns: map[string]ColumnDefinition{"id": {Type: "int32"}, "name": {Type: "string"}, "shop_id": {Type: "int32", Nullable: true}},
+		},
+		"book_author": {
+			Columns: map[string]ColumnDefinition{"book_id": {Type: "int32"}, "author_id": {Type: "int32"}},
+			ForeignKeys: []ForeignKey{
+				{Columns: []string{"author_id"}, RefTable: "author", RefColumns: []string{"id"}},
+				{Columns: []string{"book_id"}, RefTable: "book", RefColumns: []string{"id"}},
+			},
+		},
+	}
+
+	sql, err := NewDiffer(&inspectorStub{tables: tables}).Diff(registry)
+	assert.NoError(t, err)
+	assert.Equal(t, "FK book(shop_id) REFERENCES shop;", sql)
+
+	sql, err = NewDiffer(&inspectorStub{tables: tables, fkUnsupported: true}).Diff(registry)
+	assert.NoError(t, err)
+	assert.Equal(t, "CREATE book__d3_tmp FK(shop_id);"+
+		"INSERT INTO book__d3_tmp(id,name,shop_id) SELECT id,name,shop_id FROM book;\n"+
+		"DROP TABLE book;\n"+
+		"ALTER TABLE book__d3_tmp RENAME TO book;\n", sql)
+}
+
+func TestDiffWithTableRebuild(t *testing.T) {
+	registry := entity.NewMetaRegistry()
+	assert.NoError(t, registry.Add(&profile{}))
+
+	inspector := &inspectorStub{alterUnsupported: true, tables: map[string]*TableInfo{
+		"profile": {
+			Columns: map[string]ColumnDefinition{
+				"uuid":        {Type: "string"},
+				"description": {Type: "int"},
+			},
+		},
+	}}
+
+	sql, err := NewDiffer(inspector).Diff(registry)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "CREATE profile__d3_tmp;"+
+		"INSERT INTO profile__d3_tmp(description,uuid) SELECT description,uuid FROM profile;\n"+
+		"DROP TABLE profile;\n"+
+		"ALTER TABLE profile__d3_tmp RENAME TO profile;\n", sql)
+}
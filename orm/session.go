@@ -1,6 +1,9 @@
 package orm
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"github.com/godzie44/d3/orm/entity"
 	"github.com/godzie44/d3/orm/persistence"
 	"github.com/godzie44/d3/orm/query"
@@ -9,11 +12,11 @@ import (
 type Driver interface {
 	MakePusher(tx Transaction) persistence.Pusher
 
-	ExecuteQuery(query *query.Query, tx Transaction) ([]map[string]interface{}, error)
+	ExecuteQuery(ctx context.Context, query *query.Query, tx Transaction) ([]map[string]interface{}, error)
 	BeforeQuery(fn func(query string, args ...interface{}))
 	AfterQuery(fn func(query string, args ...interface{}))
 
-	BeginTx() (Transaction, error)
+	BeginTx(ctx context.Context, opts TxOptions) (Transaction, error)
 
 	MakeScalarDataMapper() ScalarDataMapper
 }
@@ -21,30 +24,71 @@ type Driver interface {
 type session struct {
 	storage Driver
 	uow     *unitOfWork
+	// txRetries - count of Transactional retries after retryable error.
+	txRetries int
+	batches   *batchLoader
 }
 
 func newSession(storage Driver, uow *unitOfWork) *session {
-	return &session{storage: storage, uow: uow}
+	return &session{storage: storage, uow: uow, batches: newBatchLoader()}
 }
 
-func (s *session) execute(q *query.Query, entityMeta *entity.MetaInfo) (*entity.Collection, error) {
+// reset - clear unit of work, identity map and registry of not initialized lazy relations.
+func (s *session) reset() {
+	s.uow.reset()
+	s.batches.reset()
+}
+
+// supportsRowLock - true if driver supports row locking clauses (see RowLockDetector).
+func (s *session) supportsRowLock() bool {
+	detector, ok := s.storage.(RowLockDetector)
+	return !ok || detector.SupportsRowLock()
+}
+
+func (s *session) execute(ctx context.Context, q *query.Query, entityMeta *entity.MetaInfo) (*entity.Collection, error) {
+	q = q.Copy()
+	s.uow.filters.apply(q)
 	fetchPlan := query.Preprocessor.MakeFetchPlan(q)
 
 	if s.uow.identityMap.canApply(fetchPlan) {
-		entities, err := s.uow.identityMap.executePlan(fetchPlan)
+		entities, err := s.uow.identityMap.executePlan(fetchPlan, entityMeta)
 		if err == nil {
 			return entities, nil
 		}
 	}
 
-	data, err := s.Execute(q)
+	if fetchPlan.NeedTwoPhaseFetch() {
+		if q.HasGroupBy() {
+			return nil, errTwoPhaseFetchGroupBy
+		}
+
+		pks, err := s.fetchRootPks(ctx, q, entityMeta)
+		if err != nil {
+			return nil, err
+		}
+		if len(pks) == 0 {
+			return entity.NewCollection(), nil
+		}
+
+		q = q.ForRoots(pks)
+		fetchPlan = query.Preprocessor.MakeFetchPlan(q)
+	}
+
+	data, err := s.Execute(ctx, q)
 	if err != nil {
 		return nil, err
 	}
 
-	hydrator := &hydrator{session: s, meta: entityMeta, scalarMapper: s.storage.MakeScalarDataMapper(),
+	return s.hydrate(ctx, data, fetchPlan, entityMeta)
+}
+
+// hydrate - create entities from fetched rows and register them in unit of work and identity map.
+func (s *session) hydrate(ctx context.Context, data []map[string]interface{}, fetchPlan *query.FetchPlan, entityMeta *entity.MetaInfo) (*entity.Collection, error) {
+	hydrator := &hydrator{ctx: ctx, session: s, meta: entityMeta, scalarMapper: s.storage.MakeScalarDataMapper(),
 		afterHydrateEntity: func(b *entity.Box) {
+			callPostLoad(ctx, b)
 			_ = s.uow.registerDirty(b)
+			s.uow.events.postLoad(ctx, b)
 		}}
 
 	result, err := hydrator.hydrate(data, fetchPlan)
@@ -57,43 +101,126 @@ func (s *session) execute(q *query.Query, entityMeta *entity.MetaInfo) (*entity.
 	return result, nil
 }
 
+// fetchRootPks - fetch primary keys of main entities of query, first phase of two phase fetch (see query.FetchPlan).
+func (s *session) fetchRootPks(ctx context.Context, q *query.Query, entityMeta *entity.MetaInfo) ([][]interface{}, error) {
+	data, err := s.Execute(ctx, q.RootsQuery())
+	if err != nil {
+		return nil, err
+	}
+
+	pkColumns := entityMeta.Pk.FullDbAliases()
+	pks := make([][]interface{}, len(data))
+	for i, row := range data {
+		pks[i] = make([]interface{}, len(pkColumns))
+		for j, col := range pkColumns {
+			pks[i][j] = row[col]
+		}
+	}
+	return pks, nil
+}
+
+// executeScalar - execute query which select single value (without hydration), return nil if query has no rows.
+// Scalar query created by scalar function from copy of q with applied session filters.
+func (s *session) executeScalar(ctx context.Context, q *query.Query, scalar func(q *query.Query) *query.Query) (interface{}, error) {
+	q = q.Copy()
+	s.uow.filters.apply(q)
+	q = scalar(q)
+
+	data, err := s.Execute(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) == 0 {
+		return nil, nil
+	}
+	for _, val := range data[0] {
+		return val, nil
+	}
+	return nil, nil
+}
+
 // Execute - execute query and return slice of result rows.
-func (s *session) Execute(q *query.Query) ([]map[string]interface{}, error) {
+func (s *session) Execute(ctx context.Context, q *query.Query) ([]map[string]interface{}, error) {
 	var err error
 	tx := s.uow.currentTx
 	if tx == nil {
-		tx, err = s.storage.BeginTx()
+		tx, err = s.storage.BeginTx(ctx, TxOptions{})
 		if err != nil {
 			return nil, err
 		}
 		defer tx.Commit() //nolint
 	}
 
-	return s.storage.ExecuteQuery(q, tx)
+	return s.storage.ExecuteQuery(ctx, q, tx)
+}
+
+// EnableFilter - enable global filter (see Orm.AddFilter) in session. Param must have the same type as filtered field.
+func (s *session) EnableFilter(name string, param interface{}) error {
+	return s.uow.filters.enable(name, param)
+}
+
+// DisableFilter - disable global filter in session.
+func (s *session) DisableFilter(name string) {
+	s.uow.filters.disable(name)
 }
 
 // Flush save all created, update changed and delete deleted entities within the session.
-func (s *session) Flush() error {
-	return s.uow.commit()
+func (s *session) Flush(ctx context.Context) error {
+	return s.uow.commit(ctx)
+}
+
+var (
+	ErrReadOnlyTx = errors.New("can't flush changes in read only transaction")
+	// ErrOptimisticLock - returned by Flush when versioned entity was changed or deleted by another transaction since it was fetched,
+	// use errors.As with *persistence.OptimisticLockError for details.
+	ErrOptimisticLock  = persistence.ErrOptimisticLock
+	errNestedTxOptions = errors.New("options can't be applied to nested transaction")
+
+	errTwoPhaseFetchGroupBy = errors.New("GROUP BY and HAVING unsupported in query with LIMIT or OFFSET which fetch collection relations")
+)
+
+// TxOptions - transaction options, zero value means driver defaults.
+type TxOptions struct {
+	// Isolation - isolation level of transaction, sql.LevelDefault means database default level.
+	Isolation sql.IsolationLevel
+	// ReadOnly - start read only transaction, Flush refuses to run in it.
+	ReadOnly bool
+	// Deferrable - start deferrable transaction, has effect only for serializable read only transactions (postgres).
+	Deferrable bool
 }
 
 // Transaction for control transaction driver must provide instance of this interface.
+// Commit must respect context passed into Driver.BeginTx, rollback must be executed even if context canceled.
 type Transaction interface {
 	Commit() error
 	Rollback() error
+	// Savepoint - create savepoint with name in transaction.
+	Savepoint(name string) error
+	// RollbackToSavepoint - rollback all changes made after savepoint with name.
+	RollbackToSavepoint(name string) error
+	// ReleaseSavepoint - destroy savepoint with name, changes made after savepoint stay in transaction.
+	ReleaseSavepoint(name string) error
 }
 
-// BeginTx - start transaction manually.
-func (s *session) BeginTx() error {
-	return s.uow.beginTx()
+// BeginTx - start transaction manually. If transaction already started, savepoint created instead (nested transaction),
+// so CommitTx and RollbackTx will release or rollback to this savepoint.
+// Options (first one used) applied only to outermost transaction, nested transaction inherit it.
+func (s *session) BeginTx(ctx context.Context, opts ...TxOptions) error {
+	var txOpts TxOptions
+	if len(opts) > 0 {
+		txOpts = opts[0]
+	}
+	return s.uow.beginTx(ctx, txOpts)
 }
 
-// CommitTx - commit transaction manually.
+// CommitTx - commit transaction manually, or release innermost savepoint if transaction is nested.
 func (s *session) CommitTx() error {
 	return s.uow.commitTx()
 }
 
-// RollbackTx - rollback transaction manually.
+// RollbackTx - rollback transaction manually, or rollback to innermost savepoint if transaction is nested.
+// On rollback to savepoint identity map and state of tracked entities restored to state at savepoint creation.
 func (s *session) RollbackTx() error {
 	return s.uow.rollbackTx()
 }
@@ -0,0 +1,59 @@
+package orm
+
+import (
+	"context"
+)
+
+// RetryableErrorDetector - driver may implement this interface to report errors (like serialization failure or deadlock)
+// after which whole transaction can be safely repeated.
+type RetryableErrorDetector interface {
+	IsRetryable(err error) bool
+}
+
+// Transactional - begin transaction, call fn, flush changes and commit transaction.
+// Transaction rolled back if fn returns error or panics (panic is propagated after rollback).
+// If driver reports retryable error (see RetryableErrorDetector) session is reset (unit of work, identity map and not initialized lazy relations are cleared)
+// and whole attempt repeated, but no more than Orm.SetTxRetries times.
+// If transaction already started fn executed in nested transaction (savepoint) without retries.
+func (s *session) Transactional(ctx context.Context, fn func(ctx context.Context) error, opts ...TxOptions) error {
+	nested := s.uow.currentTx != nil
+
+	for attempt := 0; ; attempt++ {
+		err := s.runInTx(ctx, fn, opts...)
+		if err == nil || nested || attempt >= s.txRetries || !s.isRetryable(err) {
+			return err
+		}
+
+		s.reset()
+	}
+}
+
+func (s *session) runInTx(ctx context.Context, fn func(ctx context.Context) error, opts ...TxOptions) error {
+	if err := s.BeginTx(ctx, opts...); err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = s.RollbackTx()
+			panic(p)
+		}
+	}()
+
+	if err := fn(ctx); err != nil {
+		_ = s.RollbackTx()
+		return err
+	}
+
+	if err := s.Flush(ctx); err != nil {
+		_ = s.RollbackTx()
+		return err
+	}
+
+	return s.CommitTx()
+}
+
+func (s *session) isRetryable(err error) bool {
+	detector, ok := s.storage.(RetryableErrorDetector)
+	return ok && detector.IsRetryable(err)
+}
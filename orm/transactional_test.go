@@ -0,0 +1,138 @@
+package orm
+
+import (
+	"context"
+	"errors"
+	"github.com/godzie44/d3/orm/entity"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var errSerialization = errors.New("serialization failure")
+
+type retryableStorageMock struct {
+	storageMock
+}
+
+func (s *retryableStorageMock) IsRetryable(err error) bool {
+	return errors.Is(err, errSerialization)
+}
+
+func TestTransactionalRetry(t *testing.T) {
+	storageMock := &retryableStorageMock{}
+	txMock := &transactionMock{}
+	txMock.On("Rollback")
+	txMock.On("Commit")
+	storageMock.On("BeginTx").Return(txMock)
+	storageMock.On("MakePusher").Return(&ctxAwarePusher{})
+
+	sess := newSession(storageMock, newUOW(storageMock, &eventDispatcher{}))
+	sess.txRetries = 2
+	ctx := context.Background()
+
+	attempts := 0
+	err := sess.Transactional(ctx, func(ctx context.Context) error {
+		attempts++
+		assert.Empty(t, sess.uow.newEntities[testEntityMeta.EntityName])
+		assert.NoError(t, sess.uow.registerNew(ctx, entity.NewBox(&uowTestEntity{ID: attempts}, testEntityMeta)))
+		if attempts < 3 {
+			return errSerialization
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, 3, attempts)
+	storageMock.AssertNumberOfCalls(t, "BeginTx", 3)
+	txMock.AssertNumberOfCalls(t, "Rollback", 2)
+	txMock.AssertNumberOfCalls(t, "Commit", 1)
+}
+
+func TestTransactionalRetryResetBatchLoader(t *testing.T) {
+	storageMock := &retryableStorageMock{}
+	txMock := &transactionMock{}
+	txMock.On("Rollback")
+	txMock.On("Commit")
+	storageMock.On("BeginTx").Return(txMock)
+	storageMock.On("MakePusher").Return(&ctxAwarePusher{})
+
+	sess := newSession(storageMock, newUOW(storageMock, &eventDispatcher{}))
+	sess.txRetries = 1
+	rel := makeBatchTestRelation(t)
+
+	attempts := 0
+	err := sess.Transactional(context.Background(), func(ctx context.Context) error {
+		attempts++
+		assert.Empty(t, sess.batches.pending[rel])
+		sess.batches.register(rel, []interface{}{attempts})
+		if attempts < 2 {
+			return errSerialization
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+}
+
+func TestTransactionalNoRetryOnOtherErrors(t *testing.T) {
+	storageMock := &retryableStorageMock{}
+	txMock := &transactionMock{}
+	txMock.On("Rollback")
+	storageMock.On("BeginTx").Return(txMock)
+
+	sess := newSession(storageMock, newUOW(storageMock, &eventDispatcher{}))
+	sess.txRetries = 2
+
+	fnErr := errors.New("fn error")
+	attempts := 0
+	err := sess.Transactional(context.Background(), func(ctx context.Context) error {
+		attempts++
+		return fnErr
+	})
+
+	assert.True(t, errors.Is(err, fnErr))
+	assert.Equal(t, 1, attempts)
+	txMock.AssertNumberOfCalls(t, "Rollback", 1)
+}
+
+func TestTransactionalRetriesExhausted(t *testing.T) {
+	storageMock := &retryableStorageMock{}
+	txMock := &transactionMock{}
+	txMock.On("Rollback")
+	storageMock.On("BeginTx").Return(txMock)
+
+	sess := newSession(storageMock, newUOW(storageMock, &eventDispatcher{}))
+	sess.txRetries = 1
+
+	attempts := 0
+	err := sess.Transactional(context.Background(), func(ctx context.Context) error {
+		attempts++
+		return errSerialization
+	})
+
+	assert.True(t, errors.Is(err, errSerialization))
+	assert.Equal(t, 2, attempts)
+}
+
+func TestTransactionalReadOnly(t *testing.T) {
+	storageMock := &retryableStorageMock{}
+	txMock := &transactionMock{}
+	txMock.On("Commit")
+	txMock.On("Rollback")
+	storageMock.On("BeginTx").Return(txMock)
+
+	sess := newSession(storageMock, newUOW(storageMock, &eventDispatcher{}))
+
+	err := sess.Transactional(context.Background(), func(ctx context.Context) error {
+		return nil
+	}, TxOptions{ReadOnly: true})
+	assert.NoError(t, err)
+	txMock.AssertNumberOfCalls(t, "Commit", 1)
+
+	err = sess.Transactional(context.Background(), func(ctx context.Context) error {
+		return sess.uow.registerNew(ctx, entity.NewBox(&uowTestEntity{ID: 1}, testEntityMeta))
+	}, TxOptions{ReadOnly: true})
+	assert.True(t, errors.Is(err, ErrReadOnlyTx))
+	txMock.AssertNumberOfCalls(t, "Rollback", 1)
+	storageMock.AssertNumberOfCalls(t, "MakePusher", 0)
+}
@@ -1,9 +1,12 @@
 package orm
 
 import (
+	"context"
 	"fmt"
 	"github.com/godzie44/d3/orm/entity"
 	"github.com/godzie44/d3/orm/persistence"
+	"sort"
+	"time"
 )
 
 type dirtyEl struct {
@@ -11,6 +14,14 @@ type dirtyEl struct {
 	original interface{}
 }
 
+// fieldChange - value of entity field (version, timestamp, deletion time) before flush changed it,
+// used to restore field if changes rolled back.
+type fieldChange struct {
+	box   *entity.Box
+	field string
+	prev  interface{}
+}
+
 type unitOfWork struct {
 	newEntities     map[entity.Name][]*entity.Box
 	dirtyEntities   map[entity.Name]map[interface{}]*dirtyEl
@@ -18,21 +29,39 @@ type unitOfWork struct {
 
 	storage     Driver
 	identityMap *identityMap
+	events      *eventDispatcher
 
 	currentTx Transaction
+	// currentTxOpts - options of manually started transaction.
+	currentTxOpts TxOptions
+	// savepoints - savepoints of nested transactions, innermost last.
+	savepoints []*savepoint
+	// currentTxCtx - context of manually started transaction.
+	currentTxCtx context.Context
+	// pendingEvents - domain events of entities flushed in manually started transaction.
+	pendingEvents []recordedEvent
+	// txChanges - changes of entity fields made by flushes in manually started transaction.
+	txChanges []fieldChange
+	// outboxMeta - meta of outbox message entity, nil if outbox disabled.
+	outboxMeta *entity.MetaInfo
+	filters    *sessionFilters
+	clock      func() time.Time
 }
 
-func newUOW(storage Driver) *unitOfWork {
+func newUOW(storage Driver, events *eventDispatcher) *unitOfWork {
 	return &unitOfWork{
 		newEntities:     make(map[entity.Name][]*entity.Box),
 		dirtyEntities:   make(map[entity.Name]map[interface{}]*dirtyEl),
 		deletedEntities: make(map[entity.Name]map[interface{}]*entity.Box),
 		storage:         storage,
 		identityMap:     newIdentityMap(),
+		events:          events,
+		filters:         newSessionFilters(nil),
+		clock:           time.Now,
 	}
 }
 
-func (uow *unitOfWork) registerNew(box *entity.Box) error {
+func (uow *unitOfWork) registerNew(ctx context.Context, box *entity.Box) error {
 	pkVal, err := box.ExtractPk()
 	if err != nil {
 		return fmt.Errorf("while adding Entity to new: %w", err)
@@ -42,6 +71,12 @@ func (uow *unitOfWork) registerNew(box *entity.Box) error {
 		return nil
 	}
 
+	if !uow.isNew(box) {
+		if err := callPrePersist(ctx, box); err != nil {
+			return err
+		}
+	}
+
 	if _, exists := uow.newEntities[box.GetEName()]; !exists {
 		uow.newEntities[box.Meta.EntityName] = make([]*entity.Box, 0)
 	}
@@ -51,6 +86,15 @@ func (uow *unitOfWork) registerNew(box *entity.Box) error {
 	return nil
 }
 
+func (uow *unitOfWork) isNew(box *entity.Box) bool {
+	for _, b := range uow.newEntities[box.GetEName()] {
+		if b.Entity == box.Entity {
+			return true
+		}
+	}
+	return false
+}
+
 func (uow *unitOfWork) registerDirty(box *entity.Box) error {
 	pkVal, err := box.ExtractPk()
 	if err != nil {
@@ -86,12 +130,16 @@ func (uow *unitOfWork) updateFieldOfOriginal(box *entity.Box, fieldName string,
 	_ = box.Meta.Tools.SetFieldVal(uow.dirtyEntities[box.GetEName()][pkVal].original, fieldName, newVal.DeepCopy())
 }
 
-func (uow *unitOfWork) registerRemove(box *entity.Box) error {
+func (uow *unitOfWork) registerRemove(ctx context.Context, box *entity.Box) error {
 	pkVal, err := box.ExtractPk()
 	if err != nil {
 		return err
 	}
 
+	if err := callPreRemove(ctx, box); err != nil {
+		return err
+	}
+
 	uow.clean(box, pkVal)
 
 	if _, exists := uow.deletedEntities[box.GetEName()]; !exists {
@@ -119,53 +167,197 @@ func (uow *unitOfWork) clean(box *entity.Box, pk interface{}) {
 	delete(uow.dirtyEntities[box.GetEName()], pk)
 }
 
-func (uow *unitOfWork) commit() error {
-	graph := persistence.NewPersistGraph(uow.checkInDirty, uow.getOriginal)
-
-	err := uow.processNew(graph)
+func (uow *unitOfWork) commit(ctx context.Context) error {
+	graph, err := uow.buildGraph()
 	if err != nil {
 		return err
 	}
 
-	err = uow.processDirty(graph)
+	// flush without changes is allowed in read only transaction
+	if uow.currentTx != nil && uow.currentTxOpts.ReadOnly {
+		if graph.HasChanges() {
+			return ErrReadOnlyTx
+		}
+		return nil
+	}
+
+	filled, err := uow.filters.fillInserted(graph)
 	if err != nil {
 		return err
 	}
 
-	err = uow.processDelete(graph)
+	preUpdated, err := uow.preUpdate(ctx, graph)
 	if err != nil {
 		return err
 	}
 
+	// entities may be changed in PreUpdate callbacks or by filters, so graph must be rebuilt
+	if filled || preUpdated {
+		if graph, err = uow.buildGraph(); err != nil {
+			return err
+		}
+	}
+
+	if err := uow.events.onFlush(ctx, changeSet(graph)); err != nil {
+		return err
+	}
+
+	domainEvents := uow.releaseEvents(graph)
+
 	defer func() {
 		uow.newEntities = make(map[entity.Name][]*entity.Box)
 		uow.deletedEntities = make(map[entity.Name]map[interface{}]*entity.Box)
 	}()
 
+	var flushed []*entity.Box
+	var changes []fieldChange
+	afterExec := func(act persistence.CompositeAction) {
+		if a, ok := act.(interface {
+			Box() *entity.Box
+			AppliedChanges() []persistence.FieldChange
+		}); ok && a.Box() != nil {
+			for _, change := range a.AppliedChanges() {
+				changes = append(changes, fieldChange{box: a.Box(), field: change.Field, prev: change.Prev})
+			}
+		}
+
+		switch a := act.(type) {
+		case *persistence.InsertAction:
+			if box := a.Box(); box != nil {
+				// register entity as dirty, so next commit compare entity with persisted state
+				_ = uow.registerDirty(box)
+				flushed = append(flushed, box)
+				uow.events.postInsert(ctx, box)
+			}
+		case *persistence.UpdateAction:
+			if box := a.Box(); box != nil && a.IsSoftDelete() {
+				uow.identityMap.remove(box.Meta, box.Entity)
+				uow.events.postRemove(ctx, box)
+			} else if box != nil && len(a.Values) != 0 {
+				changedFields := uow.changedFields(box)
+				_ = uow.registerDirty(box)
+				flushed = append(flushed, box)
+				uow.events.postUpdate(ctx, box, changedFields)
+			}
+		case *persistence.DeleteAction:
+			if box := a.Box(); box != nil {
+				uow.identityMap.remove(box.Meta, box.Entity)
+				uow.events.postRemove(ctx, box)
+			}
+		}
+	}
+
 	if uow.currentTx == nil {
-		tx, err := uow.storage.BeginTx()
+		tx, err := uow.storage.BeginTx(ctx, TxOptions{})
 		if err != nil {
 			return err
 		}
 
-		err = persistence.NewExecutor(uow.storage.MakePusher(tx), uow.moveInsertedBoxToDirty).Exec(graph)
+		pusher := uow.storage.MakePusher(tx)
+		err = persistence.NewExecutor(pusher, afterExec).Exec(ctx, graph)
+		if err == nil {
+			err = uow.writeOutbox(ctx, pusher, domainEvents)
+		}
 		if err != nil {
 			_ = tx.Rollback()
+			uow.restoreFields(changes)
+			uow.events.afterRollback(ctx)
+			uow.fillConstraintEntity(err, graph)
+			return err
+		}
+
+		// failed commit rolls transaction back
+		if err := tx.Commit(); err != nil {
+			uow.restoreFields(changes)
+			uow.events.afterRollback(ctx)
+			uow.fillConstraintEntity(err, graph)
+			return err
+		}
+		uow.events.afterCommit(ctx)
+		uow.events.domainEvents(ctx, domainEvents)
+	} else {
+		pusher := uow.storage.MakePusher(uow.currentTx)
+		err = persistence.NewExecutor(pusher, afterExec).Exec(ctx, graph)
+		if err == nil {
+			err = uow.writeOutbox(ctx, pusher, domainEvents)
+		}
+		if err != nil {
+			uow.restoreFields(changes)
+			uow.fillConstraintEntity(err, graph)
 			return err
 		}
+		uow.pendingEvents = append(uow.pendingEvents, domainEvents...)
+		uow.txChanges = append(uow.txChanges, changes...)
+	}
 
-		return tx.Commit()
+	for _, box := range flushed {
+		callPostFlush(ctx, box)
 	}
 
-	return persistence.NewExecutor(uow.storage.MakePusher(uow.currentTx), uow.moveInsertedBoxToDirty).Exec(graph)
+	return nil
 }
 
-func (uow *unitOfWork) moveInsertedBoxToDirty(act persistence.CompositeAction) {
-	if ia, ok := act.(*persistence.InsertAction); ok {
-		if box := ia.Box(); box != nil {
-			_ = uow.registerDirty(box)
+func (uow *unitOfWork) buildGraph() (*persistence.PersistGraph, error) {
+	graph := persistence.NewPersistGraph(uow.checkInDirty, uow.getOriginal)
+	graph.SetClock(uow.clock)
+
+	if err := uow.processNew(graph); err != nil {
+		return nil, err
+	}
+
+	if err := uow.processDirty(graph); err != nil {
+		return nil, err
+	}
+
+	if err := uow.processDelete(graph); err != nil {
+		return nil, err
+	}
+
+	return graph, nil
+}
+
+// preUpdate - call PreUpdate callback of entities that will be updated, return true if at least one callback called.
+func (uow *unitOfWork) preUpdate(ctx context.Context, graph *persistence.PersistGraph) (bool, error) {
+	var called bool
+	for _, box := range graph.UpdatedEntities() {
+		boxCalled, err := callPreUpdate(ctx, box)
+		if err != nil {
+			return false, err
+		}
+		called = called || boxCalled
+	}
+
+	return called, nil
+}
+
+// changeSet - collect entities that will be changed on graph execution.
+func changeSet(graph *persistence.PersistGraph) ChangeSet {
+	var cs ChangeSet
+	for _, box := range graph.InsertedEntities() {
+		cs.Inserted = append(cs.Inserted, box.Entity)
+	}
+	for _, box := range graph.UpdatedEntities() {
+		cs.Updated = append(cs.Updated, box.Entity)
+	}
+	for _, box := range graph.DeletedEntities() {
+		cs.Deleted = append(cs.Deleted, box.Entity)
+	}
+	return cs
+}
+
+// changedFields - return names of entity fields changed since entity was fetched or flushed last time.
+func (uow *unitOfWork) changedFields(box *entity.Box) []string {
+	original := uow.getOriginal(box)
+
+	var result []string
+	for name := range box.Meta.Fields {
+		if !box.Meta.Tools.CompareFields(box.Entity, original, name) {
+			result = append(result, name)
 		}
 	}
+	sort.Strings(result)
+
+	return result
 }
 
 func (uow *unitOfWork) processNew(graph *persistence.PersistGraph) error {
@@ -225,13 +417,36 @@ func (uow *unitOfWork) checkInDirty(box *entity.Box) (bool, error) {
 	}
 }
 
-func (uow *unitOfWork) beginTx() error {
-	tx, err := uow.storage.BeginTx()
+// reset - forget all tracked entities, identity map and state of transaction.
+func (uow *unitOfWork) reset() {
+	uow.newEntities = make(map[entity.Name][]*entity.Box)
+	uow.dirtyEntities = make(map[entity.Name]map[interface{}]*dirtyEl)
+	uow.deletedEntities = make(map[entity.Name]map[interface{}]*entity.Box)
+	uow.identityMap = newIdentityMap()
+	uow.currentTx = nil
+	uow.currentTxOpts = TxOptions{}
+	uow.currentTxCtx = nil
+	uow.savepoints = nil
+	uow.pendingEvents = nil
+	uow.txChanges = nil
+}
+
+func (uow *unitOfWork) beginTx(ctx context.Context, opts TxOptions) error {
+	if uow.currentTx != nil {
+		if opts != (TxOptions{}) {
+			return errNestedTxOptions
+		}
+		return uow.beginSavepoint()
+	}
+
+	tx, err := uow.storage.BeginTx(ctx, opts)
 	if err != nil {
 		return err
 	}
 
 	uow.currentTx = tx
+	uow.currentTxOpts = opts
+	uow.currentTxCtx = ctx
 	return nil
 }
 
@@ -239,18 +454,69 @@ func (uow *unitOfWork) commitTx() error {
 	if uow.currentTx == nil {
 		return fmt.Errorf("begin transaction before commit")
 	}
+
+	if len(uow.savepoints) != 0 {
+		return uow.releaseSavepoint()
+	}
+
 	defer func() {
 		uow.currentTx = nil
+		uow.currentTxOpts = TxOptions{}
+		uow.currentTxCtx = nil
+		uow.pendingEvents = nil
+		uow.txChanges = nil
 	}()
-	return uow.currentTx.Commit()
+
+	if err := uow.currentTx.Commit(); err != nil {
+		uow.restoreFields(uow.txChanges)
+		uow.events.afterRollback(uow.currentTxCtx)
+		return err
+	}
+	uow.events.afterCommit(uow.currentTxCtx)
+	uow.events.domainEvents(uow.currentTxCtx, uow.pendingEvents)
+	return nil
 }
 
 func (uow *unitOfWork) rollbackTx() error {
 	if uow.currentTx == nil {
 		return fmt.Errorf("begin transaction before rollback")
 	}
+
+	if len(uow.savepoints) != 0 {
+		return uow.rollbackToSavepoint()
+	}
+
 	defer func() {
 		uow.currentTx = nil
+		uow.currentTxOpts = TxOptions{}
+		uow.currentTxCtx = nil
+		uow.pendingEvents = nil
+		uow.txChanges = nil
 	}()
-	return uow.currentTx.Rollback()
+
+	// changes of transaction are lost even if rollback failed
+	uow.restoreFields(uow.txChanges)
+	if err := uow.currentTx.Rollback(); err != nil {
+		return err
+	}
+	uow.events.afterRollback(uow.currentTxCtx)
+	return nil
+}
+
+// restoreFields - set entity fields back to values before rolled back changes, originals of dirty entities
+// restored too, so fields are not treated as changed. Changes restored in reverse order, cause entity may be
+// changed by several flushes.
+func (uow *unitOfWork) restoreFields(changes []fieldChange) {
+	for i := len(changes) - 1; i >= 0; i-- {
+		box, change := changes[i].box, changes[i]
+		_ = box.Meta.Tools.SetFieldVal(box.Entity, change.field, change.prev)
+
+		pkVal, err := box.ExtractPk()
+		if err != nil {
+			continue
+		}
+		if el, exists := uow.dirtyEntities[box.GetEName()][pkVal]; exists {
+			_ = box.Meta.Tools.SetFieldVal(el.original, change.field, change.prev)
+		}
+	}
 }
@@ -1,6 +1,7 @@
 package orm
 
 import (
+	"context"
 	"errors"
 	"github.com/godzie44/d3/orm/entity"
 	"github.com/godzie44/d3/orm/persistence"
@@ -59,13 +60,13 @@ func (u *uowTestEntity) D3Token() entity.MetaToken {
 var testEntityMeta, _ = entity.NewMeta((*uowTestEntity)(nil))
 
 func TestRegisterNewEntity(t *testing.T) {
-	uow := newUOW(nil)
+	uow := newUOW(nil, &eventDispatcher{})
 
 	te1 := &uowTestEntity{ID: 1}
 	te2 := &uowTestEntity{ID: 2}
-	err := uow.registerNew(entity.NewBox(te1, testEntityMeta))
+	err := uow.registerNew(context.Background(), entity.NewBox(te1, testEntityMeta))
 	assert.NoError(t, err)
-	err = uow.registerNew(entity.NewBox(te2, testEntityMeta))
+	err = uow.registerNew(context.Background(), entity.NewBox(te2, testEntityMeta))
 	assert.NoError(t, err)
 
 	assert.Equal(t, map[entity.Name][]*entity.Box{
@@ -77,7 +78,7 @@ func TestRegisterNewEntity(t *testing.T) {
 }
 
 func TestRegisterNewEntityIfEntityInDirty(t *testing.T) {
-	uow := newUOW(nil)
+	uow := newUOW(nil, &eventDispatcher{})
 
 	te1 := &uowTestEntity{ID: 1}
 
@@ -85,7 +86,7 @@ func TestRegisterNewEntityIfEntityInDirty(t *testing.T) {
 	err := uow.registerDirty(box)
 	assert.NoError(t, err)
 
-	err = uow.registerNew(box)
+	err = uow.registerNew(context.Background(), box)
 	assert.NoError(t, err)
 
 	assert.Empty(t, uow.newEntities[box.GetEName()])
@@ -99,10 +100,10 @@ func TestTransactionRollbackIfError(t *testing.T) {
 
 	storageMock.On("BeginTx").Return(txMock)
 
-	uow := newUOW(storageMock)
+	uow := newUOW(storageMock, &eventDispatcher{})
 
-	assert.NoError(t, uow.registerNew(entity.NewBox(&uowTestEntity{}, testEntityMeta)))
-	_ = uow.commit()
+	assert.NoError(t, uow.registerNew(context.Background(), entity.NewBox(&uowTestEntity{}, testEntityMeta)))
+	_ = uow.commit(context.Background())
 
 	txMock.AssertNumberOfCalls(t, "Rollback", 1)
 	txMock.AssertNumberOfCalls(t, "Commit", 0)
@@ -116,15 +117,134 @@ func TestTransactionCommitIfNoError(t *testing.T) {
 
 	storageMock.On("BeginTx").Return(txMock)
 
-	uow := newUOW(storageMock)
+	uow := newUOW(storageMock, &eventDispatcher{})
 
-	assert.NoError(t, uow.registerNew(entity.NewBox(&uowTestEntity{}, testEntityMeta)))
-	_ = uow.commit()
+	assert.NoError(t, uow.registerNew(context.Background(), entity.NewBox(&uowTestEntity{}, testEntityMeta)))
+	_ = uow.commit(context.Background())
 
 	txMock.AssertNumberOfCalls(t, "Rollback", 0)
 	txMock.AssertNumberOfCalls(t, "Commit", 1)
 }
 
+func TestNestedTransactionUseSavepoints(t *testing.T) {
+	storageMock := &storageMock{}
+	txMock := &transactionMock{}
+	txMock.On("Savepoint", "d3_savepoint_1")
+	txMock.On("RollbackToSavepoint", "d3_savepoint_1")
+	txMock.On("ReleaseSavepoint", "d3_savepoint_1")
+	txMock.On("Commit")
+	storageMock.On("BeginTx").Return(txMock)
+
+	uow := newUOW(storageMock, &eventDispatcher{})
+	ctx := context.Background()
+
+	assert.NoError(t, uow.beginTx(ctx, TxOptions{}))
+	assert.NoError(t, uow.registerNew(ctx, entity.NewBox(&uowTestEntity{ID: 1}, testEntityMeta)))
+
+	assert.NoError(t, uow.beginTx(ctx, TxOptions{}))
+	assert.NoError(t, uow.registerNew(ctx, entity.NewBox(&uowTestEntity{ID: 2}, testEntityMeta)))
+	assert.Len(t, uow.newEntities[testEntityMeta.EntityName], 2)
+	assert.NoError(t, uow.rollbackTx())
+	assert.Len(t, uow.newEntities[testEntityMeta.EntityName], 1)
+
+	assert.NoError(t, uow.beginTx(ctx, TxOptions{}))
+	assert.NoError(t, uow.commitTx())
+	assert.NoError(t, uow.commitTx())
+
+	txMock.AssertNumberOfCalls(t, "Savepoint", 2)
+	txMock.AssertNumberOfCalls(t, "RollbackToSavepoint", 1)
+	txMock.AssertNumberOfCalls(t, "ReleaseSavepoint", 1)
+	txMock.AssertNumberOfCalls(t, "Commit", 1)
+	storageMock.AssertNumberOfCalls(t, "BeginTx", 1)
+}
+
+func TestFlushInReadOnlyTransaction(t *testing.T) {
+	storageMock := &storageMock{}
+	txMock := &transactionMock{}
+	txMock.On("Rollback")
+	storageMock.On("BeginTx").Return(txMock)
+
+	uow := newUOW(storageMock, &eventDispatcher{})
+	ctx := context.Background()
+
+	assert.NoError(t, uow.beginTx(ctx, TxOptions{ReadOnly: true}))
+	assert.True(t, errors.Is(uow.beginTx(ctx, TxOptions{ReadOnly: true}), errNestedTxOptions))
+
+	assert.NoError(t, uow.registerNew(ctx, entity.NewBox(&uowTestEntity{ID: 1}, testEntityMeta)))
+	assert.True(t, errors.Is(uow.commit(ctx), ErrReadOnlyTx))
+	assert.NoError(t, uow.rollbackTx())
+
+	storageMock.AssertNumberOfCalls(t, "MakePusher", 0)
+}
+
+func TestCommitWithCanceledContext(t *testing.T) {
+	storageMock := &storageMock{}
+	storageMock.On("MakePusher").Return(&ctxAwarePusher{})
+	txMock := &transactionMock{}
+	txMock.On("Rollback")
+
+	storageMock.On("BeginTx").Return(txMock)
+
+	uow := newUOW(storageMock, &eventDispatcher{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.NoError(t, uow.registerNew(context.Background(), entity.NewBox(&uowTestEntity{}, testEntityMeta)))
+	assert.True(t, errors.Is(uow.commit(ctx), context.Canceled))
+
+	txMock.AssertNumberOfCalls(t, "Rollback", 1)
+	txMock.AssertNumberOfCalls(t, "Commit", 0)
+}
+
+func TestCommitErrorFillConstraintEntity(t *testing.T) {
+	storageMock := &storageMock{}
+	storageMock.On("MakePusher").Return(&alwaysOkPusher{})
+	commitErr := NewConstraintError(ErrForeignKeyViolation, testEntityMeta.TableName, "", errors.New("deferred constraint"))
+	txMock := &failedCommitTxMock{err: commitErr}
+	txMock.On("Commit")
+
+	storageMock.On("BeginTx").Return(txMock)
+
+	uow := newUOW(storageMock, &eventDispatcher{})
+
+	assert.NoError(t, uow.registerNew(context.Background(), entity.NewBox(&uowTestEntity{}, testEntityMeta)))
+	err := uow.commit(context.Background())
+
+	var constraintErr *ConstraintError
+	assert.True(t, errors.As(err, &constraintErr))
+	assert.True(t, errors.Is(err, ErrForeignKeyViolation))
+	assert.Equal(t, string(testEntityMeta.EntityName), constraintErr.Entity)
+}
+
+type rollbackCountListener struct {
+	rollbacks int
+}
+
+func (r *rollbackCountListener) AfterRollback(_ context.Context) {
+	r.rollbacks++
+}
+
+func TestFailedCommitNotifyRollback(t *testing.T) {
+	storageMock := &storageMock{}
+	storageMock.On("MakePusher").Return(&alwaysOkPusher{})
+	txMock := &failedCommitTxMock{err: errors.New("commit failed")}
+	txMock.On("Commit")
+	storageMock.On("BeginTx").Return(txMock)
+
+	listener := &rollbackCountListener{}
+	uow := newUOW(storageMock, &eventDispatcher{listeners: []interface{}{listener}})
+	ctx := context.Background()
+
+	assert.NoError(t, uow.registerNew(ctx, entity.NewBox(&uowTestEntity{ID: 1}, testEntityMeta)))
+	assert.Error(t, uow.commit(ctx))
+	assert.Equal(t, 1, listener.rollbacks)
+
+	assert.NoError(t, uow.beginTx(ctx, TxOptions{}))
+	assert.Error(t, uow.commitTx())
+	assert.Equal(t, 2, listener.rollbacks)
+}
+
 type storageMock struct {
 	mock.Mock
 }
@@ -140,7 +260,7 @@ func (s *storageMock) MakePusher(_ Transaction) persistence.Pusher {
 	return args.Get(0).(persistence.Pusher)
 }
 
-func (s *storageMock) ExecuteQuery(_ *query.Query, _ Transaction) ([]map[string]interface{}, error) {
+func (s *storageMock) ExecuteQuery(_ context.Context, _ *query.Query, _ Transaction) ([]map[string]interface{}, error) {
 	return nil, nil
 }
 
@@ -150,7 +270,7 @@ func (s *storageMock) BeforeQuery(_ func(query string, args ...interface{})) {
 func (s *storageMock) AfterQuery(_ func(query string, args ...interface{})) {
 }
 
-func (s *storageMock) BeginTx() (Transaction, error) {
+func (s *storageMock) BeginTx(_ context.Context, _ TxOptions) (Transaction, error) {
 	args := s.Called()
 	return args.Get(0).(Transaction), nil
 }
@@ -160,41 +280,60 @@ type alwaysErrPusher struct {
 
 var pusherErr = errors.New("pusher err")
 
-func (a *alwaysErrPusher) Insert(_ string, _ []string, _ []interface{}, _ persistence.OnConflict) error {
+func (a *alwaysErrPusher) Insert(_ context.Context, _ string, _ []string, _ []interface{}, _ persistence.OnConflict) error {
 	return pusherErr
 }
 
-func (a alwaysErrPusher) InsertWithReturn(_ string, _ []string, _ []interface{}, _ []string, _ func(scanner persistence.Scanner) error) error {
+func (a alwaysErrPusher) InsertWithReturn(_ context.Context, _ string, _ []string, _ []interface{}, _ []string, _ func(scanner persistence.Scanner) error) error {
 	return pusherErr
 }
 
-func (a alwaysErrPusher) Update(_ string, _ []string, _ []interface{}, _ map[string]interface{}) error {
-	return pusherErr
+func (a alwaysErrPusher) Update(_ context.Context, _ string, _ []string, _ []interface{}, _ map[string]interface{}) (int64, error) {
+	return 0, pusherErr
 }
 
-func (a alwaysErrPusher) Remove(_ string, _ map[string]interface{}) error {
+func (a alwaysErrPusher) Remove(_ context.Context, _ string, _ map[string]interface{}) error {
 	return pusherErr
 }
 
 type alwaysOkPusher struct {
 }
 
-func (a alwaysOkPusher) Insert(_ string, _ []string, _ []interface{}, _ persistence.OnConflict) error {
+func (a alwaysOkPusher) Insert(_ context.Context, _ string, _ []string, _ []interface{}, _ persistence.OnConflict) error {
 	return nil
 }
 
-func (a alwaysOkPusher) InsertWithReturn(_ string, _ []string, _ []interface{}, _ []string, _ func(scanner persistence.Scanner) error) error {
+func (a alwaysOkPusher) InsertWithReturn(_ context.Context, _ string, _ []string, _ []interface{}, _ []string, _ func(scanner persistence.Scanner) error) error {
 	return nil
 }
 
-func (a alwaysOkPusher) Update(_ string, _ []string, _ []interface{}, _ map[string]interface{}) error {
-	return nil
+func (a alwaysOkPusher) Update(_ context.Context, _ string, _ []string, _ []interface{}, _ map[string]interface{}) (int64, error) {
+	return 1, nil
 }
 
-func (a alwaysOkPusher) Remove(_ string, _ map[string]interface{}) error {
+func (a alwaysOkPusher) Remove(_ context.Context, _ string, _ map[string]interface{}) error {
 	return nil
 }
 
+type ctxAwarePusher struct {
+}
+
+func (c ctxAwarePusher) Insert(ctx context.Context, _ string, _ []string, _ []interface{}, _ persistence.OnConflict) error {
+	return ctx.Err()
+}
+
+func (c ctxAwarePusher) InsertWithReturn(ctx context.Context, _ string, _ []string, _ []interface{}, _ []string, _ func(scanner persistence.Scanner) error) error {
+	return ctx.Err()
+}
+
+func (c ctxAwarePusher) Update(ctx context.Context, _ string, _ []string, _ []interface{}, _ map[string]interface{}) (int64, error) {
+	return 0, ctx.Err()
+}
+
+func (c ctxAwarePusher) Remove(ctx context.Context, _ string, _ map[string]interface{}) error {
+	return ctx.Err()
+}
+
 type transactionMock struct {
 	mock.Mock
 }
@@ -208,3 +347,29 @@ func (t *transactionMock) Rollback() error {
 	t.Called()
 	return nil
 }
+
+func (t *transactionMock) Savepoint(name string) error {
+	t.Called(name)
+	return nil
+}
+
+func (t *transactionMock) RollbackToSavepoint(name string) error {
+	t.Called(name)
+	return nil
+}
+
+func (t *transactionMock) ReleaseSavepoint(name string) error {
+	t.Called(name)
+	return nil
+}
+
+// failedCommitTxMock - transaction which commit always fails, like commit with violated deferred constraint.
+type failedCommitTxMock struct {
+	transactionMock
+	err error
+}
+
+func (t *failedCommitTxMock) Commit() error {
+	t.Called()
+	return t.err
+}
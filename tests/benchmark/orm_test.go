@@ -30,7 +30,7 @@ func BenchmarkInsert(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		aggregate := createAggregate()
 		_ = repo.Persists(ctx, aggregate)
-		_ = orm.Session(ctx).Flush()
+		_ = orm.Session(ctx).Flush(ctx)
 	}
 }
 
@@ -58,7 +58,7 @@ func BenchmarkUpdate(b *testing.B) {
 
 		book := shop.books.Get(0).(*book)
 		book.Name += " updated"
-		_ = orm.Session(ctx).Flush()
+		_ = orm.Session(ctx).Flush(ctx)
 	}
 }
 
@@ -102,7 +102,7 @@ func (i *inMemoryStorage) MakePusher(_ orm.Transaction) persistence.Pusher {
 	return i.pusher
 }
 
-func (i *inMemoryStorage) ExecuteQuery(q *query.Query, _ orm.Transaction) ([]map[string]interface{}, error) {
+func (i *inMemoryStorage) ExecuteQuery(_ context.Context, q *query.Query, _ orm.Transaction) ([]map[string]interface{}, error) {
 	var tableName string
 	query.Visit(q, func(pred interface{}) {
 		switch p := pred.(type) {
@@ -139,7 +139,7 @@ func (i *inMemoryStorage) BeforeQuery(_ func(query string, args ...interface{}))
 func (i *inMemoryStorage) AfterQuery(_ func(query string, args ...interface{})) {
 }
 
-func (i *inMemoryStorage) BeginTx() (orm.Transaction, error) {
+func (i *inMemoryStorage) BeginTx(_ context.Context, _ orm.TxOptions) (orm.Transaction, error) {
 	return &txStub{}, nil
 }
 
@@ -160,12 +160,24 @@ func (t *txStub) Rollback() error {
 	return nil
 }
 
+func (t *txStub) Savepoint(_ string) error {
+	return nil
+}
+
+func (t *txStub) RollbackToSavepoint(_ string) error {
+	return nil
+}
+
+func (t *txStub) ReleaseSavepoint(_ string) error {
+	return nil
+}
+
 type pusherStub struct {
 	store      map[string][]map[string]interface{}
 	idCounters map[string]int
 }
 
-func (p *pusherStub) Insert(table string, cols []string, values []interface{}, _ persistence.OnConflict) error {
+func (p *pusherStub) Insert(_ context.Context, table string, cols []string, values []interface{}, _ persistence.OnConflict) error {
 	colsVals := make(map[string]interface{})
 	for i, col := range cols {
 		colsVals[col] = values[i]
@@ -186,13 +198,13 @@ func (i *idScanner) Scan(v ...interface{}) error {
 	return nil
 }
 
-func (p *pusherStub) InsertWithReturn(table string, cols []string, values []interface{}, returnCols []string, withReturned func(scanner persistence.Scanner) error) error {
+func (p *pusherStub) InsertWithReturn(ctx context.Context, table string, cols []string, values []interface{}, returnCols []string, withReturned func(scanner persistence.Scanner) error) error {
 	if len(returnCols) > 1 {
 		panic("unsupported return col count")
 	}
 
 	newId := p.idCounters[table] + 1
-	if err := p.Insert(table, append(cols, returnCols...), append(values, newId), persistence.Undefined); err != nil {
+	if err := p.Insert(ctx, table, append(cols, returnCols...), append(values, newId), persistence.Undefined); err != nil {
 		return err
 	}
 
@@ -201,16 +213,18 @@ func (p *pusherStub) InsertWithReturn(table string, cols []string, values []inte
 	return withReturned(&idScanner{id: newId})
 }
 
-func (p *pusherStub) Update(table string, cols []string, values []interface{}, identityCond map[string]interface{}) error {
+func (p *pusherStub) Update(_ context.Context, table string, cols []string, values []interface{}, identityCond map[string]interface{}) (int64, error) {
+	var affected int64
 	for rowInd, row := range p.store[table] {
 		if rowIsIdentified(row, identityCond) {
 			for i := range cols {
 				p.store[table][rowInd][cols[i]] = values[i]
 			}
+			affected++
 		}
 	}
 
-	return nil
+	return affected, nil
 }
 
 func rowIsIdentified(row map[string]interface{}, identityCond map[string]interface{}) bool {
@@ -222,7 +236,7 @@ func rowIsIdentified(row map[string]interface{}, identityCond map[string]interfa
 	return true
 }
 
-func (p *pusherStub) Remove(table string, identityCond map[string]interface{}) error {
+func (p *pusherStub) Remove(_ context.Context, table string, identityCond map[string]interface{}) error {
 	var delIdx = -1
 	for rowIdx, row := range p.store[table] {
 		if rowIsIdentified(row, identityCond) {
@@ -3,6 +3,7 @@ package db
 import (
 	"context"
 	"database/sql"
+	"github.com/godzie44/d3/adapter/mysql"
 	d3pgx "github.com/godzie44/d3/adapter/pgx"
 	"github.com/godzie44/d3/adapter/sqlite"
 	"github.com/godzie44/d3/orm"
@@ -10,6 +11,7 @@ import (
 	"github.com/jackc/pgx/v4"
 	"github.com/stretchr/testify/assert"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -44,3 +46,20 @@ func CreateSQLiteTestComponents(t *testing.T, postfix string) (*helpers.DbAdapte
 
 	return d, orm.New(d), execSqlFn, helpers.NewSQLiteTester(t, conn)
 }
+
+// CreateMySQLTestComponents - foreign keys are not checked in execSqlFn, cause innodb checks them immediately
+// and test fixtures, cleanups and drops of tables with circular references can't be ordered.
+func CreateMySQLTestComponents(t *testing.T) (*helpers.DbAdapterWithQueryCounter, *orm.Orm, func(sql string) error, helpers.DBTester) {
+	driver, err := mysql.NewMySQLDriver(os.Getenv("D3_MYSQL_TEST_DB"))
+	assert.NoError(t, err)
+
+	conn := driver.UnwrapConn().(*sql.DB)
+	d := helpers.NewDbAdapterWithQueryCounter(driver)
+
+	execSqlFn := func(sql string) error {
+		_, err := conn.Exec("SET FOREIGN_KEY_CHECKS=0;\n" + strings.TrimRight(sql, "; \n\t") + ";\nSET FOREIGN_KEY_CHECKS=1;")
+		return err
+	}
+
+	return d, orm.New(d), execSqlFn, helpers.NewMySQLTester(t, conn)
+}
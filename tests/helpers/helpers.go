@@ -19,9 +19,16 @@ type DbAdapterWithQueryCounter struct {
 	dbAdapter                                                 orm.Driver
 }
 
-func (d *DbAdapterWithQueryCounter) CreateTableSql(name string, columns map[string]schema.ColumnType, pkColumns []string, pkStrategy entity.PkStrategy) string {
+func (d *DbAdapterWithQueryCounter) CreateTableSql(name string, columns map[string]schema.ColumnType, pkColumns []string, pkStrategy entity.PkStrategy, foreignKeys []schema.ForeignKey) string {
 	if generator, ok := d.dbAdapter.(schema.StorageSchemaGenerator); ok {
-		return generator.CreateTableSql(name, columns, pkColumns, pkStrategy)
+		return generator.CreateTableSql(name, columns, pkColumns, pkStrategy, foreignKeys)
+	}
+	panic("not implemented")
+}
+
+func (d *DbAdapterWithQueryCounter) CreateForeignKeySql(table string, fk schema.ForeignKey) (string, error) {
+	if generator, ok := d.dbAdapter.(schema.StorageSchemaGenerator); ok {
+		return generator.CreateForeignKeySql(table, fk)
 	}
 	panic("not implemented")
 }
@@ -33,6 +40,37 @@ func (d *DbAdapterWithQueryCounter) CreateIndexSql(name string, unique bool, tab
 	panic("not implemented")
 }
 
+func (d *DbAdapterWithQueryCounter) inspector() schema.StorageSchemaInspector {
+	if inspector, ok := d.dbAdapter.(schema.StorageSchemaInspector); ok {
+		return inspector
+	}
+	panic("not implemented")
+}
+
+func (d *DbAdapterWithQueryCounter) TableInfo(name string) (*schema.TableInfo, error) {
+	return d.inspector().TableInfo(name)
+}
+
+func (d *DbAdapterWithQueryCounter) ColumnDefinition(ctype schema.ColumnType, isPk bool, pkStrategy entity.PkStrategy) schema.ColumnDefinition {
+	return d.inspector().ColumnDefinition(ctype, isPk, pkStrategy)
+}
+
+func (d *DbAdapterWithQueryCounter) AddColumnSql(table, column string, def schema.ColumnDefinition) string {
+	return d.inspector().AddColumnSql(table, column, def)
+}
+
+func (d *DbAdapterWithQueryCounter) DropColumnSql(table, column string) (string, error) {
+	return d.inspector().DropColumnSql(table, column)
+}
+
+func (d *DbAdapterWithQueryCounter) AlterColumnSql(table, column string, def schema.ColumnDefinition) (string, error) {
+	return d.inspector().AlterColumnSql(table, column, def)
+}
+
+func (d *DbAdapterWithQueryCounter) DropIndexSql(table, name string) string {
+	return d.inspector().DropIndexSql(table, name)
+}
+
 func (d *DbAdapterWithQueryCounter) MakeScalarDataMapper() orm.ScalarDataMapper {
 	return d.dbAdapter.MakeScalarDataMapper()
 }
@@ -53,8 +91,22 @@ func (d *DbAdapterWithQueryCounter) MakePusher(tx orm.Transaction) persistence.P
 	}
 }
 
-func (d *DbAdapterWithQueryCounter) BeginTx() (orm.Transaction, error) {
-	return d.dbAdapter.BeginTx()
+func (d *DbAdapterWithQueryCounter) BeginTx(ctx context.Context, opts orm.TxOptions) (orm.Transaction, error) {
+	return d.dbAdapter.BeginTx(ctx, opts)
+}
+
+func (d *DbAdapterWithQueryCounter) IsRetryable(err error) bool {
+	if detector, ok := d.dbAdapter.(orm.RetryableErrorDetector); ok {
+		return detector.IsRetryable(err)
+	}
+	return false
+}
+
+func (d *DbAdapterWithQueryCounter) SupportsRowLock() bool {
+	if detector, ok := d.dbAdapter.(orm.RowLockDetector); ok {
+		return detector.SupportsRowLock()
+	}
+	return true
 }
 
 func NewDbAdapterWithQueryCounter(dbAdapter orm.Driver) *DbAdapterWithQueryCounter {
@@ -67,8 +119,8 @@ func NewDbAdapterWithQueryCounter(dbAdapter orm.Driver) *DbAdapterWithQueryCount
 	return wrappedAdapter
 }
 
-func (d *DbAdapterWithQueryCounter) ExecuteQuery(query *query.Query, tx orm.Transaction) ([]map[string]interface{}, error) {
-	return d.dbAdapter.ExecuteQuery(query, tx)
+func (d *DbAdapterWithQueryCounter) ExecuteQuery(ctx context.Context, query *query.Query, tx orm.Transaction) ([]map[string]interface{}, error) {
+	return d.dbAdapter.ExecuteQuery(ctx, query, tx)
 }
 
 func (d *DbAdapterWithQueryCounter) BeforeQuery(fn func(query string, args ...interface{})) {
@@ -106,24 +158,24 @@ type persistStoreWithCounters struct {
 	onInsert, onUpdate, onDelete func()
 }
 
-func (p *persistStoreWithCounters) Insert(table string, cols []string, values []interface{}, onConflict persistence.OnConflict) error {
+func (p *persistStoreWithCounters) Insert(ctx context.Context, table string, cols []string, values []interface{}, onConflict persistence.OnConflict) error {
 	p.onInsert()
-	return p.ps.Insert(table, cols, values, onConflict)
+	return p.ps.Insert(ctx, table, cols, values, onConflict)
 }
 
-func (p *persistStoreWithCounters) InsertWithReturn(table string, cols []string, values []interface{}, returnCols []string, withReturned func(scanner persistence.Scanner) error) error {
+func (p *persistStoreWithCounters) InsertWithReturn(ctx context.Context, table string, cols []string, values []interface{}, returnCols []string, withReturned func(scanner persistence.Scanner) error) error {
 	p.onInsert()
-	return p.ps.InsertWithReturn(table, cols, values, returnCols, withReturned)
+	return p.ps.InsertWithReturn(ctx, table, cols, values, returnCols, withReturned)
 }
 
-func (p *persistStoreWithCounters) Update(table string, cols []string, values []interface{}, identityCond map[string]interface{}) error {
+func (p *persistStoreWithCounters) Update(ctx context.Context, table string, cols []string, values []interface{}, identityCond map[string]interface{}) (int64, error) {
 	p.onUpdate()
-	return p.ps.Update(table, cols, values, identityCond)
+	return p.ps.Update(ctx, table, cols, values, identityCond)
 }
 
-func (p *persistStoreWithCounters) Remove(table string, identityCond map[string]interface{}) error {
+func (p *persistStoreWithCounters) Remove(ctx context.Context, table string, identityCond map[string]interface{}) error {
 	p.onDelete()
-	return p.ps.Remove(table, identityCond)
+	return p.ps.Remove(ctx, table, identityCond)
 }
 
 type DBTester interface {
@@ -253,3 +305,63 @@ func (s *SqliteTester) SeeIndex(tableName, indexName string, unique bool) DBTest
 	assert.GreaterOrEqual(s.t, cnt, 1, fmt.Sprintf("index %s not found in table %s", indexName, tableName))
 	return s
 }
+
+type MySQLTester struct {
+	t    *testing.T
+	Conn *sql.DB
+}
+
+func NewMySQLTester(t *testing.T, conn *sql.DB) *MySQLTester {
+	return &MySQLTester{t, conn}
+}
+
+func (m *MySQLTester) SeeOne(sql string, args ...interface{}) DBTester {
+	return m.See(1, sql, args...)
+}
+
+func (m *MySQLTester) SeeTwo(sql string, args ...interface{}) DBTester {
+	return m.See(2, sql, args...)
+}
+
+func (m *MySQLTester) SeeThree(sql string, args ...interface{}) DBTester {
+	return m.See(3, sql, args...)
+}
+
+func (m *MySQLTester) SeeFour(sql string, args ...interface{}) DBTester {
+	return m.See(4, sql, args...)
+}
+
+func (m *MySQLTester) See(count int, sql string, args ...interface{}) DBTester {
+	var cnt int
+	err := m.Conn.QueryRow(fmt.Sprintf("SELECT count(*) cnt FROM (%s) t", sql), args...).Scan(&cnt)
+	assert.NoError(m.t, err)
+
+	assert.Equal(m.t, count, cnt)
+	return m
+}
+
+func (m *MySQLTester) SeeTable(tableName string) DBTester {
+	var tableSql = "SELECT table_name FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = ?"
+
+	var cnt int
+	err := m.Conn.QueryRow(fmt.Sprintf("SELECT count(*) cnt FROM (%s) t", tableSql), tableName).Scan(&cnt)
+	assert.NoError(m.t, err)
+
+	assert.GreaterOrEqual(m.t, cnt, 1)
+	return m
+}
+
+func (m *MySQLTester) SeeIndex(tableName, indexName string, unique bool) DBTester {
+	var tableSql = `
+SELECT index_name
+FROM information_schema.statistics
+WHERE table_schema = DATABASE() AND table_name = ? AND index_name = ? AND non_unique = ?
+`
+
+	var cnt int
+	err := m.Conn.QueryRow(fmt.Sprintf("SELECT count(*) cnt FROM (%s) t", tableSql), tableName, indexName, !unique).Scan(&cnt)
+	assert.NoError(m.t, err)
+
+	assert.GreaterOrEqual(m.t, cnt, 1, fmt.Sprintf("index %s not found in table %s", indexName, tableName))
+	return m
+}
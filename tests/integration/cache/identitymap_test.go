@@ -136,3 +136,22 @@ func (o *IMCacheTS) TestDBCallForInQueryIfKeyNotFoundInCache() {
 
 	o.Assert().Equal(2, o.adapter.QueryCounter())
 }
+
+func (o *IMCacheTS) TestDBCallForLockingQuery() {
+	ctx := o.orm.CtxWithSession(context.Background())
+	session := orm.Session(ctx)
+	repository, _ := o.orm.MakeRepository((*entity2)(nil))
+
+	o.NoError(session.BeginTx(ctx))
+	defer session.RollbackTx() //nolint
+
+	_, err := repository.FindOne(ctx, repository.Select().Where("id", "=", 1))
+	o.Assert().NoError(err)
+
+	o.Assert().Equal(1, o.adapter.QueryCounter())
+
+	_, err = repository.FindOne(ctx, repository.Select().Where("id", "=", 1).ForUpdate())
+	o.Assert().NoError(err)
+
+	o.Assert().Equal(2, o.adapter.QueryCounter())
+}
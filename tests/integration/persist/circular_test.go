@@ -16,6 +16,7 @@ type PersistsCircularTS struct {
 	dbAdapter *helpers.DbAdapterWithQueryCounter
 	orm       *orm.Orm
 	execSqlFn func(sql string) error
+	dropSql   string
 }
 
 func (o *PersistsCircularTS) SetupSuite() {
@@ -32,12 +33,7 @@ func (o *PersistsCircularTS) SetupSuite() {
 }
 
 func (o *PersistsCircularTS) TearDownSuite() {
-	o.NoError(o.execSqlFn(`
-DROP TABLE shop_c;
-DROP TABLE profile_c;
-DROP TABLE seller_c;
-DROP TABLE known_shop_seller_c;
-`))
+	o.NoError(o.execSqlFn(o.dropSql))
 }
 
 func (o *PersistsCircularTS) TearDownTest() {
@@ -58,6 +54,8 @@ func TestPGPersistsCircularSuite(t *testing.T) {
 		dbAdapter: adapter,
 		execSqlFn: execSqlFn,
 		tester:    tester,
+		// tables reference each other, so postgres can drop them only in one statement
+		dropSql: "DROP TABLE known_shop_seller_c, profile_c, seller_c, shop_c;",
 	}
 
 	suite.Run(t, mtmTS)
@@ -71,6 +69,31 @@ func TestSQLitePersistsCircularSuite(t *testing.T) {
 		dbAdapter: adapter,
 		execSqlFn: execSqlFn,
 		tester:    tester,
+		dropSql: `
+DROP TABLE known_shop_seller_c;
+DROP TABLE profile_c;
+DROP TABLE seller_c;
+DROP TABLE shop_c;
+`,
+	}
+
+	suite.Run(t, mtmTS)
+}
+
+func TestMySQLPersistsCircularSuite(t *testing.T) {
+	adapter, d3orm, execSqlFn, tester := db.CreateMySQLTestComponents(t)
+
+	mtmTS := &PersistsCircularTS{
+		orm:       d3orm,
+		dbAdapter: adapter,
+		execSqlFn: execSqlFn,
+		tester:    tester,
+		dropSql: `
+DROP TABLE known_shop_seller_c;
+DROP TABLE profile_c;
+DROP TABLE seller_c;
+DROP TABLE shop_c;
+`,
 	}
 
 	suite.Run(t, mtmTS)
@@ -90,7 +113,7 @@ func (o *PersistsCircularTS) TestInsertWithCircularRef() {
 	profile.Shop = entity.NewCell(shop)
 
 	o.Assert().NoError(repository.Persists(ctx, shop))
-	o.Assert().NoError(orm.Session(ctx).Flush())
+	o.Assert().NoError(orm.Session(ctx).Flush(ctx))
 
 	o.Assert().NotEqual(0, shop.Id.Int32)
 	o.Assert().NotEqual(0, shop.Profile.Unwrap().(*ShopProfileCirc).Id.Int32)
@@ -124,7 +147,7 @@ func (o *PersistsCircularTS) TestInsertWithSelfCircularRef() {
 	o.Assert().NoError(repository.Persists(ctx, shop2))
 	o.Assert().NoError(repository.Persists(ctx, shop3))
 
-	o.Assert().NoError(orm.Session(ctx).Flush())
+	o.Assert().NoError(orm.Session(ctx).Flush(ctx))
 
 	o.Assert().NotEqual(0, shop1.Id.Int32)
 	o.Assert().NotEqual(0, shop2.Id.Int32)
@@ -168,7 +191,7 @@ func (o *PersistsCircularTS) TestBigCircularReferenceGraph() {
 	o.Assert().NoError(repository.Persists(ctx, shop1))
 	o.Assert().NoError(repository.Persists(ctx, shop2))
 
-	o.Assert().NoError(orm.Session(ctx).Flush())
+	o.Assert().NoError(orm.Session(ctx).Flush(ctx))
 
 	o.Assert().Equal(9, o.dbAdapter.InsertCounter())
 	o.Assert().Equal(4, o.dbAdapter.UpdateCounter())
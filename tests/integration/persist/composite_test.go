@@ -0,0 +1,197 @@
+package persist
+
+import (
+	"context"
+	"errors"
+	"github.com/godzie44/d3/orm"
+	"github.com/godzie44/d3/orm/entity"
+	"github.com/godzie44/d3/tests/helpers"
+	"github.com/godzie44/d3/tests/helpers/db"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type PersistsCompositeTS struct {
+	suite.Suite
+	tester    helpers.DBTester
+	dbAdapter *helpers.DbAdapterWithQueryCounter
+	d3Orm     *orm.Orm
+	ctx       context.Context
+	execSqlFn func(sql string) error
+}
+
+func (o *PersistsCompositeTS) SetupSuite() {
+	o.NoError(o.d3Orm.Register(
+		(*CustomerCmp)(nil),
+		(*ManagerCmp)(nil),
+		(*OrderCmp)(nil),
+		(*TagCmp)(nil),
+	))
+
+	schemaSql, err := o.d3Orm.GenerateSchema()
+	o.NoError(err)
+
+	o.NoError(o.execSqlFn(schemaSql))
+}
+
+func (o *PersistsCompositeTS) SetupTest() {
+	o.ctx = o.d3Orm.CtxWithSession(context.Background())
+}
+
+func (o *PersistsCompositeTS) TearDownSuite() {
+	o.NoError(o.execSqlFn(`
+DROP TABLE customer_tag_cmp;
+DROP TABLE order_cmp;
+DROP TABLE customer_cmp;
+DROP TABLE manager_cmp;
+DROP TABLE tag_cmp;
+`))
+}
+
+func (o *PersistsCompositeTS) TearDownTest() {
+	o.dbAdapter.ResetCounters()
+	o.NoError(o.execSqlFn(`
+delete from customer_cmp;
+delete from manager_cmp;
+delete from order_cmp;
+delete from tag_cmp;
+delete from customer_tag_cmp;
+`))
+}
+
+func TestPGPersistsCompositeSuite(t *testing.T) {
+	adapter, d3orm, execSqlFn, tester := db.CreatePGTestComponents(t)
+
+	ts := &PersistsCompositeTS{
+		dbAdapter: adapter,
+		d3Orm:     d3orm,
+		execSqlFn: execSqlFn,
+		tester:    tester,
+	}
+	suite.Run(t, ts)
+}
+
+func TestSQLitePersistsCompositeSuite(t *testing.T) {
+	adapter, d3orm, execSqlFn, tester := db.CreateSQLiteTestComponents(t, "_composite")
+
+	ts := &PersistsCompositeTS{
+		d3Orm:     d3orm,
+		dbAdapter: adapter,
+		execSqlFn: execSqlFn,
+		tester:    tester,
+	}
+	suite.Run(t, ts)
+}
+
+func (o *PersistsCompositeTS) persistsCustomer() *CustomerCmp {
+	repository, err := o.d3Orm.MakeRepository((*CustomerCmp)(nil))
+	o.NoError(err)
+
+	customer := &CustomerCmp{
+		TenantId: 1,
+		Id:       1,
+		Name:     "customer",
+		Manager:  entity.NewCell(&ManagerCmp{TenantId: 1, Id: 2, Name: "manager"}),
+		Orders:   entity.NewCollection(&OrderCmp{Total: 10}, &OrderCmp{Total: 20}),
+		Tags:     entity.NewCollection(&TagCmp{Id: 1, Name: "tag1"}, &TagCmp{Id: 2, Name: "tag2"}),
+	}
+
+	o.NoError(repository.Persists(o.ctx, customer))
+	o.NoError(orm.Session(o.ctx).Flush(o.ctx))
+
+	return customer
+}
+
+func (o *PersistsCompositeTS) TestInsert() {
+	o.persistsCustomer()
+
+	o.tester.
+		SeeOne("SELECT * FROM customer_cmp WHERE tenant_id = 1 AND id = 1 AND manager_tenant_id = 1 AND manager_id = 2").
+		SeeOne("SELECT * FROM manager_cmp WHERE tenant_id = 1 AND id = 2").
+		SeeTwo("SELECT * FROM order_cmp WHERE tenant_id = 1 AND customer_id = 1").
+		SeeTwo("SELECT * FROM customer_tag_cmp WHERE tenant_id = 1 AND customer_id = 1")
+}
+
+func (o *PersistsCompositeTS) TestFetchAndUpdate() {
+	o.persistsCustomer()
+
+	ctx := o.d3Orm.CtxWithSession(context.Background())
+	repository, err := o.d3Orm.MakeRepository((*CustomerCmp)(nil))
+	o.NoError(err)
+
+	fetched, err := repository.FindOne(ctx, repository.Select().
+		Where("customer_cmp.tenant_id", "=", 1).
+		AndWhere("customer_cmp.id", "=", 1),
+	)
+	o.NoError(err)
+
+	customer := fetched.(*CustomerCmp)
+	o.Equal("manager", customer.Manager.Unwrap().(*ManagerCmp).Name)
+	o.Equal(2, customer.Orders.Count())
+	o.Equal(2, customer.Tags.Count())
+
+	customer.Name = "new name"
+	o.dbAdapter.ResetCounters()
+	o.NoError(orm.Session(ctx).Flush(ctx))
+
+	o.Equal(1, o.dbAdapter.UpdateCounter())
+	o.tester.SeeOne("SELECT * FROM customer_cmp WHERE tenant_id = 1 AND id = 1 AND name = 'new name'")
+}
+
+func (o *PersistsCompositeTS) TestCountWithJoins() {
+	o.persistsCustomer()
+
+	ctx := o.d3Orm.CtxWithSession(context.Background())
+	repository, err := o.d3Orm.MakeRepository((*CustomerCmp)(nil))
+	o.NoError(err)
+
+	q := repository.Select()
+	o.NoError(q.With("OrderCmp"))
+
+	count, err := repository.Count(ctx, q)
+	o.NoError(err)
+	o.Equal(int64(1), count)
+}
+
+func (o *PersistsCompositeTS) TestInsertDuplicate() {
+	o.persistsCustomer()
+
+	ctx := o.d3Orm.CtxWithSession(context.Background())
+	repository, err := o.d3Orm.MakeRepository((*ManagerCmp)(nil))
+	o.NoError(err)
+
+	o.NoError(repository.Persists(ctx, &ManagerCmp{TenantId: 1, Id: 2, Name: "duplicate"}))
+	err = orm.Session(ctx).Flush(ctx)
+	o.True(errors.Is(err, orm.ErrUniqueViolation))
+
+	var constraintErr *orm.ConstraintError
+	o.True(errors.As(err, &constraintErr))
+	o.Equal("manager_cmp", constraintErr.Table)
+	o.Equal(string(entity.NameFromEntity((*ManagerCmp)(nil))), constraintErr.Entity)
+}
+
+func (o *PersistsCompositeTS) TestDelete() {
+	o.persistsCustomer()
+
+	ctx := o.d3Orm.CtxWithSession(context.Background())
+	repository, err := o.d3Orm.MakeRepository((*CustomerCmp)(nil))
+	o.NoError(err)
+
+	customer, err := repository.FindOne(ctx, repository.Select().
+		Where("customer_cmp.tenant_id", "=", 1).
+		AndWhere("customer_cmp.id", "=", 1),
+	)
+	o.NoError(err)
+
+	// initialize lazy collection, cause cascade delete works only with loaded relations
+	o.Equal(2, customer.(*CustomerCmp).Orders.Count())
+
+	o.NoError(repository.Delete(ctx, customer))
+	o.NoError(orm.Session(ctx).Flush(ctx))
+
+	o.tester.
+		See(0, "SELECT * FROM customer_cmp").
+		See(0, "SELECT * FROM order_cmp").
+		See(0, "SELECT * FROM customer_tag_cmp").
+		SeeOne("SELECT * FROM manager_cmp")
+}
@@ -0,0 +1,39 @@
+package persist
+
+import (
+	"database/sql"
+	"github.com/godzie44/d3/orm/entity"
+)
+
+//d3:entity
+//d3_table:customer_cmp
+type CustomerCmp struct {
+	TenantId int32              `d3:"pk:manual"`
+	Id       int32              `d3:"pk:manual"`
+	Manager  *entity.Cell       `d3:"one_to_one:<target_entity:ManagerCmp,join_on:manager_tenant_id;manager_id,delete:nullable>,type:lazy"`
+	Orders   *entity.Collection `d3:"one_to_many:<target_entity:OrderCmp,join_on:tenant_id;customer_id,delete:cascade>,type:lazy"`
+	Tags     *entity.Collection `d3:"many_to_many:<target_entity:TagCmp,join_on:tenant_id;customer_id,reference_on:tag_id,join_table:customer_tag_cmp>,type:lazy"`
+	Name     string
+}
+
+//d3:entity
+//d3_table:manager_cmp
+type ManagerCmp struct {
+	TenantId int32 `d3:"pk:manual"`
+	Id       int32 `d3:"pk:manual"`
+	Name     string
+}
+
+//d3:entity
+//d3_table:order_cmp
+type OrderCmp struct {
+	Id    sql.NullInt32 `d3:"pk:auto"`
+	Total int32
+}
+
+//d3:entity
+//d3_table:tag_cmp
+type TagCmp struct {
+	Id   int32 `d3:"pk:manual"`
+	Name string
+}
@@ -0,0 +1,489 @@
+// Code generated by d3. DO NOT EDIT.
+
+package persist
+
+import "fmt"
+import "github.com/godzie44/d3/orm/entity"
+import "database/sql/driver"
+
+func (c *CustomerCmp) D3Token() entity.MetaToken {
+	return entity.MetaToken{
+		Tpl:       (*CustomerCmp)(nil),
+		TableName: "customer_cmp",
+		Tools: entity.InternalTools{
+			ExtractField:  c.__d3_makeFieldExtractor(),
+			SetFieldVal:   c.__d3_makeFieldSetter(),
+			CompareFields: c.__d3_makeComparator(),
+			NewInstance:   c.__d3_makeInstantiator(),
+			Copy:          c.__d3_makeCopier(),
+		},
+		Indexes: []entity.Index{},
+	}
+}
+
+func (c *CustomerCmp) __d3_makeFieldExtractor() entity.FieldExtractor {
+	return func(s interface{}, name string) (interface{}, error) {
+		sTyped, ok := s.(*CustomerCmp)
+		if !ok {
+			return nil, fmt.Errorf("invalid entity type")
+		}
+
+		switch name {
+
+		case "TenantId":
+			return sTyped.TenantId, nil
+
+		case "Id":
+			return sTyped.Id, nil
+
+		case "Manager":
+			return sTyped.Manager, nil
+
+		case "Orders":
+			return sTyped.Orders, nil
+
+		case "Tags":
+			return sTyped.Tags, nil
+
+		case "Name":
+			return sTyped.Name, nil
+
+		default:
+			return nil, fmt.Errorf("field %s not found", name)
+		}
+	}
+}
+
+func (c *CustomerCmp) __d3_makeInstantiator() entity.Instantiator {
+	return func() interface{} {
+		return &CustomerCmp{}
+	}
+}
+
+func (c *CustomerCmp) __d3_makeFieldSetter() entity.FieldSetter {
+	return func(s interface{}, name string, val interface{}) error {
+		eTyped, ok := s.(*CustomerCmp)
+		if !ok {
+			return fmt.Errorf("invalid entity type")
+		}
+
+		switch name {
+		case "TenantId":
+			eTyped.TenantId = val.(int32)
+			return nil
+		case "Id":
+			eTyped.Id = val.(int32)
+			return nil
+		case "Manager":
+			eTyped.Manager = val.(*entity.Cell)
+			return nil
+		case "Orders":
+			eTyped.Orders = val.(*entity.Collection)
+			return nil
+		case "Tags":
+			eTyped.Tags = val.(*entity.Collection)
+			return nil
+		case "Name":
+			eTyped.Name = val.(string)
+			return nil
+
+		default:
+			return fmt.Errorf("field %s not found", name)
+		}
+	}
+}
+
+func (c *CustomerCmp) __d3_makeCopier() entity.Copier {
+	return func(src interface{}) interface{} {
+		srcTyped, ok := src.(*CustomerCmp)
+		if !ok {
+			return fmt.Errorf("invalid entity type")
+		}
+
+		copy := &CustomerCmp{}
+
+		copy.TenantId = srcTyped.TenantId
+		copy.Id = srcTyped.Id
+		copy.Name = srcTyped.Name
+
+		if srcTyped.Manager != nil {
+			copy.Manager = srcTyped.Manager.DeepCopy().(*entity.Cell)
+		}
+		if srcTyped.Orders != nil {
+			copy.Orders = srcTyped.Orders.DeepCopy().(*entity.Collection)
+		}
+		if srcTyped.Tags != nil {
+			copy.Tags = srcTyped.Tags.DeepCopy().(*entity.Collection)
+		}
+
+		return copy
+	}
+}
+
+func (c *CustomerCmp) __d3_makeComparator() entity.FieldComparator {
+	return func(e1, e2 interface{}, fName string) bool {
+		if e1 == nil || e2 == nil {
+			return e1 == e2
+		}
+
+		e1Typed, ok := e1.(*CustomerCmp)
+		if !ok {
+			return false
+		}
+		e2Typed, ok := e2.(*CustomerCmp)
+		if !ok {
+			return false
+		}
+
+		switch fName {
+
+		case "TenantId":
+			return e1Typed.TenantId == e2Typed.TenantId
+		case "Id":
+			return e1Typed.Id == e2Typed.Id
+		case "Manager":
+			return e1Typed.Manager == e2Typed.Manager
+		case "Orders":
+			return e1Typed.Orders == e2Typed.Orders
+		case "Tags":
+			return e1Typed.Tags == e2Typed.Tags
+		case "Name":
+			return e1Typed.Name == e2Typed.Name
+		default:
+			return false
+		}
+	}
+}
+
+func (m *ManagerCmp) D3Token() entity.MetaToken {
+	return entity.MetaToken{
+		Tpl:       (*ManagerCmp)(nil),
+		TableName: "manager_cmp",
+		Tools: entity.InternalTools{
+			ExtractField:  m.__d3_makeFieldExtractor(),
+			SetFieldVal:   m.__d3_makeFieldSetter(),
+			CompareFields: m.__d3_makeComparator(),
+			NewInstance:   m.__d3_makeInstantiator(),
+			Copy:          m.__d3_makeCopier(),
+		},
+		Indexes: []entity.Index{},
+	}
+}
+
+func (m *ManagerCmp) __d3_makeFieldExtractor() entity.FieldExtractor {
+	return func(s interface{}, name string) (interface{}, error) {
+		sTyped, ok := s.(*ManagerCmp)
+		if !ok {
+			return nil, fmt.Errorf("invalid entity type")
+		}
+
+		switch name {
+
+		case "TenantId":
+			return sTyped.TenantId, nil
+
+		case "Id":
+			return sTyped.Id, nil
+
+		case "Name":
+			return sTyped.Name, nil
+
+		default:
+			return nil, fmt.Errorf("field %s not found", name)
+		}
+	}
+}
+
+func (m *ManagerCmp) __d3_makeInstantiator() entity.Instantiator {
+	return func() interface{} {
+		return &ManagerCmp{}
+	}
+}
+
+func (m *ManagerCmp) __d3_makeFieldSetter() entity.FieldSetter {
+	return func(s interface{}, name string, val interface{}) error {
+		eTyped, ok := s.(*ManagerCmp)
+		if !ok {
+			return fmt.Errorf("invalid entity type")
+		}
+
+		switch name {
+		case "TenantId":
+			eTyped.TenantId = val.(int32)
+			return nil
+		case "Id":
+			eTyped.Id = val.(int32)
+			return nil
+		case "Name":
+			eTyped.Name = val.(string)
+			return nil
+
+		default:
+			return fmt.Errorf("field %s not found", name)
+		}
+	}
+}
+
+func (m *ManagerCmp) __d3_makeCopier() entity.Copier {
+	return func(src interface{}) interface{} {
+		srcTyped, ok := src.(*ManagerCmp)
+		if !ok {
+			return fmt.Errorf("invalid entity type")
+		}
+
+		copy := &ManagerCmp{}
+
+		copy.TenantId = srcTyped.TenantId
+		copy.Id = srcTyped.Id
+		copy.Name = srcTyped.Name
+
+		return copy
+	}
+}
+
+func (m *ManagerCmp) __d3_makeComparator() entity.FieldComparator {
+	return func(e1, e2 interface{}, fName string) bool {
+		if e1 == nil || e2 == nil {
+			return e1 == e2
+		}
+
+		e1Typed, ok := e1.(*ManagerCmp)
+		if !ok {
+			return false
+		}
+		e2Typed, ok := e2.(*ManagerCmp)
+		if !ok {
+			return false
+		}
+
+		switch fName {
+
+		case "TenantId":
+			return e1Typed.TenantId == e2Typed.TenantId
+		case "Id":
+			return e1Typed.Id == e2Typed.Id
+		case "Name":
+			return e1Typed.Name == e2Typed.Name
+		default:
+			return false
+		}
+	}
+}
+
+func (o *OrderCmp) D3Token() entity.MetaToken {
+	return entity.MetaToken{
+		Tpl:       (*OrderCmp)(nil),
+		TableName: "order_cmp",
+		Tools: entity.InternalTools{
+			ExtractField:  o.__d3_makeFieldExtractor(),
+			SetFieldVal:   o.__d3_makeFieldSetter(),
+			CompareFields: o.__d3_makeComparator(),
+			NewInstance:   o.__d3_makeInstantiator(),
+			Copy:          o.__d3_makeCopier(),
+		},
+		Indexes: []entity.Index{},
+	}
+}
+
+func (o *OrderCmp) __d3_makeFieldExtractor() entity.FieldExtractor {
+	return func(s interface{}, name string) (interface{}, error) {
+		sTyped, ok := s.(*OrderCmp)
+		if !ok {
+			return nil, fmt.Errorf("invalid entity type")
+		}
+
+		switch name {
+
+		case "Id":
+			return sTyped.Id, nil
+
+		case "Total":
+			return sTyped.Total, nil
+
+		default:
+			return nil, fmt.Errorf("field %s not found", name)
+		}
+	}
+}
+
+func (o *OrderCmp) __d3_makeInstantiator() entity.Instantiator {
+	return func() interface{} {
+		return &OrderCmp{}
+	}
+}
+
+func (o *OrderCmp) __d3_makeFieldSetter() entity.FieldSetter {
+	return func(s interface{}, name string, val interface{}) error {
+		eTyped, ok := s.(*OrderCmp)
+		if !ok {
+			return fmt.Errorf("invalid entity type")
+		}
+
+		switch name {
+		case "Total":
+			eTyped.Total = val.(int32)
+			return nil
+
+		case "Id":
+			if valuer, isValuer := val.(driver.Valuer); isValuer {
+				v, err := valuer.Value()
+				if err != nil {
+					return eTyped.Id.Scan(nil)
+				}
+				return eTyped.Id.Scan(v)
+			}
+			return eTyped.Id.Scan(val)
+		default:
+			return fmt.Errorf("field %s not found", name)
+		}
+	}
+}
+
+func (o *OrderCmp) __d3_makeCopier() entity.Copier {
+	return func(src interface{}) interface{} {
+		srcTyped, ok := src.(*OrderCmp)
+		if !ok {
+			return fmt.Errorf("invalid entity type")
+		}
+
+		copy := &OrderCmp{}
+
+		copy.Id = srcTyped.Id
+		copy.Total = srcTyped.Total
+
+		return copy
+	}
+}
+
+func (o *OrderCmp) __d3_makeComparator() entity.FieldComparator {
+	return func(e1, e2 interface{}, fName string) bool {
+		if e1 == nil || e2 == nil {
+			return e1 == e2
+		}
+
+		e1Typed, ok := e1.(*OrderCmp)
+		if !ok {
+			return false
+		}
+		e2Typed, ok := e2.(*OrderCmp)
+		if !ok {
+			return false
+		}
+
+		switch fName {
+
+		case "Id":
+			return e1Typed.Id == e2Typed.Id
+		case "Total":
+			return e1Typed.Total == e2Typed.Total
+		default:
+			return false
+		}
+	}
+}
+
+func (t *TagCmp) D3Token() entity.MetaToken {
+	return entity.MetaToken{
+		Tpl:       (*TagCmp)(nil),
+		TableName: "tag_cmp",
+		Tools: entity.InternalTools{
+			ExtractField:  t.__d3_makeFieldExtractor(),
+			SetFieldVal:   t.__d3_makeFieldSetter(),
+			CompareFields: t.__d3_makeComparator(),
+			NewInstance:   t.__d3_makeInstantiator(),
+			Copy:          t.__d3_makeCopier(),
+		},
+		Indexes: []entity.Index{},
+	}
+}
+
+func (t *TagCmp) __d3_makeFieldExtractor() entity.FieldExtractor {
+	return func(s interface{}, name string) (interface{}, error) {
+		sTyped, ok := s.(*TagCmp)
+		if !ok {
+			return nil, fmt.Errorf("invalid entity type")
+		}
+
+		switch name {
+
+		case "Id":
+			return sTyped.Id, nil
+
+		case "Name":
+			return sTyped.Name, nil
+
+		default:
+			return nil, fmt.Errorf("field %s not found", name)
+		}
+	}
+}
+
+func (t *TagCmp) __d3_makeInstantiator() entity.Instantiator {
+	return func() interface{} {
+		return &TagCmp{}
+	}
+}
+
+func (t *TagCmp) __d3_makeFieldSetter() entity.FieldSetter {
+	return func(s interface{}, name string, val interface{}) error {
+		eTyped, ok := s.(*TagCmp)
+		if !ok {
+			return fmt.Errorf("invalid entity type")
+		}
+
+		switch name {
+		case "Id":
+			eTyped.Id = val.(int32)
+			return nil
+		case "Name":
+			eTyped.Name = val.(string)
+			return nil
+
+		default:
+			return fmt.Errorf("field %s not found", name)
+		}
+	}
+}
+
+func (t *TagCmp) __d3_makeCopier() entity.Copier {
+	return func(src interface{}) interface{} {
+		srcTyped, ok := src.(*TagCmp)
+		if !ok {
+			return fmt.Errorf("invalid entity type")
+		}
+
+		copy := &TagCmp{}
+
+		copy.Id = srcTyped.Id
+		copy.Name = srcTyped.Name
+
+		return copy
+	}
+}
+
+func (t *TagCmp) __d3_makeComparator() entity.FieldComparator {
+	return func(e1, e2 interface{}, fName string) bool {
+		if e1 == nil || e2 == nil {
+			return e1 == e2
+		}
+
+		e1Typed, ok := e1.(*TagCmp)
+		if !ok {
+			return false
+		}
+		e2Typed, ok := e2.(*TagCmp)
+		if !ok {
+			return false
+		}
+
+		switch fName {
+
+		case "Id":
+			return e1Typed.Id == e2Typed.Id
+		case "Name":
+			return e1Typed.Name == e2Typed.Name
+		default:
+			return false
+		}
+	}
+}
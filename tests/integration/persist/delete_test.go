@@ -38,9 +38,9 @@ func (d *DeleteTS) SetupTest() {
 
 func (d *DeleteTS) TearDownSuite() {
 	d.NoError(d.execSqlFn(`
+DROP TABLE book_author_p;
 DROP TABLE book_p;
 DROP TABLE author_p;
-DROP TABLE book_author_p;
 DROP TABLE shop_p;
 DROP TABLE profile_p;
 `))
@@ -80,6 +80,18 @@ func TestSQLiteDeleteTestSuite(t *testing.T) {
 	suite.Run(t, ts)
 }
 
+func TestMySQLDeleteTestSuite(t *testing.T) {
+	adapter, d3orm, execSqlFn, _ := db.CreateMySQLTestComponents(t)
+
+	ts := &DeleteTS{
+		d3Orm:     d3orm,
+		dbAdapter: adapter,
+		execSqlFn: execSqlFn,
+	}
+
+	suite.Run(t, ts)
+}
+
 func (d *DeleteTS) TestDeleteEntity() {
 	rep, err := d.d3Orm.MakeRepository((*ShopProfile)(nil))
 	d.NoError(err)
@@ -89,7 +101,7 @@ func (d *DeleteTS) TestDeleteEntity() {
 
 	d.NoError(rep.Delete(d.ctx, profile))
 
-	d.NoError(orm.Session(d.ctx).Flush())
+	d.NoError(orm.Session(d.ctx).Flush(d.ctx))
 
 	d.Equal(1, d.dbAdapter.DeleteCounter())
 }
@@ -104,7 +116,7 @@ func (d *DeleteTS) TestDeleteWithRelations() {
 	d.NoError(rep.Delete(d.ctx, shop))
 
 	d.dbAdapter.ResetCounters()
-	d.NoError(orm.Session(d.ctx).Flush())
+	d.NoError(orm.Session(d.ctx).Flush(d.ctx))
 
 	// delete shop and profile (cause cascade)
 	d.Equal(2, d.dbAdapter.DeleteCounter())
@@ -123,7 +135,7 @@ func (d *DeleteTS) TestDeleteWithManyToMany() {
 	d.NoError(rep.Delete(d.ctx, book))
 
 	d.dbAdapter.ResetCounters()
-	d.NoError(orm.Session(d.ctx).Flush())
+	d.NoError(orm.Session(d.ctx).Flush(d.ctx))
 
 	// delete from book_p table and book_author_p table
 	d.Equal(2, d.dbAdapter.DeleteCounter())
@@ -0,0 +1,117 @@
+package persist
+
+import (
+	"context"
+	"github.com/godzie44/d3/orm"
+	"github.com/godzie44/d3/tests/helpers"
+	"github.com/godzie44/d3/tests/helpers/db"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type domainEventsCollector struct {
+	events []interface{}
+}
+
+func (d *domainEventsCollector) HandleDomainEvents(_ context.Context, events []interface{}) {
+	d.events = append(d.events, events...)
+}
+
+type DomainEventsTS struct {
+	suite.Suite
+	tester    helpers.DBTester
+	d3Orm     *orm.Orm
+	execSqlFn func(sql string) error
+	collector *domainEventsCollector
+}
+
+func (o *DomainEventsTS) SetupSuite() {
+	o.NoError(o.d3Orm.Register((*Order)(nil)))
+	o.collector = &domainEventsCollector{}
+	o.NoError(o.d3Orm.AddListener(o.collector))
+
+	schemaSql, err := o.d3Orm.GenerateSchema()
+	o.NoError(err)
+
+	o.NoError(o.execSqlFn(schemaSql))
+}
+
+func (o *DomainEventsTS) TearDownSuite() {
+	o.NoError(o.execSqlFn(`DROP TABLE order_de;`))
+}
+
+func (o *DomainEventsTS) TearDownTest() {
+	o.collector.events = nil
+	o.NoError(o.execSqlFn(`delete from order_de;`))
+}
+
+func TestPGDomainEventsSuite(t *testing.T) {
+	_, d3orm, execSqlFn, tester := db.CreatePGTestComponents(t)
+
+	suite.Run(t, &DomainEventsTS{
+		d3Orm:     d3orm,
+		execSqlFn: execSqlFn,
+		tester:    tester,
+	})
+}
+
+func TestSQLiteDomainEventsSuite(t *testing.T) {
+	_, d3orm, execSqlFn, tester := db.CreateSQLiteTestComponents(t, "_domain_events")
+
+	suite.Run(t, &DomainEventsTS{
+		d3Orm:     d3orm,
+		execSqlFn: execSqlFn,
+		tester:    tester,
+	})
+}
+
+func (o *DomainEventsTS) TestEventsDispatchedAfterCommit() {
+	repository, err := o.d3Orm.MakeRepository((*Order)(nil))
+	o.NoError(err)
+
+	ctx := o.d3Orm.CtxWithSession(context.Background())
+	order := NewOrder("1")
+	o.NoError(repository.Persists(ctx, order))
+	o.NoError(orm.Session(ctx).Flush(ctx))
+
+	o.Equal([]interface{}{OrderPlaced{Number: "1"}}, o.collector.events)
+
+	order.Ship()
+	o.NoError(orm.Session(ctx).Flush(ctx))
+	o.NoError(orm.Session(ctx).Flush(ctx))
+
+	o.Equal([]interface{}{OrderPlaced{Number: "1"}, OrderShipped{Number: "1"}}, o.collector.events)
+	o.tester.SeeOne("SELECT * FROM order_de WHERE status = 'shipped'")
+}
+
+func (o *DomainEventsTS) TestEventsDispatchedAfterManualCommit() {
+	repository, err := o.d3Orm.MakeRepository((*Order)(nil))
+	o.NoError(err)
+
+	ctx := o.d3Orm.CtxWithSession(context.Background())
+	session := orm.Session(ctx)
+
+	o.NoError(session.BeginTx(ctx))
+	o.NoError(repository.Persists(ctx, NewOrder("1")))
+	o.NoError(session.Flush(ctx))
+	o.Empty(o.collector.events)
+
+	o.NoError(session.CommitTx())
+	o.Equal([]interface{}{OrderPlaced{Number: "1"}}, o.collector.events)
+}
+
+func (o *DomainEventsTS) TestEventsDroppedOnRollback() {
+	repository, err := o.d3Orm.MakeRepository((*Order)(nil))
+	o.NoError(err)
+
+	ctx := o.d3Orm.CtxWithSession(context.Background())
+	session := orm.Session(ctx)
+
+	o.NoError(session.BeginTx(ctx))
+	o.NoError(repository.Persists(ctx, NewOrder("1")))
+	o.NoError(session.Flush(ctx))
+	o.NoError(session.RollbackTx())
+
+	o.Empty(o.collector.events)
+	o.tester.See(0, "SELECT * FROM order_de")
+}
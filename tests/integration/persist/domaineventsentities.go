@@ -0,0 +1,37 @@
+package persist
+
+import (
+	"database/sql"
+)
+
+type OrderPlaced struct {
+	Number string
+}
+
+type OrderShipped struct {
+	Number string
+}
+
+//d3:entity
+//d3_table:order_de
+type Order struct {
+	Id     sql.NullInt32 `d3:"pk:auto"`
+	Number string
+	Status string
+	events []interface{} `d3:"-"`
+}
+
+func NewOrder(number string) *Order {
+	return &Order{Number: number, Status: "placed", events: []interface{}{OrderPlaced{Number: number}}}
+}
+
+func (o *Order) Ship() {
+	o.Status = "shipped"
+	o.events = append(o.events, OrderShipped{Number: o.Number})
+}
+
+func (o *Order) ReleaseEvents() []interface{} {
+	events := o.events
+	o.events = nil
+	return events
+}
@@ -0,0 +1,129 @@
+// Code generated by d3. DO NOT EDIT.
+
+package persist
+
+import "database/sql/driver"
+import "fmt"
+import "github.com/godzie44/d3/orm/entity"
+
+func (o *Order) D3Token() entity.MetaToken {
+	return entity.MetaToken{
+		Tpl:       (*Order)(nil),
+		TableName: "order_de",
+		Tools: entity.InternalTools{
+			ExtractField:  o.__d3_makeFieldExtractor(),
+			SetFieldVal:   o.__d3_makeFieldSetter(),
+			CompareFields: o.__d3_makeComparator(),
+			NewInstance:   o.__d3_makeInstantiator(),
+			Copy:          o.__d3_makeCopier(),
+		},
+		Indexes: []entity.Index{},
+	}
+}
+
+func (o *Order) __d3_makeFieldExtractor() entity.FieldExtractor {
+	return func(s interface{}, name string) (interface{}, error) {
+		sTyped, ok := s.(*Order)
+		if !ok {
+			return nil, fmt.Errorf("invalid entity type")
+		}
+
+		switch name {
+
+		case "Id":
+			return sTyped.Id, nil
+
+		case "Number":
+			return sTyped.Number, nil
+
+		case "Status":
+			return sTyped.Status, nil
+
+		default:
+			return nil, fmt.Errorf("field %s not found", name)
+		}
+	}
+}
+
+func (o *Order) __d3_makeInstantiator() entity.Instantiator {
+	return func() interface{} {
+		return &Order{}
+	}
+}
+
+func (o *Order) __d3_makeFieldSetter() entity.FieldSetter {
+	return func(s interface{}, name string, val interface{}) error {
+		eTyped, ok := s.(*Order)
+		if !ok {
+			return fmt.Errorf("invalid entity type")
+		}
+
+		switch name {
+		case "Number":
+			eTyped.Number = val.(string)
+			return nil
+		case "Status":
+			eTyped.Status = val.(string)
+			return nil
+
+		case "Id":
+			if valuer, isValuer := val.(driver.Valuer); isValuer {
+				v, err := valuer.Value()
+				if err != nil {
+					return eTyped.Id.Scan(nil)
+				}
+				return eTyped.Id.Scan(v)
+			}
+			return eTyped.Id.Scan(val)
+		default:
+			return fmt.Errorf("field %s not found", name)
+		}
+	}
+}
+
+func (o *Order) __d3_makeCopier() entity.Copier {
+	return func(src interface{}) interface{} {
+		srcTyped, ok := src.(*Order)
+		if !ok {
+			return fmt.Errorf("invalid entity type")
+		}
+
+		copy := &Order{}
+
+		copy.Id = srcTyped.Id
+		copy.Number = srcTyped.Number
+		copy.Status = srcTyped.Status
+		copy.events = srcTyped.events
+
+		return copy
+	}
+}
+
+func (o *Order) __d3_makeComparator() entity.FieldComparator {
+	return func(e1, e2 interface{}, fName string) bool {
+		if e1 == nil || e2 == nil {
+			return e1 == e2
+		}
+
+		e1Typed, ok := e1.(*Order)
+		if !ok {
+			return false
+		}
+		e2Typed, ok := e2.(*Order)
+		if !ok {
+			return false
+		}
+
+		switch fName {
+
+		case "Id":
+			return e1Typed.Id == e2Typed.Id
+		case "Number":
+			return e1Typed.Number == e2Typed.Number
+		case "Status":
+			return e1Typed.Status == e2Typed.Status
+		default:
+			return false
+		}
+	}
+}
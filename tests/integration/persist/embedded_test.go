@@ -0,0 +1,108 @@
+package persist
+
+import (
+	"context"
+	"database/sql"
+	"github.com/godzie44/d3/orm"
+	"github.com/godzie44/d3/tests/helpers"
+	"github.com/godzie44/d3/tests/helpers/db"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type PersistsEmbeddedTS struct {
+	suite.Suite
+	tester    helpers.DBTester
+	dbAdapter *helpers.DbAdapterWithQueryCounter
+	d3Orm     *orm.Orm
+	ctx       context.Context
+	execSqlFn func(sql string) error
+}
+
+func (o *PersistsEmbeddedTS) SetupSuite() {
+	o.NoError(o.d3Orm.Register((*Warehouse)(nil)))
+
+	schemaSql, err := o.d3Orm.GenerateSchema()
+	o.NoError(err)
+
+	o.NoError(o.execSqlFn(schemaSql))
+}
+
+func (o *PersistsEmbeddedTS) SetupTest() {
+	o.ctx = o.d3Orm.CtxWithSession(context.Background())
+}
+
+func (o *PersistsEmbeddedTS) TearDownSuite() {
+	o.NoError(o.execSqlFn(`DROP TABLE warehouse_emb;`))
+}
+
+func (o *PersistsEmbeddedTS) TearDownTest() {
+	o.dbAdapter.ResetCounters()
+	o.NoError(o.execSqlFn(`delete from warehouse_emb;`))
+}
+
+func TestPGPersistsEmbeddedSuite(t *testing.T) {
+	adapter, d3orm, execSqlFn, tester := db.CreatePGTestComponents(t)
+
+	ts := &PersistsEmbeddedTS{
+		dbAdapter: adapter,
+		d3Orm:     d3orm,
+		execSqlFn: execSqlFn,
+		tester:    tester,
+	}
+	suite.Run(t, ts)
+}
+
+func TestSQLitePersistsEmbeddedSuite(t *testing.T) {
+	adapter, d3orm, execSqlFn, tester := db.CreateSQLiteTestComponents(t, "_embedded")
+
+	ts := &PersistsEmbeddedTS{
+		d3Orm:     d3orm,
+		dbAdapter: adapter,
+		execSqlFn: execSqlFn,
+		tester:    tester,
+	}
+	suite.Run(t, ts)
+}
+
+func (o *PersistsEmbeddedTS) TestInsert() {
+	repository, err := o.d3Orm.MakeRepository((*Warehouse)(nil))
+	o.NoError(err)
+
+	warehouse := &Warehouse{
+		Name:    "warehouse",
+		Rent:    Money{Amount: 100, Currency: "USD"},
+		Address: Address{City: "Moscow", Street: sql.NullString{String: "Arbat", Valid: true}},
+	}
+
+	o.NoError(repository.Persists(o.ctx, warehouse))
+	o.NoError(orm.Session(o.ctx).Flush(o.ctx))
+
+	o.tester.SeeOne("SELECT * FROM warehouse_emb WHERE rent_amount = 100 AND rent_currency = 'USD' AND addr_city = 'Moscow' AND addr_street = 'Arbat'")
+}
+
+func (o *PersistsEmbeddedTS) TestFetchAndUpdate() {
+	repository, err := o.d3Orm.MakeRepository((*Warehouse)(nil))
+	o.NoError(err)
+
+	o.NoError(repository.Persists(o.ctx, &Warehouse{
+		Name: "warehouse",
+		Rent: Money{Amount: 100, Currency: "USD"},
+	}))
+	o.NoError(orm.Session(o.ctx).Flush(o.ctx))
+
+	ctx := o.d3Orm.CtxWithSession(context.Background())
+	fetched, err := repository.FindOne(ctx, repository.Select().Where("warehouse_emb.rent_currency", "=", "USD"))
+	o.NoError(err)
+
+	warehouse := fetched.(*Warehouse)
+	o.Equal(Money{Amount: 100, Currency: "USD"}, warehouse.Rent)
+	o.False(warehouse.Address.Street.Valid)
+
+	warehouse.Rent.Amount = 200
+	o.dbAdapter.ResetCounters()
+	o.NoError(orm.Session(ctx).Flush(ctx))
+
+	o.Equal(1, o.dbAdapter.UpdateCounter())
+	o.tester.SeeOne("SELECT * FROM warehouse_emb WHERE rent_amount = 200 AND rent_currency = 'USD'")
+}
@@ -0,0 +1,24 @@
+package persist
+
+import (
+	"database/sql"
+)
+
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+type Address struct {
+	City   string
+	Street sql.NullString
+}
+
+//d3:entity
+//d3_table:warehouse_emb
+type Warehouse struct {
+	Id      sql.NullInt32 `d3:"pk:auto"`
+	Name    string
+	Rent    Money   `d3:"embedded:<>"`
+	Address Address `d3:"embedded:<prefix:addr_>"`
+}
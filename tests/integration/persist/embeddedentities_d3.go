@@ -0,0 +1,175 @@
+// Code generated by d3. DO NOT EDIT.
+
+package persist
+
+import "fmt"
+import "github.com/godzie44/d3/orm/entity"
+import "database/sql/driver"
+
+func (w *Warehouse) D3Token() entity.MetaToken {
+	return entity.MetaToken{
+		Tpl:       (*Warehouse)(nil),
+		TableName: "warehouse_emb",
+		Tools: entity.InternalTools{
+			ExtractField:  w.__d3_makeFieldExtractor(),
+			SetFieldVal:   w.__d3_makeFieldSetter(),
+			CompareFields: w.__d3_makeComparator(),
+			NewInstance:   w.__d3_makeInstantiator(),
+			Copy:          w.__d3_makeCopier(),
+		},
+		Indexes: []entity.Index{},
+	}
+}
+
+func (w *Warehouse) __d3_makeFieldExtractor() entity.FieldExtractor {
+	return func(s interface{}, name string) (interface{}, error) {
+		sTyped, ok := s.(*Warehouse)
+		if !ok {
+			return nil, fmt.Errorf("invalid entity type")
+		}
+
+		switch name {
+
+		case "Id":
+			return sTyped.Id, nil
+
+		case "Name":
+			return sTyped.Name, nil
+
+		case "Rent":
+			return sTyped.Rent, nil
+
+		case "Rent.Amount":
+			return sTyped.Rent.Amount, nil
+
+		case "Rent.Currency":
+			return sTyped.Rent.Currency, nil
+
+		case "Address":
+			return sTyped.Address, nil
+
+		case "Address.City":
+			return sTyped.Address.City, nil
+
+		case "Address.Street":
+			return sTyped.Address.Street, nil
+
+		default:
+			return nil, fmt.Errorf("field %s not found", name)
+		}
+	}
+}
+
+func (w *Warehouse) __d3_makeInstantiator() entity.Instantiator {
+	return func() interface{} {
+		return &Warehouse{}
+	}
+}
+
+func (w *Warehouse) __d3_makeFieldSetter() entity.FieldSetter {
+	return func(s interface{}, name string, val interface{}) error {
+		eTyped, ok := s.(*Warehouse)
+		if !ok {
+			return fmt.Errorf("invalid entity type")
+		}
+
+		switch name {
+		case "Name":
+			eTyped.Name = val.(string)
+			return nil
+		case "Rent":
+			eTyped.Rent = val.(Money)
+			return nil
+		case "Rent.Amount":
+			eTyped.Rent.Amount = val.(int64)
+			return nil
+		case "Rent.Currency":
+			eTyped.Rent.Currency = val.(string)
+			return nil
+		case "Address":
+			eTyped.Address = val.(Address)
+			return nil
+		case "Address.City":
+			eTyped.Address.City = val.(string)
+			return nil
+
+		case "Id":
+			if valuer, isValuer := val.(driver.Valuer); isValuer {
+				v, err := valuer.Value()
+				if err != nil {
+					return eTyped.Id.Scan(nil)
+				}
+				return eTyped.Id.Scan(v)
+			}
+			return eTyped.Id.Scan(val)
+		case "Address.Street":
+			if valuer, isValuer := val.(driver.Valuer); isValuer {
+				v, err := valuer.Value()
+				if err != nil {
+					return eTyped.Address.Street.Scan(nil)
+				}
+				return eTyped.Address.Street.Scan(v)
+			}
+			return eTyped.Address.Street.Scan(val)
+		default:
+			return fmt.Errorf("field %s not found", name)
+		}
+	}
+}
+
+func (w *Warehouse) __d3_makeCopier() entity.Copier {
+	return func(src interface{}) interface{} {
+		srcTyped, ok := src.(*Warehouse)
+		if !ok {
+			return fmt.Errorf("invalid entity type")
+		}
+
+		copy := &Warehouse{}
+
+		copy.Id = srcTyped.Id
+		copy.Name = srcTyped.Name
+		copy.Rent = srcTyped.Rent
+		copy.Address = srcTyped.Address
+
+		return copy
+	}
+}
+
+func (w *Warehouse) __d3_makeComparator() entity.FieldComparator {
+	return func(e1, e2 interface{}, fName string) bool {
+		if e1 == nil || e2 == nil {
+			return e1 == e2
+		}
+
+		e1Typed, ok := e1.(*Warehouse)
+		if !ok {
+			return false
+		}
+		e2Typed, ok := e2.(*Warehouse)
+		if !ok {
+			return false
+		}
+
+		switch fName {
+
+		case "Id":
+			return e1Typed.Id == e2Typed.Id
+		case "Name":
+			return e1Typed.Name == e2Typed.Name
+		case "Rent":
+			return e1Typed.Rent == e2Typed.Rent
+		case "Rent.Amount":
+			return e1Typed.Rent.Amount == e2Typed.Rent.Amount
+		case "Rent.Currency":
+			return e1Typed.Rent.Currency == e2Typed.Rent.Currency
+		case "Address":
+			return e1Typed.Address == e2Typed.Address
+		case "Address.City":
+			return e1Typed.Address.City == e2Typed.Address.City
+		case "Address.Street":
+			return e1Typed.Address.Street == e2Typed.Address.Street
+		default:
+			return false
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package persist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/godzie44/d3/orm"
+	"github.com/godzie44/d3/tests/helpers"
+	"github.com/godzie44/d3/tests/helpers/db"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+var errFlushForbidden = errors.New("flush forbidden")
+
+type recordingListener struct {
+	events      []string
+	forbidFlush bool
+}
+
+func (r *recordingListener) OnFlush(_ context.Context, changeSet orm.ChangeSet) error {
+	if r.forbidFlush {
+		return errFlushForbidden
+	}
+	r.events = append(r.events, fmt.Sprintf("flush %d/%d/%d", len(changeSet.Inserted), len(changeSet.Updated), len(changeSet.Deleted)))
+	return nil
+}
+
+func (r *recordingListener) PostInsert(_ context.Context, entity interface{}) {
+	r.events = append(r.events, "insert "+entity.(*Document).Title)
+}
+
+func (r *recordingListener) PostUpdate(_ context.Context, entity interface{}, changedFields []string) {
+	r.events = append(r.events, fmt.Sprintf("update %s %v", entity.(*Document).Title, changedFields))
+}
+
+func (r *recordingListener) PostRemove(_ context.Context, entity interface{}) {
+	r.events = append(r.events, "remove "+entity.(*Document).Title)
+}
+
+func (r *recordingListener) PostLoad(_ context.Context, entity interface{}) {
+	r.events = append(r.events, "load "+entity.(*Document).Title)
+}
+
+func (r *recordingListener) AfterCommit(_ context.Context) {
+	r.events = append(r.events, "commit")
+}
+
+func (r *recordingListener) AfterRollback(_ context.Context) {
+	r.events = append(r.events, "rollback")
+}
+
+type SessionEventsTS struct {
+	suite.Suite
+	tester    helpers.DBTester
+	d3Orm     *orm.Orm
+	execSqlFn func(sql string) error
+	listener  *recordingListener
+}
+
+func (o *SessionEventsTS) SetupSuite() {
+	o.NoError(o.d3Orm.Register((*Document)(nil)))
+	o.listener = &recordingListener{}
+	o.NoError(o.d3Orm.AddListener(o.listener))
+	o.Error(o.d3Orm.AddListener(struct{}{}))
+
+	schemaSql, err := o.d3Orm.GenerateSchema()
+	o.NoError(err)
+
+	o.NoError(o.execSqlFn(schemaSql))
+}
+
+func (o *SessionEventsTS) TearDownSuite() {
+	o.NoError(o.execSqlFn(`DROP TABLE document_ver;`))
+}
+
+func (o *SessionEventsTS) TearDownTest() {
+	o.listener.events = nil
+	o.listener.forbidFlush = false
+	o.NoError(o.execSqlFn(`delete from document_ver;`))
+}
+
+func TestPGSessionEventsSuite(t *testing.T) {
+	_, d3orm, execSqlFn, tester := db.CreatePGTestComponents(t)
+
+	suite.Run(t, &SessionEventsTS{
+		d3Orm:     d3orm,
+		execSqlFn: execSqlFn,
+		tester:    tester,
+	})
+}
+
+func TestSQLiteSessionEventsSuite(t *testing.T) {
+	_, d3orm, execSqlFn, tester := db.CreateSQLiteTestComponents(t, "_events")
+
+	suite.Run(t, &SessionEventsTS{
+		d3Orm:     d3orm,
+		execSqlFn: execSqlFn,
+		tester:    tester,
+	})
+}
+
+func (o *SessionEventsTS) TestFlushEvents() {
+	repository, err := o.d3Orm.MakeRepository((*Document)(nil))
+	o.NoError(err)
+
+	ctx := o.d3Orm.CtxWithSession(context.Background())
+	o.NoError(repository.Persists(ctx, &Document{Title: "draft"}))
+	o.NoError(orm.Session(ctx).Flush(ctx))
+	o.Equal([]string{"flush 1/0/0", "insert draft", "commit"}, o.listener.events)
+	o.listener.events = nil
+
+	ctx = o.d3Orm.CtxWithSession(context.Background())
+	fetched, err := repository.FindOne(ctx, repository.Select())
+	o.NoError(err)
+	doc := fetched.(*Document)
+
+	doc.Title = "final"
+	o.NoError(orm.Session(ctx).Flush(ctx))
+
+	o.NoError(repository.Delete(ctx, doc))
+	o.NoError(orm.Session(ctx).Flush(ctx))
+
+	o.Equal([]string{
+		"load draft",
+		"flush 0/1/0", "update final [Title Version]", "commit",
+		"flush 0/0/1", "remove final", "commit",
+	}, o.listener.events)
+	o.tester.See(0, "SELECT * FROM document_ver")
+}
+
+func (o *SessionEventsTS) TestManualTransactionEvents() {
+	repository, err := o.d3Orm.MakeRepository((*Document)(nil))
+	o.NoError(err)
+
+	ctx := o.d3Orm.CtxWithSession(context.Background())
+	session := orm.Session(ctx)
+
+	o.NoError(session.BeginTx(ctx))
+	o.NoError(repository.Persists(ctx, &Document{Title: "draft"}))
+	o.NoError(session.Flush(ctx))
+	o.NoError(session.RollbackTx())
+
+	o.NoError(session.BeginTx(ctx))
+	o.NoError(repository.Persists(ctx, &Document{Title: "draft"}))
+	o.NoError(session.Flush(ctx))
+	o.NoError(session.CommitTx())
+
+	o.Equal([]string{"flush 1/0/0", "insert draft", "rollback", "flush 1/0/0", "insert draft", "commit"}, o.listener.events)
+	o.tester.SeeOne("SELECT * FROM document_ver")
+}
+
+func (o *SessionEventsTS) TestFlushAbortedByListener() {
+	repository, err := o.d3Orm.MakeRepository((*Document)(nil))
+	o.NoError(err)
+
+	o.listener.forbidFlush = true
+
+	ctx := o.d3Orm.CtxWithSession(context.Background())
+	o.NoError(repository.Persists(ctx, &Document{Title: "draft"}))
+	o.True(errors.Is(orm.Session(ctx).Flush(ctx), errFlushForbidden))
+
+	o.Empty(o.listener.events)
+	o.tester.See(0, "SELECT * FROM document_ver")
+}
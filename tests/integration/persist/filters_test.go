@@ -0,0 +1,176 @@
+package persist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/godzie44/d3/orm"
+	"github.com/godzie44/d3/orm/entity"
+	"github.com/godzie44/d3/tests/helpers"
+	"github.com/godzie44/d3/tests/helpers/db"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type FiltersTS struct {
+	suite.Suite
+	tester    helpers.DBTester
+	d3Orm     *orm.Orm
+	execSqlFn func(sql string) error
+}
+
+func (f *FiltersTS) SetupSuite() {
+	f.NoError(f.d3Orm.Register((*ProjectMT)(nil), (*TaskMT)(nil)))
+	f.NoError(f.d3Orm.AddFilter("tenant", "TenantId"))
+
+	schemaSql, err := f.d3Orm.GenerateSchema()
+	f.NoError(err)
+
+	f.NoError(f.execSqlFn(schemaSql))
+}
+
+func (f *FiltersTS) TearDownSuite() {
+	f.NoError(f.execSqlFn(`
+DROP TABLE task_mt;
+DROP TABLE project_mt;
+`))
+}
+
+func (f *FiltersTS) TearDownTest() {
+	f.NoError(f.execSqlFn(`
+delete from task_mt;
+delete from project_mt;
+`))
+}
+
+func TestPGFiltersSuite(t *testing.T) {
+	_, d3orm, execSqlFn, tester := db.CreatePGTestComponents(t)
+
+	suite.Run(t, &FiltersTS{
+		d3Orm:     d3orm,
+		execSqlFn: execSqlFn,
+		tester:    tester,
+	})
+}
+
+func TestSQLiteFiltersSuite(t *testing.T) {
+	_, d3orm, execSqlFn, tester := db.CreateSQLiteTestComponents(t, "_filters")
+
+	suite.Run(t, &FiltersTS{
+		d3Orm:     d3orm,
+		execSqlFn: execSqlFn,
+		tester:    tester,
+	})
+}
+
+func (f *FiltersTS) tenantCtx(tenant string) context.Context {
+	ctx := f.d3Orm.CtxWithSession(context.Background())
+	f.NoError(orm.Session(ctx).EnableFilter("tenant", tenant))
+	return ctx
+}
+
+func (f *FiltersTS) createProject(tenant, name string, tasks ...interface{}) *ProjectMT {
+	repository, err := f.d3Orm.MakeRepository((*ProjectMT)(nil))
+	f.NoError(err)
+
+	ctx := f.tenantCtx(tenant)
+	project := &ProjectMT{Name: name, Tasks: entity.NewCollection(tasks...)}
+	f.NoError(repository.Persists(ctx, project))
+	f.NoError(orm.Session(ctx).Flush(ctx))
+
+	return project
+}
+
+func (f *FiltersTS) TestFilteredFieldFilledOnInsert() {
+	f.createProject("acme", "rocket", &TaskMT{Title: "engine"}, &TaskMT{Title: "fuel", TenantId: "other"})
+
+	f.tester.SeeOne("SELECT * FROM project_mt WHERE tenant_id = 'acme'")
+	f.tester.SeeOne("SELECT * FROM task_mt WHERE tenant_id = 'acme' AND title = 'engine'")
+	f.tester.SeeOne("SELECT * FROM task_mt WHERE tenant_id = 'other' AND title = 'fuel'")
+}
+
+func (f *FiltersTS) TestQueriesFiltered() {
+	f.createProject("acme", "rocket")
+	f.createProject("globex", "satellite")
+
+	repository, err := f.d3Orm.MakeRepository((*ProjectMT)(nil))
+	f.NoError(err)
+
+	ctx := f.tenantCtx("acme")
+	projects, err := repository.FindAll(ctx, repository.Select())
+	f.NoError(err)
+	f.Equal(1, projects.Count())
+	f.Equal("rocket", projects.Get(0).(*ProjectMT).Name)
+
+	orm.Session(ctx).DisableFilter("tenant")
+	projects, err = repository.FindAll(ctx, repository.Select())
+	f.NoError(err)
+	f.Equal(2, projects.Count())
+}
+
+func (f *FiltersTS) TestRelationsFiltered() {
+	project := f.createProject("acme", "rocket", &TaskMT{Title: "engine"})
+	f.NoError(f.execSqlFn(fmt.Sprintf(
+		"INSERT INTO task_mt(tenant_id, title, project_id) VALUES ('globex', 'spy', %d)", project.Id.Int32,
+	)))
+
+	repository, err := f.d3Orm.MakeRepository((*ProjectMT)(nil))
+	f.NoError(err)
+
+	ctx := f.tenantCtx("acme")
+	fetched, err := repository.FindOne(ctx, repository.Select().Where("project_mt.id", "=", project.Id))
+	f.NoError(err)
+	f.Equal(1, fetched.(*ProjectMT).Tasks.Count())
+
+	ctx = f.tenantCtx("acme")
+	q := repository.Select()
+	f.NoError(q.With("TaskMT"))
+	fetched, err = repository.FindOne(ctx, q.Where("project_mt.id", "=", project.Id))
+	f.NoError(err)
+	f.Equal(1, fetched.(*ProjectMT).Tasks.Count())
+	f.Equal("engine", fetched.(*ProjectMT).Tasks.Get(0).(*TaskMT).Title)
+}
+
+func (f *FiltersTS) TestIdentityMapFiltered() {
+	project := f.createProject("acme", "rocket")
+
+	repository, err := f.d3Orm.MakeRepository((*ProjectMT)(nil))
+	f.NoError(err)
+
+	ctx := f.d3Orm.CtxWithSession(context.Background())
+	_, err = repository.FindOne(ctx, repository.Select().Where("project_mt.id", "=", project.Id))
+	f.NoError(err)
+
+	f.NoError(orm.Session(ctx).EnableFilter("tenant", "globex"))
+	_, err = repository.FindOne(ctx, repository.Select().Where("project_mt.id", "=", project.Id))
+	f.True(errors.Is(err, orm.ErrEntityNotFound))
+
+	f.NoError(orm.Session(ctx).EnableFilter("tenant", "acme"))
+	fetched, err := repository.FindOne(ctx, repository.Select().Where("project_mt.id", "=", project.Id))
+	f.NoError(err)
+	f.Equal("rocket", fetched.(*ProjectMT).Name)
+}
+
+func (f *FiltersTS) TestFiltersNotChangeQuery() {
+	f.createProject("acme", "rocket")
+	f.createProject("globex", "satellite")
+
+	repository, err := f.d3Orm.MakeRepository((*ProjectMT)(nil))
+	f.NoError(err)
+
+	ctx := f.tenantCtx("acme")
+	q := repository.Select()
+	projects, err := repository.FindAll(ctx, q)
+	f.NoError(err)
+	f.Equal(1, projects.Count())
+
+	orm.Session(ctx).DisableFilter("tenant")
+	projects, err = repository.FindAll(ctx, q)
+	f.NoError(err)
+	f.Equal(2, projects.Count())
+}
+
+func (f *FiltersTS) TestEnableUnknownFilter() {
+	ctx := f.d3Orm.CtxWithSession(context.Background())
+	f.True(errors.Is(orm.Session(ctx).EnableFilter("unknown", 1), orm.ErrUnknownFilter))
+}
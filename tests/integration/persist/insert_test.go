@@ -39,9 +39,9 @@ func (o *PersistsTS) SetupTest() {
 
 func (o *PersistsTS) TearDownSuite() {
 	o.NoError(o.execSqlFn(`
+DROP TABLE book_author_p;
 DROP TABLE book_p;
 DROP TABLE author_p;
-DROP TABLE book_author_p;
 DROP TABLE shop_p;
 DROP TABLE profile_p;
 `))
@@ -82,6 +82,18 @@ func TestSQLitePersistsSuite(t *testing.T) {
 	suite.Run(t, ts)
 }
 
+func TestMySQLPersistsSuite(t *testing.T) {
+	adapter, d3orm, execSqlFn, tester := db.CreateMySQLTestComponents(t)
+
+	ts := &PersistsTS{
+		d3Orm:     d3orm,
+		dbAdapter: adapter,
+		execSqlFn: execSqlFn,
+		tester:    tester,
+	}
+	suite.Run(t, ts)
+}
+
 func (o *PersistsTS) TestSimpleInsert() {
 	repository, err := o.d3Orm.MakeRepository((*Shop)(nil))
 	o.NoError(err)
@@ -95,7 +107,7 @@ func (o *PersistsTS) TestSimpleInsert() {
 	}
 
 	o.NoError(repository.Persists(o.ctx, shop))
-	o.NoError(orm.Session(o.ctx).Flush())
+	o.NoError(orm.Session(o.ctx).Flush(o.ctx))
 
 	o.NotEqual(0, shop.Id.Int32)
 	o.NotEqual(0, shop.Profile.Unwrap().(*ShopProfile).Id.Int32)
@@ -109,7 +121,7 @@ func (o *PersistsTS) TestBigInsert() {
 	shop, err := createAndPersistsShop(o.ctx, o.d3Orm)
 	o.NoError(err)
 
-	o.NoError(orm.Session(o.ctx).Flush())
+	o.NoError(orm.Session(o.ctx).Flush(o.ctx))
 
 	o.NotEqual(0, shop.Id.Int32)
 	o.NotEqual(0, shop.Profile.Unwrap().(*ShopProfile).Id.Int32)
@@ -130,10 +142,10 @@ func (o *PersistsTS) TestNoNewQueriesIfDoubleFlush() {
 	_, err := createAndPersistsShop(o.ctx, o.d3Orm)
 	o.NoError(err)
 
-	o.NoError(orm.Session(o.ctx).Flush())
+	o.NoError(orm.Session(o.ctx).Flush(o.ctx))
 	insertCounter, updCounter := o.dbAdapter.InsertCounter(), o.dbAdapter.UpdateCounter()
 
-	o.NoError(orm.Session(o.ctx).Flush())
+	o.NoError(orm.Session(o.ctx).Flush(o.ctx))
 
 	o.Equal(insertCounter, o.dbAdapter.InsertCounter())
 	o.Equal(updCounter, o.dbAdapter.UpdateCounter())
@@ -149,7 +161,7 @@ func (o *PersistsTS) TestOneNewEntityIfDoublePersist() {
 	o.NoError(repository.Persists(o.ctx, shop))
 	o.NoError(repository.Persists(o.ctx, shop))
 
-	o.NoError(orm.Session(o.ctx).Flush())
+	o.NoError(orm.Session(o.ctx).Flush(o.ctx))
 
 	o.Equal(1, o.dbAdapter.InsertCounter())
 }
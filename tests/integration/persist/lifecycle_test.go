@@ -0,0 +1,172 @@
+package persist
+
+import (
+	"context"
+	"errors"
+	"github.com/godzie44/d3/orm"
+	"github.com/godzie44/d3/tests/helpers"
+	"github.com/godzie44/d3/tests/helpers/db"
+	"github.com/stretchr/testify/suite"
+	"strings"
+	"testing"
+)
+
+var (
+	errEmptyTitle  = errors.New("title must not be empty")
+	errPinnedPost  = errors.New("pinned post can't be removed")
+	postLifecycles []string
+)
+
+func (p *Post) PrePersist(_ context.Context) error {
+	postLifecycles = append(postLifecycles, "pre persist "+p.Title)
+	return p.fillSlug()
+}
+
+func (p *Post) PreUpdate(_ context.Context) error {
+	postLifecycles = append(postLifecycles, "pre update "+p.Title)
+	return p.fillSlug()
+}
+
+func (p *Post) PreRemove(_ context.Context) error {
+	postLifecycles = append(postLifecycles, "pre remove "+p.Title)
+	if p.Slug == "pinned" {
+		return errPinnedPost
+	}
+	return nil
+}
+
+func (p *Post) PostLoad(_ context.Context) {
+	postLifecycles = append(postLifecycles, "post load "+p.Title)
+}
+
+func (p *Post) PostFlush(_ context.Context) {
+	postLifecycles = append(postLifecycles, "post flush "+p.Title)
+}
+
+func (p *Post) fillSlug() error {
+	if p.Title == "" {
+		return errEmptyTitle
+	}
+	p.Slug = strings.ReplaceAll(strings.ToLower(p.Title), " ", "-")
+	return nil
+}
+
+type PersistsLifecycleTS struct {
+	suite.Suite
+	tester    helpers.DBTester
+	dbAdapter *helpers.DbAdapterWithQueryCounter
+	d3Orm     *orm.Orm
+	execSqlFn func(sql string) error
+}
+
+func (o *PersistsLifecycleTS) SetupSuite() {
+	o.NoError(o.d3Orm.Register((*Post)(nil)))
+
+	schemaSql, err := o.d3Orm.GenerateSchema()
+	o.NoError(err)
+
+	o.NoError(o.execSqlFn(schemaSql))
+}
+
+func (o *PersistsLifecycleTS) TearDownSuite() {
+	o.NoError(o.execSqlFn(`DROP TABLE post_lc;`))
+}
+
+func (o *PersistsLifecycleTS) TearDownTest() {
+	postLifecycles = nil
+	o.dbAdapter.ResetCounters()
+	o.NoError(o.execSqlFn(`delete from post_lc;`))
+}
+
+func TestPGPersistsLifecycleSuite(t *testing.T) {
+	adapter, d3orm, execSqlFn, tester := db.CreatePGTestComponents(t)
+
+	ts := &PersistsLifecycleTS{
+		dbAdapter: adapter,
+		d3Orm:     d3orm,
+		execSqlFn: execSqlFn,
+		tester:    tester,
+	}
+	suite.Run(t, ts)
+}
+
+func TestSQLitePersistsLifecycleSuite(t *testing.T) {
+	adapter, d3orm, execSqlFn, tester := db.CreateSQLiteTestComponents(t, "_lifecycle")
+
+	ts := &PersistsLifecycleTS{
+		d3Orm:     d3orm,
+		dbAdapter: adapter,
+		execSqlFn: execSqlFn,
+		tester:    tester,
+	}
+	suite.Run(t, ts)
+}
+
+func (o *PersistsLifecycleTS) persistPost(title string) {
+	ctx := o.d3Orm.CtxWithSession(context.Background())
+	repository, err := o.d3Orm.MakeRepository((*Post)(nil))
+	o.NoError(err)
+
+	o.NoError(repository.Persists(ctx, &Post{Title: title}))
+	o.NoError(orm.Session(ctx).Flush(ctx))
+}
+
+func (o *PersistsLifecycleTS) fetchPost(ctx context.Context) *Post {
+	repository, err := o.d3Orm.MakeRepository((*Post)(nil))
+	o.NoError(err)
+
+	post, err := repository.FindOne(ctx, repository.Select())
+	o.NoError(err)
+
+	return post.(*Post)
+}
+
+func (o *PersistsLifecycleTS) TestPrePersist() {
+	o.persistPost("Hello World")
+
+	o.Equal([]string{"pre persist Hello World", "post flush Hello World"}, postLifecycles)
+	o.tester.SeeOne("SELECT * FROM post_lc WHERE slug = 'hello-world'")
+
+	ctx := o.d3Orm.CtxWithSession(context.Background())
+	repository, err := o.d3Orm.MakeRepository((*Post)(nil))
+	o.NoError(err)
+
+	o.True(errors.Is(repository.Persists(ctx, &Post{}), errEmptyTitle))
+	o.NoError(orm.Session(ctx).Flush(ctx))
+
+	o.tester.SeeOne("SELECT * FROM post_lc")
+}
+
+func (o *PersistsLifecycleTS) TestPreUpdate() {
+	o.persistPost("Hello World")
+	postLifecycles = nil
+
+	ctx := o.d3Orm.CtxWithSession(context.Background())
+	post := o.fetchPost(ctx)
+
+	post.Title = "New Title"
+	o.NoError(orm.Session(ctx).Flush(ctx))
+
+	o.Equal(1, o.dbAdapter.UpdateCounter())
+	o.Equal([]string{"post load Hello World", "pre update New Title", "post flush New Title"}, postLifecycles)
+	o.tester.SeeOne("SELECT * FROM post_lc WHERE title = 'New Title' AND slug = 'new-title'")
+
+	post.Title = ""
+	o.True(errors.Is(orm.Session(ctx).Flush(ctx), errEmptyTitle))
+	o.tester.SeeOne("SELECT * FROM post_lc WHERE title = 'New Title' AND slug = 'new-title'")
+}
+
+func (o *PersistsLifecycleTS) TestPreRemove() {
+	o.persistPost("Pinned")
+	postLifecycles = nil
+
+	ctx := o.d3Orm.CtxWithSession(context.Background())
+	repository, err := o.d3Orm.MakeRepository((*Post)(nil))
+	o.NoError(err)
+
+	o.True(errors.Is(repository.Delete(ctx, o.fetchPost(ctx)), errPinnedPost))
+	o.NoError(orm.Session(ctx).Flush(ctx))
+
+	o.Equal([]string{"post load Pinned", "pre remove Pinned"}, postLifecycles)
+	o.tester.SeeOne("SELECT * FROM post_lc")
+}
@@ -0,0 +1,13 @@
+package persist
+
+import (
+	"database/sql"
+)
+
+//d3:entity
+//d3_table:post_lc
+type Post struct {
+	Id    sql.NullInt32 `d3:"pk:auto"`
+	Title string
+	Slug  string
+}
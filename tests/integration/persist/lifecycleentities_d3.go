@@ -0,0 +1,128 @@
+// Code generated by d3. DO NOT EDIT.
+
+package persist
+
+import "github.com/godzie44/d3/orm/entity"
+import "database/sql/driver"
+import "fmt"
+
+func (p *Post) D3Token() entity.MetaToken {
+	return entity.MetaToken{
+		Tpl:       (*Post)(nil),
+		TableName: "post_lc",
+		Tools: entity.InternalTools{
+			ExtractField:  p.__d3_makeFieldExtractor(),
+			SetFieldVal:   p.__d3_makeFieldSetter(),
+			CompareFields: p.__d3_makeComparator(),
+			NewInstance:   p.__d3_makeInstantiator(),
+			Copy:          p.__d3_makeCopier(),
+		},
+		Indexes: []entity.Index{},
+	}
+}
+
+func (p *Post) __d3_makeFieldExtractor() entity.FieldExtractor {
+	return func(s interface{}, name string) (interface{}, error) {
+		sTyped, ok := s.(*Post)
+		if !ok {
+			return nil, fmt.Errorf("invalid entity type")
+		}
+
+		switch name {
+
+		case "Id":
+			return sTyped.Id, nil
+
+		case "Title":
+			return sTyped.Title, nil
+
+		case "Slug":
+			return sTyped.Slug, nil
+
+		default:
+			return nil, fmt.Errorf("field %s not found", name)
+		}
+	}
+}
+
+func (p *Post) __d3_makeInstantiator() entity.Instantiator {
+	return func() interface{} {
+		return &Post{}
+	}
+}
+
+func (p *Post) __d3_makeFieldSetter() entity.FieldSetter {
+	return func(s interface{}, name string, val interface{}) error {
+		eTyped, ok := s.(*Post)
+		if !ok {
+			return fmt.Errorf("invalid entity type")
+		}
+
+		switch name {
+		case "Title":
+			eTyped.Title = val.(string)
+			return nil
+		case "Slug":
+			eTyped.Slug = val.(string)
+			return nil
+
+		case "Id":
+			if valuer, isValuer := val.(driver.Valuer); isValuer {
+				v, err := valuer.Value()
+				if err != nil {
+					return eTyped.Id.Scan(nil)
+				}
+				return eTyped.Id.Scan(v)
+			}
+			return eTyped.Id.Scan(val)
+		default:
+			return fmt.Errorf("field %s not found", name)
+		}
+	}
+}
+
+func (p *Post) __d3_makeCopier() entity.Copier {
+	return func(src interface{}) interface{} {
+		srcTyped, ok := src.(*Post)
+		if !ok {
+			return fmt.Errorf("invalid entity type")
+		}
+
+		copy := &Post{}
+
+		copy.Id = srcTyped.Id
+		copy.Title = srcTyped.Title
+		copy.Slug = srcTyped.Slug
+
+		return copy
+	}
+}
+
+func (p *Post) __d3_makeComparator() entity.FieldComparator {
+	return func(e1, e2 interface{}, fName string) bool {
+		if e1 == nil || e2 == nil {
+			return e1 == e2
+		}
+
+		e1Typed, ok := e1.(*Post)
+		if !ok {
+			return false
+		}
+		e2Typed, ok := e2.(*Post)
+		if !ok {
+			return false
+		}
+
+		switch fName {
+
+		case "Id":
+			return e1Typed.Id == e2Typed.Id
+		case "Title":
+			return e1Typed.Title == e2Typed.Title
+		case "Slug":
+			return e1Typed.Slug == e2Typed.Slug
+		default:
+			return false
+		}
+	}
+}
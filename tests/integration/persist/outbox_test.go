@@ -0,0 +1,151 @@
+package persist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/godzie44/d3/orm"
+	"github.com/godzie44/d3/orm/outbox"
+	"github.com/godzie44/d3/tests/helpers"
+	"github.com/godzie44/d3/tests/helpers/db"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type failingSink struct{}
+
+func (f *failingSink) Publish(_ context.Context, _ []*outbox.Message) error {
+	return errors.New("broker unavailable")
+}
+
+type OutboxTS struct {
+	suite.Suite
+	tester    helpers.DBTester
+	d3Orm     *orm.Orm
+	execSqlFn func(sql string) error
+}
+
+func (o *OutboxTS) SetupSuite() {
+	o.NoError(o.d3Orm.Register((*Order)(nil)))
+	o.NoError(o.d3Orm.EnableOutbox())
+
+	schemaSql, err := o.d3Orm.GenerateSchema()
+	o.NoError(err)
+
+	o.NoError(o.execSqlFn(schemaSql))
+}
+
+func (o *OutboxTS) TearDownSuite() {
+	o.NoError(o.execSqlFn(`DROP TABLE order_de;DROP TABLE d3_outbox;`))
+}
+
+func (o *OutboxTS) TearDownTest() {
+	o.NoError(o.execSqlFn(`delete from order_de;delete from d3_outbox;`))
+}
+
+func TestPGOutboxSuite(t *testing.T) {
+	_, d3orm, execSqlFn, tester := db.CreatePGTestComponents(t)
+
+	suite.Run(t, &OutboxTS{
+		d3Orm:     d3orm,
+		execSqlFn: execSqlFn,
+		tester:    tester,
+	})
+}
+
+func TestSQLiteOutboxSuite(t *testing.T) {
+	_, d3orm, execSqlFn, tester := db.CreateSQLiteTestComponents(t, "_outbox")
+
+	suite.Run(t, &OutboxTS{
+		d3Orm:     d3orm,
+		execSqlFn: execSqlFn,
+		tester:    tester,
+	})
+}
+
+func (o *OutboxTS) TestEventsStoredInOutbox() {
+	repository, err := o.d3Orm.MakeRepository((*Order)(nil))
+	o.NoError(err)
+
+	ctx := o.d3Orm.CtxWithSession(context.Background())
+	order := NewOrder("1")
+	o.NoError(repository.Persists(ctx, order))
+	o.NoError(orm.Session(ctx).Flush(ctx))
+
+	o.tester.SeeOne(fmt.Sprintf(
+		`SELECT * FROM d3_outbox WHERE type = 'persist.OrderPlaced' AND payload = '{"Number":"1"}' AND aggregate_id = '%d' AND published_at IS NULL`,
+		order.Id.Int32,
+	))
+
+	order.Ship()
+	o.NoError(orm.Session(ctx).Flush(ctx))
+
+	o.tester.See(2, "SELECT * FROM d3_outbox")
+	o.tester.SeeOne(`SELECT * FROM d3_outbox WHERE type = 'persist.OrderShipped'`)
+}
+
+func (o *OutboxTS) TestOutboxRolledBackWithTransaction() {
+	repository, err := o.d3Orm.MakeRepository((*Order)(nil))
+	o.NoError(err)
+
+	ctx := o.d3Orm.CtxWithSession(context.Background())
+	session := orm.Session(ctx)
+
+	o.NoError(session.BeginTx(ctx))
+	o.NoError(repository.Persists(ctx, NewOrder("1")))
+	o.NoError(session.Flush(ctx))
+	o.NoError(session.RollbackTx())
+
+	o.tester.See(0, "SELECT * FROM d3_outbox")
+}
+
+func (o *OutboxTS) TestRelayPublishMessages() {
+	repository, err := o.d3Orm.MakeRepository((*Order)(nil))
+	o.NoError(err)
+
+	ctx := o.d3Orm.CtxWithSession(context.Background())
+	o.NoError(repository.Persists(ctx, NewOrder("1"), NewOrder("2"), NewOrder("3")))
+	o.NoError(orm.Session(ctx).Flush(ctx))
+
+	sink := &outbox.MemorySink{}
+	relay, err := o.d3Orm.MakeOutboxRelay(sink)
+	o.NoError(err)
+
+	published, err := relay.Relay(context.Background(), 2)
+	o.NoError(err)
+	o.Equal(2, published)
+	o.Len(sink.Messages(), 2)
+
+	published, err = relay.Relay(context.Background(), 2)
+	o.NoError(err)
+	o.Equal(1, published)
+
+	published, err = relay.Relay(context.Background(), 2)
+	o.NoError(err)
+	o.Equal(0, published)
+
+	messages := sink.Messages()
+	o.Len(messages, 3)
+	for i, number := range []string{"1", "2", "3"} {
+		o.Equal("persist.OrderPlaced", messages[i].Type)
+		o.Equal(fmt.Sprintf(`{"Number":"%s"}`, number), messages[i].Payload)
+	}
+	o.tester.See(3, "SELECT * FROM d3_outbox WHERE published_at IS NOT NULL")
+}
+
+func (o *OutboxTS) TestRelayNotMarkMessagesIfPublishFail() {
+	repository, err := o.d3Orm.MakeRepository((*Order)(nil))
+	o.NoError(err)
+
+	ctx := o.d3Orm.CtxWithSession(context.Background())
+	o.NoError(repository.Persists(ctx, NewOrder("1")))
+	o.NoError(orm.Session(ctx).Flush(ctx))
+
+	relay, err := o.d3Orm.MakeOutboxRelay(&failingSink{})
+	o.NoError(err)
+
+	_, err = relay.Relay(context.Background(), 10)
+	o.Error(err)
+
+	o.tester.See(1, "SELECT * FROM d3_outbox WHERE published_at IS NULL")
+}
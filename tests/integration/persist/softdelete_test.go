@@ -0,0 +1,192 @@
+package persist
+
+import (
+	"context"
+	"errors"
+	"github.com/godzie44/d3/orm"
+	"github.com/godzie44/d3/orm/entity"
+	"github.com/godzie44/d3/orm/persistence"
+	"github.com/godzie44/d3/tests/helpers"
+	"github.com/godzie44/d3/tests/helpers/db"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type SoftDeleteTS struct {
+	suite.Suite
+	tester    helpers.DBTester
+	d3Orm     *orm.Orm
+	execSqlFn func(sql string) error
+}
+
+func (s *SoftDeleteTS) SetupSuite() {
+	s.NoError(s.d3Orm.Register((*CustomerSD)(nil), (*AddressSD)(nil), (*CardSD)(nil)))
+
+	schemaSql, err := s.d3Orm.GenerateSchema()
+	s.NoError(err)
+
+	s.NoError(s.execSqlFn(schemaSql))
+}
+
+func (s *SoftDeleteTS) TearDownSuite() {
+	s.NoError(s.execSqlFn(`
+DROP TABLE address_sd;
+DROP TABLE card_sd;
+DROP TABLE customer_sd;
+`))
+}
+
+func (s *SoftDeleteTS) TearDownTest() {
+	s.NoError(s.execSqlFn(`
+delete from address_sd;
+delete from card_sd;
+delete from customer_sd;
+`))
+}
+
+func TestPGSoftDeleteSuite(t *testing.T) {
+	_, d3orm, execSqlFn, tester := db.CreatePGTestComponents(t)
+
+	suite.Run(t, &SoftDeleteTS{
+		d3Orm:     d3orm,
+		execSqlFn: execSqlFn,
+		tester:    tester,
+	})
+}
+
+func TestSQLiteSoftDeleteSuite(t *testing.T) {
+	_, d3orm, execSqlFn, tester := db.CreateSQLiteTestComponents(t, "_soft_delete")
+
+	suite.Run(t, &SoftDeleteTS{
+		d3Orm:     d3orm,
+		execSqlFn: execSqlFn,
+		tester:    tester,
+	})
+}
+
+func (s *SoftDeleteTS) createCustomer() *CustomerSD {
+	repository, err := s.d3Orm.MakeRepository((*CustomerSD)(nil))
+	s.NoError(err)
+
+	ctx := s.d3Orm.CtxWithSession(context.Background())
+	customer := &CustomerSD{
+		Name:      "alice",
+		Addresses: entity.NewCollection(&AddressSD{City: "Paris"}, &AddressSD{City: "Berlin"}),
+		Cards:     entity.NewCollection(&CardSD{Number: "1111"}),
+	}
+	s.NoError(repository.Persists(ctx, customer))
+	s.NoError(orm.Session(ctx).Flush(ctx))
+
+	return customer
+}
+
+func (s *SoftDeleteTS) TestDeleteSetDeletionTime() {
+	customer := s.createCustomer()
+
+	repository, err := s.d3Orm.MakeRepository((*CustomerSD)(nil))
+	s.NoError(err)
+
+	ctx := s.d3Orm.CtxWithSession(context.Background())
+	fetched, err := repository.FindOne(ctx, repository.Select().Where("customer_sd.id", "=", customer.Id))
+	s.NoError(err)
+	// cascade works only for loaded relations
+	s.Equal(2, fetched.(*CustomerSD).Addresses.Count())
+	s.Equal(1, fetched.(*CustomerSD).Cards.Count())
+
+	s.NoError(repository.Delete(ctx, fetched))
+	s.NoError(orm.Session(ctx).Flush(ctx))
+
+	s.True(fetched.(*CustomerSD).DeletedAt.Valid)
+	s.tester.SeeOne("SELECT * FROM customer_sd WHERE deleted_at IS NOT NULL")
+	s.tester.See(2, "SELECT * FROM address_sd WHERE deleted_at IS NOT NULL")
+	// card has no soft delete field, so it deleted physically
+	s.tester.See(0, "SELECT * FROM card_sd")
+}
+
+func (s *SoftDeleteTS) TestSoftDeletedEntitiesFilteredOut() {
+	customer := s.createCustomer()
+
+	repository, err := s.d3Orm.MakeRepository((*CustomerSD)(nil))
+	s.NoError(err)
+
+	ctx := s.d3Orm.CtxWithSession(context.Background())
+	s.NoError(repository.Delete(ctx, customer))
+	s.NoError(orm.Session(ctx).Flush(ctx))
+
+	ctx = s.d3Orm.CtxWithSession(context.Background())
+	customers, err := repository.FindAll(ctx, repository.Select())
+	s.NoError(err)
+	s.Equal(0, customers.Count())
+
+	customers, err = repository.FindAll(ctx, repository.Select().WithDeleted())
+	s.NoError(err)
+	s.Equal(1, customers.Count())
+}
+
+func (s *SoftDeleteTS) TestSoftDeletedRelationsFilteredOut() {
+	customer := s.createCustomer()
+
+	addressRepository, err := s.d3Orm.MakeRepository((*AddressSD)(nil))
+	s.NoError(err)
+
+	ctx := s.d3Orm.CtxWithSession(context.Background())
+	s.NoError(addressRepository.Delete(ctx, customer.Addresses.Get(0)))
+	s.NoError(orm.Session(ctx).Flush(ctx))
+
+	repository, err := s.d3Orm.MakeRepository((*CustomerSD)(nil))
+	s.NoError(err)
+
+	ctx = s.d3Orm.CtxWithSession(context.Background())
+	fetched, err := repository.FindOne(ctx, repository.Select().Where("customer_sd.id", "=", customer.Id))
+	s.NoError(err)
+	s.Equal(1, fetched.(*CustomerSD).Addresses.Count())
+
+	ctx = s.d3Orm.CtxWithSession(context.Background())
+	q := repository.Select()
+	s.NoError(q.With("AddressSD"))
+	fetched, err = repository.FindOne(ctx, q.Where("customer_sd.id", "=", customer.Id))
+	s.NoError(err)
+	s.Equal(1, fetched.(*CustomerSD).Addresses.Count())
+	s.Equal("Berlin", fetched.(*CustomerSD).Addresses.Get(0).(*AddressSD).City)
+}
+
+func (s *SoftDeleteTS) TestSoftDeletedNotReturnedFromIdentityMap() {
+	customer := s.createCustomer()
+
+	repository, err := s.d3Orm.MakeRepository((*CustomerSD)(nil))
+	s.NoError(err)
+
+	ctx := s.d3Orm.CtxWithSession(context.Background())
+	s.NoError(repository.Delete(ctx, customer))
+	s.NoError(orm.Session(ctx).Flush(ctx))
+
+	ctx = s.d3Orm.CtxWithSession(context.Background())
+	fetched, err := repository.FindOne(ctx, repository.Select().Where("customer_sd.id", "=", customer.Id).WithDeleted())
+	s.NoError(err)
+	s.True(fetched.(*CustomerSD).DeletedAt.Valid)
+
+	_, err = repository.FindOne(ctx, repository.Select().Where("customer_sd.id", "=", customer.Id))
+	s.True(errors.Is(err, orm.ErrEntityNotFound))
+}
+
+func (s *SoftDeleteTS) TestSoftDeleteCheckVersion() {
+	customer := s.createCustomer()
+
+	repository, err := s.d3Orm.MakeRepository((*CustomerSD)(nil))
+	s.NoError(err)
+
+	ctx1 := s.d3Orm.CtxWithSession(context.Background())
+	fetched1, err := repository.FindOne(ctx1, repository.Select().Where("customer_sd.id", "=", customer.Id))
+	s.NoError(err)
+
+	ctx2 := s.d3Orm.CtxWithSession(context.Background())
+	fetched2, err := repository.FindOne(ctx2, repository.Select().Where("customer_sd.id", "=", customer.Id))
+	s.NoError(err)
+
+	fetched1.(*CustomerSD).Name = "bob"
+	s.NoError(orm.Session(ctx1).Flush(ctx1))
+
+	s.NoError(repository.Delete(ctx2, fetched2))
+	s.True(errors.Is(orm.Session(ctx2).Flush(ctx2), persistence.ErrOptimisticLock))
+	s.tester.See(0, "SELECT * FROM customer_sd WHERE deleted_at IS NOT NULL")
+}
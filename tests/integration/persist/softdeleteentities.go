@@ -0,0 +1,32 @@
+package persist
+
+import (
+	"database/sql"
+	"github.com/godzie44/d3/orm/entity"
+)
+
+//d3:entity
+//d3_table:customer_sd
+type CustomerSD struct {
+	Id        sql.NullInt32      `d3:"pk:auto"`
+	Name      string
+	Addresses *entity.Collection `d3:"one_to_many:<target_entity:AddressSD,join_on:customer_id,delete:cascade>,type:lazy"`
+	Cards     *entity.Collection `d3:"one_to_many:<target_entity:CardSD,join_on:customer_id,delete:cascade>,type:lazy"`
+	DeletedAt sql.NullTime       `d3:"soft_delete"`
+	Version   int32              `d3:"version"`
+}
+
+//d3:entity
+//d3_table:address_sd
+type AddressSD struct {
+	Id        sql.NullInt32 `d3:"pk:auto"`
+	City      string
+	DeletedAt sql.NullTime `d3:"soft_delete"`
+}
+
+//d3:entity
+//d3_table:card_sd
+type CardSD struct {
+	Id     sql.NullInt32 `d3:"pk:auto"`
+	Number string
+}
@@ -0,0 +1,405 @@
+// Code generated by d3. DO NOT EDIT.
+
+package persist
+
+import "fmt"
+import "github.com/godzie44/d3/orm/entity"
+import "database/sql/driver"
+
+func (c *CustomerSD) D3Token() entity.MetaToken {
+	return entity.MetaToken{
+		Tpl:       (*CustomerSD)(nil),
+		TableName: "customer_sd",
+		Tools: entity.InternalTools{
+			ExtractField:  c.__d3_makeFieldExtractor(),
+			SetFieldVal:   c.__d3_makeFieldSetter(),
+			CompareFields: c.__d3_makeComparator(),
+			NewInstance:   c.__d3_makeInstantiator(),
+			Copy:          c.__d3_makeCopier(),
+		},
+		Indexes: []entity.Index{},
+	}
+}
+
+func (c *CustomerSD) __d3_makeFieldExtractor() entity.FieldExtractor {
+	return func(s interface{}, name string) (interface{}, error) {
+		sTyped, ok := s.(*CustomerSD)
+		if !ok {
+			return nil, fmt.Errorf("invalid entity type")
+		}
+
+		switch name {
+
+		case "Id":
+			return sTyped.Id, nil
+
+		case "Name":
+			return sTyped.Name, nil
+
+		case "Addresses":
+			return sTyped.Addresses, nil
+
+		case "Cards":
+			return sTyped.Cards, nil
+
+		case "DeletedAt":
+			return sTyped.DeletedAt, nil
+
+		case "Version":
+			return sTyped.Version, nil
+
+		default:
+			return nil, fmt.Errorf("field %s not found", name)
+		}
+	}
+}
+
+func (c *CustomerSD) __d3_makeInstantiator() entity.Instantiator {
+	return func() interface{} {
+		return &CustomerSD{}
+	}
+}
+
+func (c *CustomerSD) __d3_makeFieldSetter() entity.FieldSetter {
+	return func(s interface{}, name string, val interface{}) error {
+		eTyped, ok := s.(*CustomerSD)
+		if !ok {
+			return fmt.Errorf("invalid entity type")
+		}
+
+		switch name {
+		case "Name":
+			eTyped.Name = val.(string)
+			return nil
+		case "Addresses":
+			eTyped.Addresses = val.(*entity.Collection)
+			return nil
+		case "Cards":
+			eTyped.Cards = val.(*entity.Collection)
+			return nil
+		case "Version":
+			eTyped.Version = val.(int32)
+			return nil
+
+		case "Id":
+			if valuer, isValuer := val.(driver.Valuer); isValuer {
+				v, err := valuer.Value()
+				if err != nil {
+					return eTyped.Id.Scan(nil)
+				}
+				return eTyped.Id.Scan(v)
+			}
+			return eTyped.Id.Scan(val)
+		case "DeletedAt":
+			if valuer, isValuer := val.(driver.Valuer); isValuer {
+				v, err := valuer.Value()
+				if err != nil {
+					return eTyped.DeletedAt.Scan(nil)
+				}
+				return eTyped.DeletedAt.Scan(v)
+			}
+			return eTyped.DeletedAt.Scan(val)
+		default:
+			return fmt.Errorf("field %s not found", name)
+		}
+	}
+}
+
+func (c *CustomerSD) __d3_makeCopier() entity.Copier {
+	return func(src interface{}) interface{} {
+		srcTyped, ok := src.(*CustomerSD)
+		if !ok {
+			return fmt.Errorf("invalid entity type")
+		}
+
+		copy := &CustomerSD{}
+
+		copy.Id = srcTyped.Id
+		copy.Name = srcTyped.Name
+		copy.DeletedAt = srcTyped.DeletedAt
+		copy.Version = srcTyped.Version
+
+		if srcTyped.Addresses != nil {
+			copy.Addresses = srcTyped.Addresses.DeepCopy().(*entity.Collection)
+		}
+		if srcTyped.Cards != nil {
+			copy.Cards = srcTyped.Cards.DeepCopy().(*entity.Collection)
+		}
+
+		return copy
+	}
+}
+
+func (c *CustomerSD) __d3_makeComparator() entity.FieldComparator {
+	return func(e1, e2 interface{}, fName string) bool {
+		if e1 == nil || e2 == nil {
+			return e1 == e2
+		}
+
+		e1Typed, ok := e1.(*CustomerSD)
+		if !ok {
+			return false
+		}
+		e2Typed, ok := e2.(*CustomerSD)
+		if !ok {
+			return false
+		}
+
+		switch fName {
+
+		case "Id":
+			return e1Typed.Id == e2Typed.Id
+		case "Name":
+			return e1Typed.Name == e2Typed.Name
+		case "Addresses":
+			return e1Typed.Addresses == e2Typed.Addresses
+		case "Cards":
+			return e1Typed.Cards == e2Typed.Cards
+		case "DeletedAt":
+			return e1Typed.DeletedAt == e2Typed.DeletedAt
+		case "Version":
+			return e1Typed.Version == e2Typed.Version
+		default:
+			return false
+		}
+	}
+}
+
+func (a *AddressSD) D3Token() entity.MetaToken {
+	return entity.MetaToken{
+		Tpl:       (*AddressSD)(nil),
+		TableName: "address_sd",
+		Tools: entity.InternalTools{
+			ExtractField:  a.__d3_makeFieldExtractor(),
+			SetFieldVal:   a.__d3_makeFieldSetter(),
+			CompareFields: a.__d3_makeComparator(),
+			NewInstance:   a.__d3_makeInstantiator(),
+			Copy:          a.__d3_makeCopier(),
+		},
+		Indexes: []entity.Index{},
+	}
+}
+
+func (a *AddressSD) __d3_makeFieldExtractor() entity.FieldExtractor {
+	return func(s interface{}, name string) (interface{}, error) {
+		sTyped, ok := s.(*AddressSD)
+		if !ok {
+			return nil, fmt.Errorf("invalid entity type")
+		}
+
+		switch name {
+
+		case "Id":
+			return sTyped.Id, nil
+
+		case "City":
+			return sTyped.City, nil
+
+		case "DeletedAt":
+			return sTyped.DeletedAt, nil
+
+		default:
+			return nil, fmt.Errorf("field %s not found", name)
+		}
+	}
+}
+
+func (a *AddressSD) __d3_makeInstantiator() entity.Instantiator {
+	return func() interface{} {
+		return &AddressSD{}
+	}
+}
+
+func (a *AddressSD) __d3_makeFieldSetter() entity.FieldSetter {
+	return func(s interface{}, name string, val interface{}) error {
+		eTyped, ok := s.(*AddressSD)
+		if !ok {
+			return fmt.Errorf("invalid entity type")
+		}
+
+		switch name {
+		case "City":
+			eTyped.City = val.(string)
+			return nil
+
+		case "Id":
+			if valuer, isValuer := val.(driver.Valuer); isValuer {
+				v, err := valuer.Value()
+				if err != nil {
+					return eTyped.Id.Scan(nil)
+				}
+				return eTyped.Id.Scan(v)
+			}
+			return eTyped.Id.Scan(val)
+		case "DeletedAt":
+			if valuer, isValuer := val.(driver.Valuer); isValuer {
+				v, err := valuer.Value()
+				if err != nil {
+					return eTyped.DeletedAt.Scan(nil)
+				}
+				return eTyped.DeletedAt.Scan(v)
+			}
+			return eTyped.DeletedAt.Scan(val)
+		default:
+			return fmt.Errorf("field %s not found", name)
+		}
+	}
+}
+
+func (a *AddressSD) __d3_makeCopier() entity.Copier {
+	return func(src interface{}) interface{} {
+		srcTyped, ok := src.(*AddressSD)
+		if !ok {
+			return fmt.Errorf("invalid entity type")
+		}
+
+		copy := &AddressSD{}
+
+		copy.Id = srcTyped.Id
+		copy.City = srcTyped.City
+		copy.DeletedAt = srcTyped.DeletedAt
+
+		return copy
+	}
+}
+
+func (a *AddressSD) __d3_makeComparator() entity.FieldComparator {
+	return func(e1, e2 interface{}, fName string) bool {
+		if e1 == nil || e2 == nil {
+			return e1 == e2
+		}
+
+		e1Typed, ok := e1.(*AddressSD)
+		if !ok {
+			return false
+		}
+		e2Typed, ok := e2.(*AddressSD)
+		if !ok {
+			return false
+		}
+
+		switch fName {
+
+		case "Id":
+			return e1Typed.Id == e2Typed.Id
+		case "City":
+			return e1Typed.City == e2Typed.City
+		case "DeletedAt":
+			return e1Typed.DeletedAt == e2Typed.DeletedAt
+		default:
+			return false
+		}
+	}
+}
+
+func (c *CardSD) D3Token() entity.MetaToken {
+	return entity.MetaToken{
+		Tpl:       (*CardSD)(nil),
+		TableName: "card_sd",
+		Tools: entity.InternalTools{
+			ExtractField:  c.__d3_makeFieldExtractor(),
+			SetFieldVal:   c.__d3_makeFieldSetter(),
+			CompareFields: c.__d3_makeComparator(),
+			NewInstance:   c.__d3_makeInstantiator(),
+			Copy:          c.__d3_makeCopier(),
+		},
+		Indexes: []entity.Index{},
+	}
+}
+
+func (c *CardSD) __d3_makeFieldExtractor() entity.FieldExtractor {
+	return func(s interface{}, name string) (interface{}, error) {
+		sTyped, ok := s.(*CardSD)
+		if !ok {
+			return nil, fmt.Errorf("invalid entity type")
+		}
+
+		switch name {
+
+		case "Id":
+			return sTyped.Id, nil
+
+		case "Number":
+			return sTyped.Number, nil
+
+		default:
+			return nil, fmt.Errorf("field %s not found", name)
+		}
+	}
+}
+
+func (c *CardSD) __d3_makeInstantiator() entity.Instantiator {
+	return func() interface{} {
+		return &CardSD{}
+	}
+}
+
+func (c *CardSD) __d3_makeFieldSetter() entity.FieldSetter {
+	return func(s interface{}, name string, val interface{}) error {
+		eTyped, ok := s.(*CardSD)
+		if !ok {
+			return fmt.Errorf("invalid entity type")
+		}
+
+		switch name {
+		case "Number":
+			eTyped.Number = val.(string)
+			return nil
+
+		case "Id":
+			if valuer, isValuer := val.(driver.Valuer); isValuer {
+				v, err := valuer.Value()
+				if err != nil {
+					return eTyped.Id.Scan(nil)
+				}
+				return eTyped.Id.Scan(v)
+			}
+			return eTyped.Id.Scan(val)
+		default:
+			return fmt.Errorf("field %s not found", name)
+		}
+	}
+}
+
+func (c *CardSD) __d3_makeCopier() entity.Copier {
+	return func(src interface{}) interface{} {
+		srcTyped, ok := src.(*CardSD)
+		if !ok {
+			return fmt.Errorf("invalid entity type")
+		}
+
+		copy := &CardSD{}
+
+		copy.Id = srcTyped.Id
+		copy.Number = srcTyped.Number
+
+		return copy
+	}
+}
+
+func (c *CardSD) __d3_makeComparator() entity.FieldComparator {
+	return func(e1, e2 interface{}, fName string) bool {
+		if e1 == nil || e2 == nil {
+			return e1 == e2
+		}
+
+		e1Typed, ok := e1.(*CardSD)
+		if !ok {
+			return false
+		}
+		e2Typed, ok := e2.(*CardSD)
+		if !ok {
+			return false
+		}
+
+		switch fName {
+
+		case "Id":
+			return e1Typed.Id == e2Typed.Id
+		case "Number":
+			return e1Typed.Number == e2Typed.Number
+		default:
+			return false
+		}
+	}
+}
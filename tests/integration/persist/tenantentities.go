@@ -0,0 +1,23 @@
+package persist
+
+import (
+	"database/sql"
+	"github.com/godzie44/d3/orm/entity"
+)
+
+//d3:entity
+//d3_table:project_mt
+type ProjectMT struct {
+	Id       sql.NullInt32      `d3:"pk:auto"`
+	TenantId string
+	Name     string
+	Tasks    *entity.Collection `d3:"one_to_many:<target_entity:TaskMT,join_on:project_id>,type:lazy"`
+}
+
+//d3:entity
+//d3_table:task_mt
+type TaskMT struct {
+	Id       sql.NullInt32 `d3:"pk:auto"`
+	TenantId string
+	Title    string
+}
@@ -0,0 +1,261 @@
+// Code generated by d3. DO NOT EDIT.
+
+package persist
+
+import "fmt"
+import "github.com/godzie44/d3/orm/entity"
+import "database/sql/driver"
+
+func (p *ProjectMT) D3Token() entity.MetaToken {
+	return entity.MetaToken{
+		Tpl:       (*ProjectMT)(nil),
+		TableName: "project_mt",
+		Tools: entity.InternalTools{
+			ExtractField:  p.__d3_makeFieldExtractor(),
+			SetFieldVal:   p.__d3_makeFieldSetter(),
+			CompareFields: p.__d3_makeComparator(),
+			NewInstance:   p.__d3_makeInstantiator(),
+			Copy:          p.__d3_makeCopier(),
+		},
+		Indexes: []entity.Index{},
+	}
+}
+
+func (p *ProjectMT) __d3_makeFieldExtractor() entity.FieldExtractor {
+	return func(s interface{}, name string) (interface{}, error) {
+		sTyped, ok := s.(*ProjectMT)
+		if !ok {
+			return nil, fmt.Errorf("invalid entity type")
+		}
+
+		switch name {
+
+		case "Id":
+			return sTyped.Id, nil
+
+		case "TenantId":
+			return sTyped.TenantId, nil
+
+		case "Name":
+			return sTyped.Name, nil
+
+		case "Tasks":
+			return sTyped.Tasks, nil
+
+		default:
+			return nil, fmt.Errorf("field %s not found", name)
+		}
+	}
+}
+
+func (p *ProjectMT) __d3_makeInstantiator() entity.Instantiator {
+	return func() interface{} {
+		return &ProjectMT{}
+	}
+}
+
+func (p *ProjectMT) __d3_makeFieldSetter() entity.FieldSetter {
+	return func(s interface{}, name string, val interface{}) error {
+		eTyped, ok := s.(*ProjectMT)
+		if !ok {
+			return fmt.Errorf("invalid entity type")
+		}
+
+		switch name {
+		case "TenantId":
+			eTyped.TenantId = val.(string)
+			return nil
+		case "Name":
+			eTyped.Name = val.(string)
+			return nil
+		case "Tasks":
+			eTyped.Tasks = val.(*entity.Collection)
+			return nil
+
+		case "Id":
+			if valuer, isValuer := val.(driver.Valuer); isValuer {
+				v, err := valuer.Value()
+				if err != nil {
+					return eTyped.Id.Scan(nil)
+				}
+				return eTyped.Id.Scan(v)
+			}
+			return eTyped.Id.Scan(val)
+		default:
+			return fmt.Errorf("field %s not found", name)
+		}
+	}
+}
+
+func (p *ProjectMT) __d3_makeCopier() entity.Copier {
+	return func(src interface{}) interface{} {
+		srcTyped, ok := src.(*ProjectMT)
+		if !ok {
+			return fmt.Errorf("invalid entity type")
+		}
+
+		copy := &ProjectMT{}
+
+		copy.Id = srcTyped.Id
+		copy.TenantId = srcTyped.TenantId
+		copy.Name = srcTyped.Name
+
+		if srcTyped.Tasks != nil {
+			copy.Tasks = srcTyped.Tasks.DeepCopy().(*entity.Collection)
+		}
+
+		return copy
+	}
+}
+
+func (p *ProjectMT) __d3_makeComparator() entity.FieldComparator {
+	return func(e1, e2 interface{}, fName string) bool {
+		if e1 == nil || e2 == nil {
+			return e1 == e2
+		}
+
+		e1Typed, ok := e1.(*ProjectMT)
+		if !ok {
+			return false
+		}
+		e2Typed, ok := e2.(*ProjectMT)
+		if !ok {
+			return false
+		}
+
+		switch fName {
+
+		case "Id":
+			return e1Typed.Id == e2Typed.Id
+		case "TenantId":
+			return e1Typed.TenantId == e2Typed.TenantId
+		case "Name":
+			return e1Typed.Name == e2Typed.Name
+		case "Tasks":
+			return e1Typed.Tasks == e2Typed.Tasks
+		default:
+			return false
+		}
+	}
+}
+
+func (t *TaskMT) D3Token() entity.MetaToken {
+	return entity.MetaToken{
+		Tpl:       (*TaskMT)(nil),
+		TableName: "task_mt",
+		Tools: entity.InternalTools{
+			ExtractField:  t.__d3_makeFieldExtractor(),
+			SetFieldVal:   t.__d3_makeFieldSetter(),
+			CompareFields: t.__d3_makeComparator(),
+			NewInstance:   t.__d3_makeInstantiator(),
+			Copy:          t.__d3_makeCopier(),
+		},
+		Indexes: []entity.Index{},
+	}
+}
+
+func (t *TaskMT) __d3_makeFieldExtractor() entity.FieldExtractor {
+	return func(s interface{}, name string) (interface{}, error) {
+		sTyped, ok := s.(*TaskMT)
+		if !ok {
+			return nil, fmt.Errorf("invalid entity type")
+		}
+
+		switch name {
+
+		case "Id":
+			return sTyped.Id, nil
+
+		case "TenantId":
+			return sTyped.TenantId, nil
+
+		case "Title":
+			return sTyped.Title, nil
+
+		default:
+			return nil, fmt.Errorf("field %s not found", name)
+		}
+	}
+}
+
+func (t *TaskMT) __d3_makeInstantiator() entity.Instantiator {
+	return func() interface{} {
+		return &TaskMT{}
+	}
+}
+
+func (t *TaskMT) __d3_makeFieldSetter() entity.FieldSetter {
+	return func(s interface{}, name string, val interface{}) error {
+		eTyped, ok := s.(*TaskMT)
+		if !ok {
+			return fmt.Errorf("invalid entity type")
+		}
+
+		switch name {
+		case "TenantId":
+			eTyped.TenantId = val.(string)
+			return nil
+		case "Title":
+			eTyped.Title = val.(string)
+			return nil
+
+		case "Id":
+			if valuer, isValuer := val.(driver.Valuer); isValuer {
+				v, err := valuer.Value()
+				if err != nil {
+					return eTyped.Id.Scan(nil)
+				}
+				return eTyped.Id.Scan(v)
+			}
+			return eTyped.Id.Scan(val)
+		default:
+			return fmt.Errorf("field %s not found", name)
+		}
+	}
+}
+
+func (t *TaskMT) __d3_makeCopier() entity.Copier {
+	return func(src interface{}) interface{} {
+		srcTyped, ok := src.(*TaskMT)
+		if !ok {
+			return fmt.Errorf("invalid entity type")
+		}
+
+		copy := &TaskMT{}
+
+		copy.Id = srcTyped.Id
+		copy.TenantId = srcTyped.TenantId
+		copy.Title = srcTyped.Title
+
+		return copy
+	}
+}
+
+func (t *TaskMT) __d3_makeComparator() entity.FieldComparator {
+	return func(e1, e2 interface{}, fName string) bool {
+		if e1 == nil || e2 == nil {
+			return e1 == e2
+		}
+
+		e1Typed, ok := e1.(*TaskMT)
+		if !ok {
+			return false
+		}
+		e2Typed, ok := e2.(*TaskMT)
+		if !ok {
+			return false
+		}
+
+		switch fName {
+
+		case "Id":
+			return e1Typed.Id == e2Typed.Id
+		case "TenantId":
+			return e1Typed.TenantId == e2Typed.TenantId
+		case "Title":
+			return e1Typed.Title == e2Typed.Title
+		default:
+			return false
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package persist
+
+import (
+	"context"
+	"database/sql"
+	"github.com/godzie44/d3/orm"
+	"github.com/godzie44/d3/tests/helpers"
+	"github.com/godzie44/d3/tests/helpers/db"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type TimestampTS struct {
+	suite.Suite
+	tester    helpers.DBTester
+	d3Orm     *orm.Orm
+	execSqlFn func(sql string) error
+	now       time.Time
+}
+
+func (ts *TimestampTS) SetupSuite() {
+	ts.NoError(ts.d3Orm.Register((*Note)(nil)))
+	ts.d3Orm.SetClock(func() time.Time {
+		return ts.now
+	})
+
+	schemaSql, err := ts.d3Orm.GenerateSchema()
+	ts.NoError(err)
+
+	ts.NoError(ts.execSqlFn(schemaSql))
+}
+
+func (ts *TimestampTS) TearDownSuite() {
+	ts.NoError(ts.execSqlFn(`DROP TABLE note_ts;`))
+}
+
+func (ts *TimestampTS) TearDownTest() {
+	ts.NoError(ts.execSqlFn(`delete from note_ts;`))
+}
+
+func TestPGTimestampSuite(t *testing.T) {
+	_, d3orm, execSqlFn, tester := db.CreatePGTestComponents(t)
+
+	suite.Run(t, &TimestampTS{
+		d3Orm:     d3orm,
+		execSqlFn: execSqlFn,
+		tester:    tester,
+	})
+}
+
+func TestSQLiteTimestampSuite(t *testing.T) {
+	_, d3orm, execSqlFn, tester := db.CreateSQLiteTestComponents(t, "_timestamp")
+
+	suite.Run(t, &TimestampTS{
+		d3Orm:     d3orm,
+		execSqlFn: execSqlFn,
+		tester:    tester,
+	})
+}
+
+func (ts *TimestampTS) fetchNote(id sql.NullInt32) *Note {
+	repository, err := ts.d3Orm.MakeRepository((*Note)(nil))
+	ts.NoError(err)
+
+	ctx := ts.d3Orm.CtxWithSession(context.Background())
+	note, err := repository.FindOne(ctx, repository.Select().Where("note_ts.id", "=", id))
+	ts.NoError(err)
+
+	return note.(*Note)
+}
+
+func (ts *TimestampTS) TestTimestampsSetOnInsertAndUpdate() {
+	created := time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+	updated := created.Add(time.Hour)
+
+	repository, err := ts.d3Orm.MakeRepository((*Note)(nil))
+	ts.NoError(err)
+
+	ts.now = created
+	ctx := ts.d3Orm.CtxWithSession(context.Background())
+	note := &Note{Text: "draft"}
+	ts.NoError(repository.Persists(ctx, note))
+	ts.NoError(orm.Session(ctx).Flush(ctx))
+
+	ts.Equal(created, note.CreatedAt)
+	ts.Equal(created, note.UpdatedAt)
+	fetched := ts.fetchNote(note.Id)
+	ts.True(created.Equal(fetched.CreatedAt))
+	ts.True(created.Equal(fetched.UpdatedAt))
+
+	ts.now = updated
+	note.Text = "final"
+	ts.NoError(orm.Session(ctx).Flush(ctx))
+
+	ts.Equal(updated, note.UpdatedAt)
+	fetched = ts.fetchNote(note.Id)
+	ts.True(created.Equal(fetched.CreatedAt))
+	ts.True(updated.Equal(fetched.UpdatedAt))
+
+	ts.now = updated.Add(time.Hour)
+	ts.NoError(orm.Session(ctx).Flush(ctx))
+
+	ts.Equal(updated, note.UpdatedAt)
+	ts.True(updated.Equal(ts.fetchNote(note.Id).UpdatedAt))
+}
+
+func (ts *TimestampTS) TestExplicitCreationTimeNotOverwritten() {
+	created := time.Date(2019, 5, 1, 0, 0, 0, 0, time.UTC)
+	ts.now = time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	repository, err := ts.d3Orm.MakeRepository((*Note)(nil))
+	ts.NoError(err)
+
+	ctx := ts.d3Orm.CtxWithSession(context.Background())
+	note := &Note{Text: "imported", CreatedAt: created}
+	ts.NoError(repository.Persists(ctx, note))
+	ts.NoError(orm.Session(ctx).Flush(ctx))
+
+	fetched := ts.fetchNote(note.Id)
+	ts.True(created.Equal(fetched.CreatedAt))
+	ts.True(ts.now.Equal(fetched.UpdatedAt))
+}
+
+func (ts *TimestampTS) TestTimestampsNotChangedByRejectedOrRolledBackFlush() {
+	created := time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	repository, err := ts.d3Orm.MakeRepository((*Note)(nil))
+	ts.NoError(err)
+
+	ts.now = created
+	ctx := ts.d3Orm.CtxWithSession(context.Background())
+	note := &Note{Text: "draft"}
+	ts.NoError(repository.Persists(ctx, note))
+	ts.NoError(orm.Session(ctx).Flush(ctx))
+
+	ts.now = created.Add(time.Hour)
+	note.Text = "final"
+
+	session := orm.Session(ctx)
+	ts.NoError(session.BeginTx(ctx, orm.TxOptions{ReadOnly: true}))
+	ts.Equal(orm.ErrReadOnlyTx, session.Flush(ctx))
+	ts.NoError(session.RollbackTx())
+	ts.Equal(created, note.UpdatedAt)
+
+	ts.NoError(session.BeginTx(ctx))
+	ts.NoError(session.Flush(ctx))
+	ts.Equal(ts.now, note.UpdatedAt)
+	ts.NoError(session.RollbackTx())
+	ts.Equal(created, note.UpdatedAt)
+
+	newNote := &Note{Text: "new"}
+	ts.NoError(session.BeginTx(ctx))
+	ts.NoError(repository.Persists(ctx, newNote))
+	ts.NoError(session.Flush(ctx))
+	ts.Equal(ts.now, newNote.CreatedAt)
+	ts.NoError(session.RollbackTx())
+	ts.True(newNote.CreatedAt.IsZero())
+	ts.True(newNote.UpdatedAt.IsZero())
+}
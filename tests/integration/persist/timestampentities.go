@@ -0,0 +1,15 @@
+package persist
+
+import (
+	"database/sql"
+	"time"
+)
+
+//d3:entity
+//d3_table:note_ts
+type Note struct {
+	Id        sql.NullInt32 `d3:"pk:auto"`
+	Text      string
+	CreatedAt time.Time `d3:"created_at"`
+	UpdatedAt time.Time `d3:"updated_at"`
+}
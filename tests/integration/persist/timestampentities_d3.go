@@ -0,0 +1,138 @@
+// Code generated by d3. DO NOT EDIT.
+
+package persist
+
+import "github.com/godzie44/d3/orm/entity"
+import "time"
+import "database/sql/driver"
+import "fmt"
+
+func (n *Note) D3Token() entity.MetaToken {
+	return entity.MetaToken{
+		Tpl:       (*Note)(nil),
+		TableName: "note_ts",
+		Tools: entity.InternalTools{
+			ExtractField:  n.__d3_makeFieldExtractor(),
+			SetFieldVal:   n.__d3_makeFieldSetter(),
+			CompareFields: n.__d3_makeComparator(),
+			NewInstance:   n.__d3_makeInstantiator(),
+			Copy:          n.__d3_makeCopier(),
+		},
+		Indexes: []entity.Index{},
+	}
+}
+
+func (n *Note) __d3_makeFieldExtractor() entity.FieldExtractor {
+	return func(s interface{}, name string) (interface{}, error) {
+		sTyped, ok := s.(*Note)
+		if !ok {
+			return nil, fmt.Errorf("invalid entity type")
+		}
+
+		switch name {
+
+		case "Id":
+			return sTyped.Id, nil
+
+		case "Text":
+			return sTyped.Text, nil
+
+		case "CreatedAt":
+			return sTyped.CreatedAt, nil
+
+		case "UpdatedAt":
+			return sTyped.UpdatedAt, nil
+
+		default:
+			return nil, fmt.Errorf("field %s not found", name)
+		}
+	}
+}
+
+func (n *Note) __d3_makeInstantiator() entity.Instantiator {
+	return func() interface{} {
+		return &Note{}
+	}
+}
+
+func (n *Note) __d3_makeFieldSetter() entity.FieldSetter {
+	return func(s interface{}, name string, val interface{}) error {
+		eTyped, ok := s.(*Note)
+		if !ok {
+			return fmt.Errorf("invalid entity type")
+		}
+
+		switch name {
+		case "Text":
+			eTyped.Text = val.(string)
+			return nil
+		case "CreatedAt":
+			eTyped.CreatedAt = val.(time.Time)
+			return nil
+		case "UpdatedAt":
+			eTyped.UpdatedAt = val.(time.Time)
+			return nil
+
+		case "Id":
+			if valuer, isValuer := val.(driver.Valuer); isValuer {
+				v, err := valuer.Value()
+				if err != nil {
+					return eTyped.Id.Scan(nil)
+				}
+				return eTyped.Id.Scan(v)
+			}
+			return eTyped.Id.Scan(val)
+		default:
+			return fmt.Errorf("field %s not found", name)
+		}
+	}
+}
+
+func (n *Note) __d3_makeCopier() entity.Copier {
+	return func(src interface{}) interface{} {
+		srcTyped, ok := src.(*Note)
+		if !ok {
+			return fmt.Errorf("invalid entity type")
+		}
+
+		copy := &Note{}
+
+		copy.Id = srcTyped.Id
+		copy.Text = srcTyped.Text
+		copy.CreatedAt = srcTyped.CreatedAt
+		copy.UpdatedAt = srcTyped.UpdatedAt
+
+		return copy
+	}
+}
+
+func (n *Note) __d3_makeComparator() entity.FieldComparator {
+	return func(e1, e2 interface{}, fName string) bool {
+		if e1 == nil || e2 == nil {
+			return e1 == e2
+		}
+
+		e1Typed, ok := e1.(*Note)
+		if !ok {
+			return false
+		}
+		e2Typed, ok := e2.(*Note)
+		if !ok {
+			return false
+		}
+
+		switch fName {
+
+		case "Id":
+			return e1Typed.Id == e2Typed.Id
+		case "Text":
+			return e1Typed.Text == e2Typed.Text
+		case "CreatedAt":
+			return e1Typed.CreatedAt == e2Typed.CreatedAt
+		case "UpdatedAt":
+			return e1Typed.UpdatedAt == e2Typed.UpdatedAt
+		default:
+			return false
+		}
+	}
+}
@@ -3,6 +3,7 @@ package persist
 import (
 	"context"
 	"database/sql"
+	"errors"
 	d3pgx "github.com/godzie44/d3/adapter/pgx"
 	"github.com/godzie44/d3/orm"
 	"github.com/godzie44/d3/tests/helpers"
@@ -40,9 +41,9 @@ func (t *TransactionalTs) SetupSuite() {
 
 func (t *TransactionalTs) TearDownSuite() {
 	t.NoError(t.execSqlFn(`
+DROP TABLE book_author_p;
 DROP TABLE book_p;
 DROP TABLE author_p;
-DROP TABLE book_author_p;
 DROP TABLE shop_p;
 DROP TABLE profile_p;
 `))
@@ -104,7 +105,7 @@ func (t *TransactionalTs) TestAutoCommit() {
 	}
 
 	t.NoError(repository.Persists(ctx, shop1, shop2))
-	t.NoError(session.Flush())
+	t.NoError(session.Flush(ctx))
 
 	t.tester.
 		SeeTwo("SELECT * FROM shop_p WHERE name = $1 or name = $2", "shop1", "shop2")
@@ -116,7 +117,7 @@ func (t *TransactionalTs) TestManualCommit() {
 
 	repository, _ := t.d3Orm.MakeRepository((*Shop)(nil))
 
-	t.NoError(session.BeginTx())
+	t.NoError(session.BeginTx(ctx))
 
 	shop1 := &Shop{
 		Name: "shop1",
@@ -126,7 +127,7 @@ func (t *TransactionalTs) TestManualCommit() {
 	}
 
 	t.NoError(repository.Persists(ctx, shop1, shop2))
-	t.NoError(session.Flush())
+	t.NoError(session.Flush(ctx))
 
 	t.independentTester.See(0, "SELECT * FROM shop_p WHERE name = $1 or name = $2", "shop1", "shop2")
 
@@ -141,20 +142,20 @@ func (t *TransactionalTs) TestManualRollback() {
 
 	repository, _ := t.d3Orm.MakeRepository((*Shop)(nil))
 
-	t.NoError(session.BeginTx())
+	t.NoError(session.BeginTx(ctx))
 
 	shop1 := &Shop{
 		Name: "shop1",
 	}
 	t.NoError(repository.Persists(ctx, shop1))
-	t.NoError(session.Flush())
+	t.NoError(session.Flush(ctx))
 
 	shop2 := &Shop{
 		Name: "shop2",
 	}
 
 	t.NoError(repository.Persists(ctx, shop2))
-	t.NoError(session.Flush())
+	t.NoError(session.Flush(ctx))
 
 	t.independentTester.See(0, "SELECT * FROM shop_p WHERE name = $1 or name = $2", "shop1", "shop2")
 
@@ -163,6 +164,133 @@ func (t *TransactionalTs) TestManualRollback() {
 	t.independentTester.See(0, "SELECT * FROM shop_p WHERE name = $1 or name = $2", "shop1", "shop2")
 }
 
+func (t *TransactionalTs) TestNestedCommit() {
+	ctx := t.d3Orm.CtxWithSession(context.Background())
+	session := orm.Session(ctx)
+
+	repository, _ := t.d3Orm.MakeRepository((*Shop)(nil))
+
+	t.NoError(session.BeginTx(ctx))
+	t.NoError(repository.Persists(ctx, &Shop{Name: "shop1"}))
+	t.NoError(session.Flush(ctx))
+
+	t.NoError(session.BeginTx(ctx))
+	t.NoError(repository.Persists(ctx, &Shop{Name: "shop2"}))
+	t.NoError(session.Flush(ctx))
+	t.NoError(session.CommitTx())
+
+	t.independentTester.See(0, "SELECT * FROM shop_p WHERE name = $1 or name = $2", "shop1", "shop2")
+
+	t.NoError(session.CommitTx())
+
+	t.independentTester.SeeTwo("SELECT * FROM shop_p WHERE name = $1 or name = $2", "shop1", "shop2")
+}
+
+func (t *TransactionalTs) TestNestedRollback() {
+	ctx := t.d3Orm.CtxWithSession(context.Background())
+	session := orm.Session(ctx)
+
+	repository, _ := t.d3Orm.MakeRepository((*Shop)(nil))
+
+	t.NoError(session.BeginTx(ctx))
+	t.NoError(repository.Persists(ctx, &Shop{Name: "shop1"}))
+	t.NoError(session.Flush(ctx))
+
+	t.NoError(session.BeginTx(ctx))
+	t.NoError(repository.Persists(ctx, &Shop{Name: "shop2"}))
+	t.NoError(session.Flush(ctx))
+	t.NoError(session.RollbackTx())
+
+	t.NoError(session.CommitTx())
+
+	t.independentTester.SeeOne("SELECT * FROM shop_p WHERE name = $1", "shop1")
+	t.independentTester.See(0, "SELECT * FROM shop_p WHERE name = $1", "shop2")
+}
+
+func (t *TransactionalTs) TestNestedRollbackRestoreEntityState() {
+	ctx := t.d3Orm.CtxWithSession(context.Background())
+	session := orm.Session(ctx)
+
+	repository, _ := t.d3Orm.MakeRepository((*Shop)(nil))
+
+	t.NoError(session.BeginTx(ctx))
+	shop := &Shop{Name: "shop1"}
+	t.NoError(repository.Persists(ctx, shop))
+	t.NoError(session.Flush(ctx))
+
+	t.NoError(session.BeginTx(ctx))
+	shop.Name = "changed"
+	t.NoError(session.Flush(ctx))
+	t.NoError(session.RollbackTx())
+
+	// state of shop restored to state at savepoint, so change flushed again
+	t.NoError(session.Flush(ctx))
+	t.NoError(session.CommitTx())
+
+	t.independentTester.SeeOne("SELECT * FROM shop_p WHERE name = $1", "changed")
+}
+
+func (t *TransactionalTs) TestTransactionalCommit() {
+	ctx := t.d3Orm.CtxWithSession(context.Background())
+	session := orm.Session(ctx)
+
+	repository, _ := t.d3Orm.MakeRepository((*Shop)(nil))
+
+	t.NoError(session.Transactional(ctx, func(ctx context.Context) error {
+		return repository.Persists(ctx, &Shop{Name: "shop1"}, &Shop{Name: "shop2"})
+	}))
+
+	t.independentTester.SeeTwo("SELECT * FROM shop_p WHERE name = $1 or name = $2", "shop1", "shop2")
+}
+
+func (t *TransactionalTs) TestTransactionalRollbackOnError() {
+	ctx := t.d3Orm.CtxWithSession(context.Background())
+	session := orm.Session(ctx)
+
+	repository, _ := t.d3Orm.MakeRepository((*Shop)(nil))
+	fnErr := errors.New("fn error")
+
+	err := session.Transactional(ctx, func(ctx context.Context) error {
+		if err := repository.Persists(ctx, &Shop{Name: "shop1"}); err != nil {
+			return err
+		}
+		if err := session.Flush(ctx); err != nil {
+			return err
+		}
+		return fnErr
+	})
+	t.True(errors.Is(err, fnErr))
+
+	t.independentTester.See(0, "SELECT * FROM shop_p WHERE name = $1", "shop1")
+}
+
+func (t *TransactionalTs) TestTransactionalRollbackOnPanic() {
+	ctx := t.d3Orm.CtxWithSession(context.Background())
+	session := orm.Session(ctx)
+
+	repository, _ := t.d3Orm.MakeRepository((*Shop)(nil))
+
+	t.Panics(func() {
+		_ = session.Transactional(ctx, func(ctx context.Context) error {
+			if err := repository.Persists(ctx, &Shop{Name: "shop1"}); err != nil {
+				return err
+			}
+			if err := session.Flush(ctx); err != nil {
+				return err
+			}
+			panic("fn panic")
+		})
+	})
+
+	t.independentTester.See(0, "SELECT * FROM shop_p WHERE name = $1", "shop1")
+
+	// session usable after panic
+	t.NoError(session.Transactional(ctx, func(ctx context.Context) error {
+		return repository.Persists(ctx, &Shop{Name: "shop2"})
+	}))
+	t.independentTester.SeeOne("SELECT * FROM shop_p WHERE name = $1", "shop2")
+}
+
 type MultipleTransactionTs struct {
 	suite.Suite
 	dbAdapter *helpers.DbAdapterWithQueryCounter
@@ -188,9 +316,9 @@ func (m *MultipleTransactionTs) SetupSuite() {
 
 func (m *MultipleTransactionTs) TearDownSuite() {
 	m.NoError(m.execSqlFn(`
+DROP TABLE book_author_p;
 DROP TABLE book_p;
 DROP TABLE author_p;
-DROP TABLE book_author_p;
 DROP TABLE shop_p;
 DROP TABLE profile_p;
 `))
@@ -208,11 +336,11 @@ func (m *MultipleTransactionTs) TestConcurrentTransactionQuerying() {
 
 		ctx := m.d3Orm.CtxWithSession(context.Background())
 		session := orm.Session(ctx)
-		m.NoError(session.BeginTx())
+		m.NoError(session.BeginTx(ctx))
 
 		shop, _ := repository.FindOne(ctx, repository.Select().Where("id", "=", 1))
 		shop.(*Shop).Name = "changed name"
-		m.NoError(session.Flush())
+		m.NoError(session.Flush(ctx))
 
 		syncChan <- struct{}{}
 		<-syncChan
@@ -227,7 +355,7 @@ func (m *MultipleTransactionTs) TestConcurrentTransactionQuerying() {
 		defer wg.Done()
 		ctx := m.d3Orm.CtxWithSession(context.Background())
 		session := orm.Session(ctx)
-		m.NoError(session.BeginTx())
+		m.NoError(session.BeginTx(ctx))
 
 		<-syncChan
 
@@ -270,3 +398,30 @@ func TestSQLiteMultipleTransactionalTs(t *testing.T) {
 	}
 	suite.Run(t, ts)
 }
+
+func (t *TransactionalTs) TestTransactionalReadOnly() {
+	ctx := t.d3Orm.CtxWithSession(context.Background())
+	repository, _ := t.d3Orm.MakeRepository((*Shop)(nil))
+
+	t.NoError(repository.Persists(ctx, &Shop{Name: "shop1"}))
+	t.NoError(orm.Session(ctx).Flush(ctx))
+
+	ctx = t.d3Orm.CtxWithSession(context.Background())
+	var shop *Shop
+	err := orm.Session(ctx).Transactional(ctx, func(ctx context.Context) error {
+		fetched, err := repository.FindOne(ctx, repository.Select().Where("name", "=", "shop1"))
+		if err != nil {
+			return err
+		}
+		shop = fetched.(*Shop)
+		return nil
+	}, orm.TxOptions{ReadOnly: true})
+	t.NoError(err)
+
+	err = orm.Session(ctx).Transactional(ctx, func(ctx context.Context) error {
+		shop.Name = "shop2"
+		return nil
+	}, orm.TxOptions{ReadOnly: true})
+	t.True(errors.Is(err, orm.ErrReadOnlyTx))
+	t.tester.SeeOne("SELECT * FROM shop_p WHERE name = 'shop1'")
+}
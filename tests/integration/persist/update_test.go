@@ -39,9 +39,9 @@ func (u *UpdateTs) SetupTest() {
 
 func (u *UpdateTs) TearDownSuite() {
 	u.NoError(u.execSqlFn(`
+DROP TABLE book_author_p;
 DROP TABLE book_p;
 DROP TABLE author_p;
-DROP TABLE book_author_p;
 DROP TABLE shop_p;
 DROP TABLE profile_p;
 `))
@@ -83,15 +83,28 @@ func TestSQLiteUpdateSuite(t *testing.T) {
 	suite.Run(t, ts)
 }
 
+func TestMySQLUpdateSuite(t *testing.T) {
+	adapter, d3orm, execSqlFn, tester := db.CreateMySQLTestComponents(t)
+
+	ts := &UpdateTs{
+		d3Orm:     d3orm,
+		dbAdapter: adapter,
+		execSqlFn: execSqlFn,
+		tester:    tester,
+	}
+
+	suite.Run(t, ts)
+}
+
 func (u *UpdateTs) TestInsertThenUpdate() {
 	shop, err := createAndPersistsShop(u.ctx, u.d3Orm)
 	u.NoError(err)
 
-	u.NoError(orm.Session(u.ctx).Flush())
+	u.NoError(orm.Session(u.ctx).Flush(u.ctx))
 
 	shop.Name = "new shop"
 
-	u.NoError(orm.Session(u.ctx).Flush())
+	u.NoError(orm.Session(u.ctx).Flush(u.ctx))
 
 	u.Equal(1, u.dbAdapter.UpdateCounter())
 	u.tester.
@@ -103,11 +116,11 @@ func (u *UpdateTs) TestInsertThenUpdateOToMRelation() {
 	shop, err := createAndPersistsShop(u.ctx, u.d3Orm)
 	u.NoError(err)
 
-	u.NoError(orm.Session(u.ctx).Flush())
+	u.NoError(orm.Session(u.ctx).Flush(u.ctx))
 
 	shop.Books.Remove(0)
 
-	u.NoError(orm.Session(u.ctx).Flush())
+	u.NoError(orm.Session(u.ctx).Flush(u.ctx))
 
 	u.Equal(1, u.dbAdapter.UpdateCounter())
 	u.tester.
@@ -119,14 +132,14 @@ func (u *UpdateTs) TestInsertThenUpdateMToMRelations() {
 	shop, err := createAndPersistsShop(u.ctx, u.d3Orm)
 	u.NoError(err)
 
-	u.NoError(orm.Session(u.ctx).Flush())
+	u.NoError(orm.Session(u.ctx).Flush(u.ctx))
 
 	book := shop.Books.Get(0).(*Book)
 	author := book.Authors.Get(1).(*Author)
 
 	book.Authors.Remove(1)
 
-	u.NoError(orm.Session(u.ctx).Flush())
+	u.NoError(orm.Session(u.ctx).Flush(u.ctx))
 
 	u.Equal(1, u.dbAdapter.DeleteCounter())
 	u.tester.
@@ -137,7 +150,7 @@ func (u *UpdateTs) TestInsertThenFullUpdate() {
 	shop, err := createAndPersistsShop(u.ctx, u.d3Orm)
 	u.NoError(err)
 
-	u.NoError(orm.Session(u.ctx).Flush())
+	u.NoError(orm.Session(u.ctx).Flush(u.ctx))
 
 	newProfile := &ShopProfile{Description: "new shop profile"}
 	shop.Profile = entity.NewCell(newProfile)
@@ -153,7 +166,7 @@ func (u *UpdateTs) TestInsertThenFullUpdate() {
 	oldBook.Authors.Remove(1)
 
 	u.dbAdapter.ResetCounters()
-	u.NoError(orm.Session(u.ctx).Flush())
+	u.NoError(orm.Session(u.ctx).Flush(u.ctx))
 
 	u.Equal(1, u.dbAdapter.DeleteCounter())
 	u.Equal(2, u.dbAdapter.UpdateCounter())
@@ -204,7 +217,7 @@ func (u *UpdateTs) TestSelectThenSimpleUpdate() {
 	shop2i.(*Shop).Name = "new shop 1002 name"
 
 	u.dbAdapter.ResetCounters()
-	u.NoError(orm.Session(u.ctx).Flush())
+	u.NoError(orm.Session(u.ctx).Flush(u.ctx))
 
 	u.Equal(2, u.dbAdapter.UpdateCounter())
 
@@ -225,7 +238,7 @@ func (u *UpdateTs) TestSelectThenUpdateOtoORelation() {
 	shop1i.(*Shop).Profile.Unwrap().(*ShopProfile).Description = "new shop 1001 profile"
 
 	u.dbAdapter.ResetCounters()
-	u.NoError(orm.Session(u.ctx).Flush())
+	u.NoError(orm.Session(u.ctx).Flush(u.ctx))
 
 	u.Equal(1, u.dbAdapter.UpdateCounter())
 
@@ -245,7 +258,7 @@ func (u *UpdateTs) TestSelectThenDeleteOtoORelation() {
 	shop1i.(*Shop).Profile = entity.NewCell(nil)
 
 	u.dbAdapter.ResetCounters()
-	u.NoError(orm.Session(u.ctx).Flush())
+	u.NoError(orm.Session(u.ctx).Flush(u.ctx))
 
 	u.Equal(1, u.dbAdapter.UpdateCounter())
 
@@ -267,7 +280,7 @@ func (u *UpdateTs) TestSelectThenChangeOtoORelation() {
 	})
 
 	u.dbAdapter.ResetCounters()
-	u.NoError(orm.Session(u.ctx).Flush())
+	u.NoError(orm.Session(u.ctx).Flush(u.ctx))
 
 	u.Equal(1, u.dbAdapter.UpdateCounter())
 
@@ -289,7 +302,7 @@ func (u *UpdateTs) TestSelectThenViewButDontChangeOtoORelation() {
 	shop1i.(*Shop).Profile.Unwrap().(*ShopProfile).Description = "desc1"
 
 	u.dbAdapter.ResetCounters()
-	u.NoError(orm.Session(u.ctx).Flush())
+	u.NoError(orm.Session(u.ctx).Flush(u.ctx))
 
 	u.Equal(0, u.dbAdapter.UpdateCounter())
 }
@@ -307,7 +320,7 @@ func (u *UpdateTs) TestSelectThenUpdateOtoMRelation() {
 	shop1.(*Shop).Books.Get(1).(*Book).Name = "new book 1"
 
 	u.dbAdapter.ResetCounters()
-	u.NoError(orm.Session(u.ctx).Flush())
+	u.NoError(orm.Session(u.ctx).Flush(u.ctx))
 
 	u.Equal(2, u.dbAdapter.UpdateCounter())
 
@@ -329,7 +342,7 @@ func (u *UpdateTs) TestSelectThenDeleteOtoMRelation() {
 	shop1.(*Shop).Books.Remove(0)
 
 	u.dbAdapter.ResetCounters()
-	u.NoError(orm.Session(u.ctx).Flush())
+	u.NoError(orm.Session(u.ctx).Flush(u.ctx))
 
 	u.Equal(1, u.dbAdapter.UpdateCounter())
 
@@ -353,7 +366,7 @@ func (u *UpdateTs) TestSelectThenAddOtoMRelation() {
 	shop1.(*Shop).Books.Add(newBook)
 
 	u.dbAdapter.ResetCounters()
-	u.NoError(orm.Session(u.ctx).Flush())
+	u.NoError(orm.Session(u.ctx).Flush(u.ctx))
 
 	u.Equal(1, u.dbAdapter.InsertCounter())
 
@@ -374,7 +387,7 @@ func (u *UpdateTs) TestSelectThenViewButDontChangeOtoMRelation() {
 	shop1.(*Shop).Books.Get(0).(*Book).Name = sameName
 
 	u.dbAdapter.ResetCounters()
-	u.NoError(orm.Session(u.ctx).Flush())
+	u.NoError(orm.Session(u.ctx).Flush(u.ctx))
 
 	u.Equal(0, u.dbAdapter.UpdateCounter())
 }
@@ -392,7 +405,7 @@ func (u *UpdateTs) TestSelectThenUpdateMtoMRelation() {
 	book1.(*Book).Authors.Get(1).(*Author).Name = "new author 2"
 
 	u.dbAdapter.ResetCounters()
-	u.NoError(orm.Session(u.ctx).Flush())
+	u.NoError(orm.Session(u.ctx).Flush(u.ctx))
 
 	u.Equal(2, u.dbAdapter.UpdateCounter())
 
@@ -414,7 +427,7 @@ func (u *UpdateTs) TestSelectThenDeleteMtoMRelation() {
 	book1.(*Book).Authors.Remove(1)
 
 	u.dbAdapter.ResetCounters()
-	u.NoError(orm.Session(u.ctx).Flush())
+	u.NoError(orm.Session(u.ctx).Flush(u.ctx))
 
 	u.Equal(1, u.dbAdapter.DeleteCounter())
 
@@ -440,7 +453,7 @@ func (u *UpdateTs) TestSelectThenAddMtoMRelation() {
 	book2.(*Book).Authors.Add(newAuthor)
 
 	u.dbAdapter.ResetCounters()
-	u.NoError(orm.Session(u.ctx).Flush())
+	u.NoError(orm.Session(u.ctx).Flush(u.ctx))
 
 	u.Equal(3, u.dbAdapter.InsertCounter())
 
@@ -463,7 +476,7 @@ func (u *UpdateTs) TestSelectThenViewButDontChangeMtoMRelation() {
 	book1.(*Book).Authors.Get(0).(*Author).Name = sameName
 
 	u.dbAdapter.ResetCounters()
-	u.NoError(orm.Session(u.ctx).Flush())
+	u.NoError(orm.Session(u.ctx).Flush(u.ctx))
 
 	u.Equal(0, u.dbAdapter.UpdateCounter())
 }
@@ -493,7 +506,7 @@ func (u *UpdateTs) TestSelectThenFullUpdate() {
 	oldBook.Authors.Remove(0)
 
 	u.dbAdapter.ResetCounters()
-	u.NoError(orm.Session(u.ctx).Flush())
+	u.NoError(orm.Session(u.ctx).Flush(u.ctx))
 
 	u.Equal(1, u.dbAdapter.DeleteCounter())
 	u.Equal(2, u.dbAdapter.UpdateCounter())
@@ -517,7 +530,7 @@ func (u *UpdateTs) TestDoubleAddMToMIsIdempotence() {
 	}
 
 	u.NoError(repo.Persists(u.ctx, book))
-	u.NoError(orm.Session(u.ctx).Flush())
+	u.NoError(orm.Session(u.ctx).Flush(u.ctx))
 
 	newCtx := u.d3Orm.CtxWithSession(context.Background())
 	fetchedEntity, err := repo.FindOne(newCtx, repo.Select().Where("id", "=", book.Id))
@@ -527,7 +540,7 @@ func (u *UpdateTs) TestDoubleAddMToMIsIdempotence() {
 	fetchedBook.Authors.Add(author)
 
 	u.NoError(repo.Persists(newCtx, book))
-	u.NoError(orm.Session(newCtx).Flush())
+	u.NoError(orm.Session(newCtx).Flush(newCtx))
 
 	u.tester.
 		SeeOne("SELECT * FROM book_author_p")
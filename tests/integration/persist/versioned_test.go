@@ -0,0 +1,176 @@
+package persist
+
+import (
+	"context"
+	"errors"
+	"github.com/godzie44/d3/orm"
+	"github.com/godzie44/d3/orm/entity"
+	"github.com/godzie44/d3/orm/persistence"
+	"github.com/godzie44/d3/tests/helpers"
+	"github.com/godzie44/d3/tests/helpers/db"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type PersistsVersionedTS struct {
+	suite.Suite
+	tester    helpers.DBTester
+	dbAdapter *helpers.DbAdapterWithQueryCounter
+	d3Orm     *orm.Orm
+	execSqlFn func(sql string) error
+}
+
+func (o *PersistsVersionedTS) SetupSuite() {
+	o.NoError(o.d3Orm.Register((*Document)(nil)))
+
+	schemaSql, err := o.d3Orm.GenerateSchema()
+	o.NoError(err)
+
+	o.NoError(o.execSqlFn(schemaSql))
+}
+
+func (o *PersistsVersionedTS) TearDownSuite() {
+	o.NoError(o.execSqlFn(`DROP TABLE document_ver;`))
+}
+
+func (o *PersistsVersionedTS) TearDownTest() {
+	o.dbAdapter.ResetCounters()
+	o.NoError(o.execSqlFn(`delete from document_ver;`))
+}
+
+func TestPGPersistsVersionedSuite(t *testing.T) {
+	adapter, d3orm, execSqlFn, tester := db.CreatePGTestComponents(t)
+
+	ts := &PersistsVersionedTS{
+		dbAdapter: adapter,
+		d3Orm:     d3orm,
+		execSqlFn: execSqlFn,
+		tester:    tester,
+	}
+	suite.Run(t, ts)
+}
+
+func TestSQLitePersistsVersionedSuite(t *testing.T) {
+	adapter, d3orm, execSqlFn, tester := db.CreateSQLiteTestComponents(t, "_versioned")
+
+	ts := &PersistsVersionedTS{
+		d3Orm:     d3orm,
+		dbAdapter: adapter,
+		execSqlFn: execSqlFn,
+		tester:    tester,
+	}
+	suite.Run(t, ts)
+}
+
+func (o *PersistsVersionedTS) createDocument() {
+	ctx := o.d3Orm.CtxWithSession(context.Background())
+	repository, err := o.d3Orm.MakeRepository((*Document)(nil))
+	o.NoError(err)
+
+	o.NoError(repository.Persists(ctx, &Document{Title: "draft", Version: 1}))
+	o.NoError(orm.Session(ctx).Flush(ctx))
+}
+
+func (o *PersistsVersionedTS) fetchDocument(ctx context.Context) *Document {
+	repository, err := o.d3Orm.MakeRepository((*Document)(nil))
+	o.NoError(err)
+
+	doc, err := repository.FindOne(ctx, repository.Select().Where("document_ver.title", "=", "draft"))
+	o.NoError(err)
+
+	return doc.(*Document)
+}
+
+func (o *PersistsVersionedTS) TestUpdateIncrementVersion() {
+	o.createDocument()
+
+	ctx := o.d3Orm.CtxWithSession(context.Background())
+	doc := o.fetchDocument(ctx)
+
+	doc.Title = "final"
+	o.dbAdapter.ResetCounters()
+	o.NoError(orm.Session(ctx).Flush(ctx))
+
+	o.Equal(1, o.dbAdapter.UpdateCounter())
+	o.Equal(int64(2), doc.Version)
+	o.tester.SeeOne("SELECT * FROM document_ver WHERE title = 'final' AND version = 2")
+
+	o.NoError(orm.Session(ctx).Flush(ctx))
+	o.Equal(1, o.dbAdapter.UpdateCounter())
+	o.tester.SeeOne("SELECT * FROM document_ver WHERE title = 'final' AND version = 2")
+}
+
+func (o *PersistsVersionedTS) TestConcurrentUpdate() {
+	o.createDocument()
+
+	ctx1 := o.d3Orm.CtxWithSession(context.Background())
+	doc1 := o.fetchDocument(ctx1)
+
+	ctx2 := o.d3Orm.CtxWithSession(context.Background())
+	doc2 := o.fetchDocument(ctx2)
+
+	doc1.Title = "first"
+	o.NoError(orm.Session(ctx1).Flush(ctx1))
+
+	doc2.Title = "second"
+	err := orm.Session(ctx2).Flush(ctx2)
+	o.True(errors.Is(err, orm.ErrOptimisticLock))
+
+	var lockErr *persistence.OptimisticLockError
+	o.True(errors.As(err, &lockErr))
+	o.Equal(entity.Name("github.com/godzie44/d3/tests/integration/persist/Document"), lockErr.Entity)
+	o.Equal(int64(1), lockErr.Version)
+
+	o.tester.SeeOne("SELECT * FROM document_ver WHERE title = 'first' AND version = 2")
+}
+
+func (o *PersistsVersionedTS) TestRollbackRestoreVersion() {
+	o.createDocument()
+
+	ctx := o.d3Orm.CtxWithSession(context.Background())
+	session := orm.Session(ctx)
+	doc := o.fetchDocument(ctx)
+
+	o.NoError(session.BeginTx(ctx))
+	doc.Title = "final"
+	o.NoError(session.Flush(ctx))
+	o.Equal(int64(2), doc.Version)
+	o.NoError(session.RollbackTx())
+
+	o.Equal(int64(1), doc.Version)
+
+	doc.Title = "final2"
+	o.NoError(session.Flush(ctx))
+	o.Equal(int64(2), doc.Version)
+	o.tester.SeeOne("SELECT * FROM document_ver WHERE title = 'final2' AND version = 2")
+}
+
+func (o *PersistsVersionedTS) TestFailedFlushRestoreVersion() {
+	o.createDocument()
+
+	ctx := o.d3Orm.CtxWithSession(context.Background())
+	repository, err := o.d3Orm.MakeRepository((*Document)(nil))
+	o.NoError(err)
+	o.NoError(repository.Persists(ctx, &Document{Title: "other", Version: 1}))
+	o.NoError(orm.Session(ctx).Flush(ctx))
+
+	ctx1 := o.d3Orm.CtxWithSession(context.Background())
+	docs, err := repository.FindAll(ctx1, repository.Select())
+	o.NoError(err)
+	o.Equal(2, docs.Count())
+
+	ctx2 := o.d3Orm.CtxWithSession(context.Background())
+	concurrent := o.fetchDocument(ctx2)
+	concurrent.Title = "draft2"
+	o.NoError(orm.Session(ctx2).Flush(ctx2))
+
+	// one of documents may be updated before optimistic lock failure, rolled back version must be restored
+	for _, doc := range docs.ToSlice() {
+		doc.(*Document).Title += " final"
+	}
+	o.True(errors.Is(orm.Session(ctx1).Flush(ctx1), orm.ErrOptimisticLock))
+
+	for _, doc := range docs.ToSlice() {
+		o.Equal(int64(1), doc.(*Document).Version)
+	}
+}
@@ -0,0 +1,13 @@
+package persist
+
+import (
+	"database/sql"
+)
+
+//d3:entity
+//d3_table:document_ver
+type Document struct {
+	Id      sql.NullInt32 `d3:"pk:auto"`
+	Title   string
+	Version int64 `d3:"version"`
+}
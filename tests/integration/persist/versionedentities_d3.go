@@ -0,0 +1,128 @@
+// Code generated by d3. DO NOT EDIT.
+
+package persist
+
+import "fmt"
+import "github.com/godzie44/d3/orm/entity"
+import "database/sql/driver"
+
+func (d *Document) D3Token() entity.MetaToken {
+	return entity.MetaToken{
+		Tpl:       (*Document)(nil),
+		TableName: "document_ver",
+		Tools: entity.InternalTools{
+			ExtractField:  d.__d3_makeFieldExtractor(),
+			SetFieldVal:   d.__d3_makeFieldSetter(),
+			CompareFields: d.__d3_makeComparator(),
+			NewInstance:   d.__d3_makeInstantiator(),
+			Copy:          d.__d3_makeCopier(),
+		},
+		Indexes: []entity.Index{},
+	}
+}
+
+func (d *Document) __d3_makeFieldExtractor() entity.FieldExtractor {
+	return func(s interface{}, name string) (interface{}, error) {
+		sTyped, ok := s.(*Document)
+		if !ok {
+			return nil, fmt.Errorf("invalid entity type")
+		}
+
+		switch name {
+
+		case "Id":
+			return sTyped.Id, nil
+
+		case "Title":
+			return sTyped.Title, nil
+
+		case "Version":
+			return sTyped.Version, nil
+
+		default:
+			return nil, fmt.Errorf("field %s not found", name)
+		}
+	}
+}
+
+func (d *Document) __d3_makeInstantiator() entity.Instantiator {
+	return func() interface{} {
+		return &Document{}
+	}
+}
+
+func (d *Document) __d3_makeFieldSetter() entity.FieldSetter {
+	return func(s interface{}, name string, val interface{}) error {
+		eTyped, ok := s.(*Document)
+		if !ok {
+			return fmt.Errorf("invalid entity type")
+		}
+
+		switch name {
+		case "Title":
+			eTyped.Title = val.(string)
+			return nil
+		case "Version":
+			eTyped.Version = val.(int64)
+			return nil
+
+		case "Id":
+			if valuer, isValuer := val.(driver.Valuer); isValuer {
+				v, err := valuer.Value()
+				if err != nil {
+					return eTyped.Id.Scan(nil)
+				}
+				return eTyped.Id.Scan(v)
+			}
+			return eTyped.Id.Scan(val)
+		default:
+			return fmt.Errorf("field %s not found", name)
+		}
+	}
+}
+
+func (d *Document) __d3_makeCopier() entity.Copier {
+	return func(src interface{}) interface{} {
+		srcTyped, ok := src.(*Document)
+		if !ok {
+			return fmt.Errorf("invalid entity type")
+		}
+
+		copy := &Document{}
+
+		copy.Id = srcTyped.Id
+		copy.Title = srcTyped.Title
+		copy.Version = srcTyped.Version
+
+		return copy
+	}
+}
+
+func (d *Document) __d3_makeComparator() entity.FieldComparator {
+	return func(e1, e2 interface{}, fName string) bool {
+		if e1 == nil || e2 == nil {
+			return e1 == e2
+		}
+
+		e1Typed, ok := e1.(*Document)
+		if !ok {
+			return false
+		}
+		e2Typed, ok := e2.(*Document)
+		if !ok {
+			return false
+		}
+
+		switch fName {
+
+		case "Id":
+			return e1Typed.Id == e2Typed.Id
+		case "Title":
+			return e1Typed.Title == e2Typed.Title
+		case "Version":
+			return e1Typed.Version == e2Typed.Version
+		default:
+			return false
+		}
+	}
+}
@@ -20,3 +20,28 @@ type Photo struct {
 	id  sql.NullInt64 `d3:"pk:auto"`
 	src string
 }
+
+//d3:entity
+//d3_table:q_order
+type Order struct {
+	id              sql.NullInt64 `d3:"pk:auto"`
+	billingAddress  *entity.Cell  `d3:"one_to_one:<target_entity:Address,join_on:billing_address_id>,type:lazy"`
+	shippingAddress *entity.Cell  `d3:"one_to_one:<target_entity:Address,join_on:shipping_address_id>,type:lazy"`
+	buyer           *entity.Cell  `d3:"one_to_one:<target_entity:User,join_on:buyer_id>,type:lazy"`
+	number          string
+}
+
+//d3:entity
+//d3_table:q_address
+type Address struct {
+	id      sql.NullInt64 `d3:"pk:auto"`
+	country *entity.Cell  `d3:"one_to_one:<target_entity:Country,join_on:country_id>,type:lazy"`
+	city    string
+}
+
+//d3:entity
+//d3_table:q_country
+type Country struct {
+	id   sql.NullInt64 `d3:"pk:auto"`
+	name string
+}
@@ -250,3 +250,385 @@ func (p *Photo) __d3_makeComparator() entity.FieldComparator {
 		}
 	}
 }
+
+func (o *Order) D3Token() entity.MetaToken {
+	return entity.MetaToken{
+		Tpl:       (*Order)(nil),
+		TableName: "q_order",
+		Tools: entity.InternalTools{
+			ExtractField:  o.__d3_makeFieldExtractor(),
+			SetFieldVal:   o.__d3_makeFieldSetter(),
+			CompareFields: o.__d3_makeComparator(),
+			NewInstance:   o.__d3_makeInstantiator(),
+			Copy:          o.__d3_makeCopier(),
+		},
+		Indexes: []entity.Index{},
+	}
+}
+
+func (o *Order) __d3_makeFieldExtractor() entity.FieldExtractor {
+	return func(s interface{}, name string) (interface{}, error) {
+		sTyped, ok := s.(*Order)
+		if !ok {
+			return nil, fmt.Errorf("invalid entity type")
+		}
+
+		switch name {
+
+		case "id":
+			return sTyped.id, nil
+
+		case "billingAddress":
+			return sTyped.billingAddress, nil
+
+		case "shippingAddress":
+			return sTyped.shippingAddress, nil
+
+		case "buyer":
+			return sTyped.buyer, nil
+
+		case "number":
+			return sTyped.number, nil
+
+		default:
+			return nil, fmt.Errorf("field %s not found", name)
+		}
+	}
+}
+
+func (o *Order) __d3_makeInstantiator() entity.Instantiator {
+	return func() interface{} {
+		return &Order{}
+	}
+}
+
+func (o *Order) __d3_makeFieldSetter() entity.FieldSetter {
+	return func(s interface{}, name string, val interface{}) error {
+		eTyped, ok := s.(*Order)
+		if !ok {
+			return fmt.Errorf("invalid entity type")
+		}
+
+		switch name {
+		case "billingAddress":
+			eTyped.billingAddress = val.(*entity.Cell)
+			return nil
+		case "shippingAddress":
+			eTyped.shippingAddress = val.(*entity.Cell)
+			return nil
+		case "buyer":
+			eTyped.buyer = val.(*entity.Cell)
+			return nil
+		case "number":
+			eTyped.number = val.(string)
+			return nil
+
+		case "id":
+			if valuer, isValuer := val.(driver.Valuer); isValuer {
+				v, err := valuer.Value()
+				if err != nil {
+					return eTyped.id.Scan(nil)
+				}
+				return eTyped.id.Scan(v)
+			}
+			return eTyped.id.Scan(val)
+		default:
+			return fmt.Errorf("field %s not found", name)
+		}
+	}
+}
+
+func (o *Order) __d3_makeCopier() entity.Copier {
+	return func(src interface{}) interface{} {
+		srcTyped, ok := src.(*Order)
+		if !ok {
+			return fmt.Errorf("invalid entity type")
+		}
+
+		copy := &Order{}
+
+		copy.id = srcTyped.id
+		copy.number = srcTyped.number
+
+		if srcTyped.billingAddress != nil {
+			copy.billingAddress = srcTyped.billingAddress.DeepCopy().(*entity.Cell)
+		}
+		if srcTyped.shippingAddress != nil {
+			copy.shippingAddress = srcTyped.shippingAddress.DeepCopy().(*entity.Cell)
+		}
+		if srcTyped.buyer != nil {
+			copy.buyer = srcTyped.buyer.DeepCopy().(*entity.Cell)
+		}
+
+		return copy
+	}
+}
+
+func (o *Order) __d3_makeComparator() entity.FieldComparator {
+	return func(e1, e2 interface{}, fName string) bool {
+		if e1 == nil || e2 == nil {
+			return e1 == e2
+		}
+
+		e1Typed, ok := e1.(*Order)
+		if !ok {
+			return false
+		}
+		e2Typed, ok := e2.(*Order)
+		if !ok {
+			return false
+		}
+
+		switch fName {
+
+		case "id":
+			return e1Typed.id == e2Typed.id
+		case "billingAddress":
+			return e1Typed.billingAddress == e2Typed.billingAddress
+		case "shippingAddress":
+			return e1Typed.shippingAddress == e2Typed.shippingAddress
+		case "buyer":
+			return e1Typed.buyer == e2Typed.buyer
+		case "number":
+			return e1Typed.number == e2Typed.number
+		default:
+			return false
+		}
+	}
+}
+
+func (a *Address) D3Token() entity.MetaToken {
+	return entity.MetaToken{
+		Tpl:       (*Address)(nil),
+		TableName: "q_address",
+		Tools: entity.InternalTools{
+			ExtractField:  a.__d3_makeFieldExtractor(),
+			SetFieldVal:   a.__d3_makeFieldSetter(),
+			CompareFields: a.__d3_makeComparator(),
+			NewInstance:   a.__d3_makeInstantiator(),
+			Copy:          a.__d3_makeCopier(),
+		},
+		Indexes: []entity.Index{},
+	}
+}
+
+func (a *Address) __d3_makeFieldExtractor() entity.FieldExtractor {
+	return func(s interface{}, name string) (interface{}, error) {
+		sTyped, ok := s.(*Address)
+		if !ok {
+			return nil, fmt.Errorf("invalid entity type")
+		}
+
+		switch name {
+
+		case "id":
+			return sTyped.id, nil
+
+		case "country":
+			return sTyped.country, nil
+
+		case "city":
+			return sTyped.city, nil
+
+		default:
+			return nil, fmt.Errorf("field %s not found", name)
+		}
+	}
+}
+
+func (a *Address) __d3_makeInstantiator() entity.Instantiator {
+	return func() interface{} {
+		return &Address{}
+	}
+}
+
+func (a *Address) __d3_makeFieldSetter() entity.FieldSetter {
+	return func(s interface{}, name string, val interface{}) error {
+		eTyped, ok := s.(*Address)
+		if !ok {
+			return fmt.Errorf("invalid entity type")
+		}
+
+		switch name {
+		case "country":
+			eTyped.country = val.(*entity.Cell)
+			return nil
+		case "city":
+			eTyped.city = val.(string)
+			return nil
+
+		case "id":
+			if valuer, isValuer := val.(driver.Valuer); isValuer {
+				v, err := valuer.Value()
+				if err != nil {
+					return eTyped.id.Scan(nil)
+				}
+				return eTyped.id.Scan(v)
+			}
+			return eTyped.id.Scan(val)
+		default:
+			return fmt.Errorf("field %s not found", name)
+		}
+	}
+}
+
+func (a *Address) __d3_makeCopier() entity.Copier {
+	return func(src interface{}) interface{} {
+		srcTyped, ok := src.(*Address)
+		if !ok {
+			return fmt.Errorf("invalid entity type")
+		}
+
+		copy := &Address{}
+
+		copy.id = srcTyped.id
+		copy.city = srcTyped.city
+
+		if srcTyped.country != nil {
+			copy.country = srcTyped.country.DeepCopy().(*entity.Cell)
+		}
+
+		return copy
+	}
+}
+
+func (a *Address) __d3_makeComparator() entity.FieldComparator {
+	return func(e1, e2 interface{}, fName string) bool {
+		if e1 == nil || e2 == nil {
+			return e1 == e2
+		}
+
+		e1Typed, ok := e1.(*Address)
+		if !ok {
+			return false
+		}
+		e2Typed, ok := e2.(*Address)
+		if !ok {
+			return false
+		}
+
+		switch fName {
+
+		case "id":
+			return e1Typed.id == e2Typed.id
+		case "country":
+			return e1Typed.country == e2Typed.country
+		case "city":
+			return e1Typed.city == e2Typed.city
+		default:
+			return false
+		}
+	}
+}
+
+func (c *Country) D3Token() entity.MetaToken {
+	return entity.MetaToken{
+		Tpl:       (*Country)(nil),
+		TableName: "q_country",
+		Tools: entity.InternalTools{
+			ExtractField:  c.__d3_makeFieldExtractor(),
+			SetFieldVal:   c.__d3_makeFieldSetter(),
+			CompareFields: c.__d3_makeComparator(),
+			NewInstance:   c.__d3_makeInstantiator(),
+			Copy:          c.__d3_makeCopier(),
+		},
+		Indexes: []entity.Index{},
+	}
+}
+
+func (c *Country) __d3_makeFieldExtractor() entity.FieldExtractor {
+	return func(s interface{}, name string) (interface{}, error) {
+		sTyped, ok := s.(*Country)
+		if !ok {
+			return nil, fmt.Errorf("invalid entity type")
+		}
+
+		switch name {
+
+		case "id":
+			return sTyped.id, nil
+
+		case "name":
+			return sTyped.name, nil
+
+		default:
+			return nil, fmt.Errorf("field %s not found", name)
+		}
+	}
+}
+
+func (c *Country) __d3_makeInstantiator() entity.Instantiator {
+	return func() interface{} {
+		return &Country{}
+	}
+}
+
+func (c *Country) __d3_makeFieldSetter() entity.FieldSetter {
+	return func(s interface{}, name string, val interface{}) error {
+		eTyped, ok := s.(*Country)
+		if !ok {
+			return fmt.Errorf("invalid entity type")
+		}
+
+		switch name {
+		case "name":
+			eTyped.name = val.(string)
+			return nil
+
+		case "id":
+			if valuer, isValuer := val.(driver.Valuer); isValuer {
+				v, err := valuer.Value()
+				if err != nil {
+					return eTyped.id.Scan(nil)
+				}
+				return eTyped.id.Scan(v)
+			}
+			return eTyped.id.Scan(val)
+		default:
+			return fmt.Errorf("field %s not found", name)
+		}
+	}
+}
+
+func (c *Country) __d3_makeCopier() entity.Copier {
+	return func(src interface{}) interface{} {
+		srcTyped, ok := src.(*Country)
+		if !ok {
+			return fmt.Errorf("invalid entity type")
+		}
+
+		copy := &Country{}
+
+		copy.id = srcTyped.id
+		copy.name = srcTyped.name
+
+		return copy
+	}
+}
+
+func (c *Country) __d3_makeComparator() entity.FieldComparator {
+	return func(e1, e2 interface{}, fName string) bool {
+		if e1 == nil || e2 == nil {
+			return e1 == e2
+		}
+
+		e1Typed, ok := e1.(*Country)
+		if !ok {
+			return false
+		}
+		e2Typed, ok := e2.(*Country)
+		if !ok {
+			return false
+		}
+
+		switch fName {
+
+		case "id":
+			return e1Typed.id == e2Typed.id
+		case "name":
+			return e1Typed.name == e2Typed.name
+		default:
+			return false
+		}
+	}
+}
@@ -2,12 +2,15 @@ package query
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
 	"github.com/godzie44/d3/orm"
 	"github.com/godzie44/d3/orm/query"
 	"github.com/godzie44/d3/tests/helpers"
 	"github.com/godzie44/d3/tests/helpers/db"
 	"github.com/stretchr/testify/suite"
 	"math"
+	"strings"
 	"testing"
 )
 
@@ -22,6 +25,9 @@ func (qts *QueryTS) SetupSuite() {
 	qts.Assert().NoError(qts.orm.Register(
 		(*User)(nil),
 		(*Photo)(nil),
+		(*Order)(nil),
+		(*Address)(nil),
+		(*Country)(nil),
 	))
 
 	sql, err := qts.orm.GenerateSchema()
@@ -44,14 +50,25 @@ INSERT INTO q_photo(user_id, src) VALUES (2, 'http://sara_pic_url');
 INSERT INTO q_photo(user_id, src) VALUES (4, 'http://victor_pic_url');
 INSERT INTO q_photo(user_id, src) VALUES (5, 'http://emili_pic_url');
 INSERT INTO q_photo(user_id, src) VALUES (5, 'http://emili_pic_url');
+INSERT INTO q_country(name) VALUES ('France');
+INSERT INTO q_country(name) VALUES ('Japan');
+INSERT INTO q_address(city, country_id) VALUES ('Paris', 1);
+INSERT INTO q_address(city, country_id) VALUES ('Tokyo', 2);
+INSERT INTO q_address(city, country_id) VALUES ('Lyon', 1);
+INSERT INTO q_order(number, billing_address_id, shipping_address_id, buyer_id) VALUES ('A-1', 1, 2, 2);
+INSERT INTO q_order(number, billing_address_id, shipping_address_id, buyer_id) VALUES ('A-2', 3, 3, 5);
+INSERT INTO q_order(number, billing_address_id, shipping_address_id, buyer_id) VALUES ('A-3', 1, NULL, NULL);
 `)
 	qts.NoError(err)
 }
 
 func (qts *QueryTS) TearDownSuite() {
 	qts.Assert().NoError(qts.execSqlFn(`
-DROP TABLE q_user;
+DROP TABLE q_order;
+DROP TABLE q_address;
+DROP TABLE q_country;
 DROP TABLE q_photo;
+DROP TABLE q_user;
 `))
 }
 
@@ -212,7 +229,7 @@ func (qts *QueryTS) TestQueryJoin() {
 
 	q := query.New().Select("*").From("q_user").Join(query.JoinInner, "q_photo", "q_user.id=q_photo.user_id")
 
-	result, err := session.Execute(q)
+	result, err := session.Execute(context.Background(), q)
 	qts.Assert().NoError(err)
 
 	qts.Assert().Len(result, 5)
@@ -223,7 +240,7 @@ func (qts *QueryTS) TestQueryGroupBy() {
 
 	q := query.New().Select("age", "count(*)").From("q_user").GroupBy("age")
 
-	result, err := session.Execute(q)
+	result, err := session.Execute(context.Background(), q)
 	qts.Assert().NoError(err)
 
 	qts.Assert().Len(result, 7)
@@ -239,8 +256,299 @@ func (qts *QueryTS) TestQueryHaving() {
 
 	q := query.New().Select("age").From("q_user").GroupBy("age").Having("count(*)", ">", 1)
 
-	result, err := session.Execute(q)
+	result, err := session.Execute(context.Background(), q)
 	qts.Assert().NoError(err)
 
 	qts.Assert().Len(result, 2)
 }
+
+func (qts *QueryTS) TestCount() {
+	ctx := qts.orm.CtxWithSession(context.Background())
+	rep, err := qts.orm.MakeRepository((*User)(nil))
+	qts.Assert().NoError(err)
+
+	count, err := rep.Count(ctx, rep.Select().Where("name", "=", "Sara").OrderBy("age ASC").Limit(1))
+	qts.Assert().NoError(err)
+	qts.Assert().Equal(int64(3), count)
+
+	q := rep.Select()
+	qts.Assert().NoError(q.With("github.com/godzie44/d3/tests/integration/query/Photo"))
+	count, err = rep.Count(ctx, q.Where("q_photo.user_id", "IS NOT NULL"))
+	qts.Assert().NoError(err)
+	qts.Assert().Equal(int64(3), count)
+
+	qts.Assert().Equal(2, qts.driver.QueryCounter())
+}
+
+func (qts *QueryTS) TestExists() {
+	ctx := qts.orm.CtxWithSession(context.Background())
+	rep, err := qts.orm.MakeRepository((*User)(nil))
+	qts.Assert().NoError(err)
+
+	exists, err := rep.Exists(ctx, rep.Select().Where("name", "=", "Victor"))
+	qts.Assert().NoError(err)
+	qts.Assert().True(exists)
+
+	exists, err = rep.Exists(ctx, rep.Select().Where("name", "=", "Unknown"))
+	qts.Assert().NoError(err)
+	qts.Assert().False(exists)
+}
+
+func (qts *QueryTS) TestAggregates() {
+	ctx := qts.orm.CtxWithSession(context.Background())
+	rep, err := qts.orm.MakeRepository((*User)(nil))
+	qts.Assert().NoError(err)
+
+	sum, err := rep.Sum(ctx, rep.Select().Where("name", "=", "Sara"), "age")
+	qts.Assert().NoError(err)
+	qts.Assert().Equal(94, sum)
+
+	q := rep.Select()
+	qts.Assert().NoError(q.With("github.com/godzie44/d3/tests/integration/query/Photo"))
+	sum, err = rep.Sum(ctx, q.Where("q_photo.user_id", "IS NOT NULL"), "age")
+	qts.Assert().NoError(err)
+	qts.Assert().Equal(101, sum)
+
+	min, err := rep.Min(ctx, rep.Select(), "age")
+	qts.Assert().NoError(err)
+	qts.Assert().Equal(19, min)
+
+	max, err := rep.Max(ctx, rep.Select().Where("name", "=", "John"), "age")
+	qts.Assert().NoError(err)
+	qts.Assert().Equal(29, max)
+
+	max, err = rep.Max(ctx, rep.Select().Where("name", "=", "Unknown"), "age")
+	qts.Assert().NoError(err)
+	qts.Assert().Nil(max)
+}
+
+func userIds(page *orm.Page) []int64 {
+	ids := make([]int64, 0, page.Entities.Count())
+	iter := page.Entities.MakeIter()
+	for iter.Next() {
+		ids = append(ids, iter.Value().(*User).id.Int64)
+	}
+	return ids
+}
+
+func (qts *QueryTS) TestKeysetPagination() {
+	ctx := qts.orm.CtxWithSession(context.Background())
+	rep, err := qts.orm.MakeRepository((*User)(nil))
+	qts.Assert().NoError(err)
+
+	paginator, err := rep.MakePaginator(4, orm.Asc("age"))
+	qts.Assert().NoError(err)
+
+	page1, err := paginator.Page(ctx, rep.Select(), "")
+	qts.Assert().NoError(err)
+	qts.Assert().Equal([]int64{2, 1, 9, 8}, userIds(page1))
+	qts.Assert().Empty(page1.PrevCursor)
+
+	page2, err := paginator.Page(ctx, rep.Select(), page1.NextCursor)
+	qts.Assert().NoError(err)
+	qts.Assert().Equal([]int64{3, 7, 4, 5}, userIds(page2))
+
+	page3, err := paginator.Page(ctx, rep.Select(), page2.NextCursor)
+	qts.Assert().NoError(err)
+	qts.Assert().Equal([]int64{6}, userIds(page3))
+	qts.Assert().Empty(page3.NextCursor)
+
+	prevPage, err := paginator.Page(ctx, rep.Select(), page3.PrevCursor)
+	qts.Assert().NoError(err)
+	qts.Assert().Equal([]int64{3, 7, 4, 5}, userIds(prevPage))
+
+	prevPage, err = paginator.Page(ctx, rep.Select(), prevPage.PrevCursor)
+	qts.Assert().NoError(err)
+	qts.Assert().Equal([]int64{2, 1, 9, 8}, userIds(prevPage))
+	qts.Assert().Empty(prevPage.PrevCursor)
+	qts.Assert().NotEmpty(prevPage.NextCursor)
+}
+
+func (qts *QueryTS) TestKeysetPaginationMixedDirections() {
+	ctx := qts.orm.CtxWithSession(context.Background())
+	rep, err := qts.orm.MakeRepository((*User)(nil))
+	qts.Assert().NoError(err)
+
+	paginator, err := rep.MakePaginator(3, orm.Desc("name"), orm.Asc("age"))
+	qts.Assert().NoError(err)
+
+	var ids []int64
+	var cursor string
+	for {
+		page, err := paginator.Page(ctx, rep.Select(), cursor)
+		qts.Assert().NoError(err)
+		ids = append(ids, userIds(page)...)
+
+		if cursor = page.NextCursor; cursor == "" {
+			break
+		}
+	}
+
+	qts.Assert().Equal([]int64{4, 2, 7, 6, 3, 9, 8, 1, 5}, ids)
+}
+
+func (qts *QueryTS) TestKeysetPaginationRejectTamperedCursor() {
+	ctx := qts.orm.CtxWithSession(context.Background())
+	rep, err := qts.orm.MakeRepository((*User)(nil))
+	qts.Assert().NoError(err)
+
+	paginator, err := rep.MakePaginator(4, orm.Asc("age"))
+	qts.Assert().NoError(err)
+
+	page, err := paginator.Page(ctx, rep.Select(), "")
+	qts.Assert().NoError(err)
+
+	parts := strings.Split(page.NextCursor, ".")
+	tampered := base64.RawURLEncoding.EncodeToString([]byte(`{"d":"n","v":["0","0"]}`)) + "." + parts[1]
+
+	_, err = paginator.Page(ctx, rep.Select(), tampered)
+	qts.Assert().True(errors.Is(err, orm.ErrInvalidCursor))
+
+	_, err = paginator.Page(ctx, rep.Select(), "garbage")
+	qts.Assert().True(errors.Is(err, orm.ErrInvalidCursor))
+
+	otherPaginator, err := rep.MakePaginator(4, orm.Desc("age"))
+	qts.Assert().NoError(err)
+	_, err = otherPaginator.Page(ctx, rep.Select(), page.NextCursor)
+	qts.Assert().True(errors.Is(err, orm.ErrInvalidCursor))
+}
+
+func (qts *QueryTS) TestQueryWithLimitOffset() {
+	ctx := qts.orm.CtxWithSession(context.Background())
+	rep, err := qts.orm.MakeRepository((*User)(nil))
+	qts.Assert().NoError(err)
+
+	q := rep.Select()
+	qts.Assert().NoError(q.With("github.com/godzie44/d3/tests/integration/query/Photo"))
+
+	users, err := rep.FindAll(ctx, q.Where("q_photo.user_id", "IS NOT NULL").OrderBy("q_user.id ASC").Limit(2))
+	qts.Assert().NoError(err)
+
+	qts.Assert().Equal(2, users.Count())
+	qts.Assert().Equal(int64(2), users.Get(0).(*User).id.Int64)
+	qts.Assert().Equal(2, users.Get(0).(*User).photos.Count())
+	qts.Assert().Equal(int64(4), users.Get(1).(*User).id.Int64)
+	qts.Assert().Equal(1, users.Get(1).(*User).photos.Count())
+
+	qts.Assert().Equal(2, qts.driver.QueryCounter())
+
+	q = rep.Select()
+	qts.Assert().NoError(q.With("github.com/godzie44/d3/tests/integration/query/Photo"))
+
+	users, err = rep.FindAll(ctx, q.Where("q_photo.user_id", "IS NOT NULL").OrderBy("q_user.id DESC").Limit(1).Offset(1))
+	qts.Assert().NoError(err)
+
+	qts.Assert().Equal(1, users.Count())
+	qts.Assert().Equal(int64(4), users.Get(0).(*User).id.Int64)
+	qts.Assert().Equal(1, users.Get(0).(*User).photos.Count())
+
+	q = rep.Select()
+	qts.Assert().NoError(q.With("github.com/godzie44/d3/tests/integration/query/Photo"))
+
+	users, err = rep.FindAll(ctx, q.Where("q_user.name", "=", "Unknown").Limit(2))
+	qts.Assert().NoError(err)
+	qts.Assert().Equal(0, users.Count())
+}
+
+func (qts *QueryTS) TestQueryWithLimitOrderByJoined() {
+	ctx := qts.orm.CtxWithSession(context.Background())
+	rep, err := qts.orm.MakeRepository((*User)(nil))
+	qts.Assert().NoError(err)
+
+	var queries []string
+	qts.driver.BeforeQuery(func(query string, args ...interface{}) {
+		queries = append(queries, query)
+	})
+
+	q := rep.Select()
+	qts.Assert().NoError(q.With("github.com/godzie44/d3/tests/integration/query/Photo"))
+
+	users, err := rep.FindAll(ctx, q.Where("q_photo.user_id", "IS NOT NULL").OrderBy("q_photo.src DESC, q_user.id").Limit(2))
+	qts.Assert().NoError(err)
+	qts.Assert().Contains(queries[0], "GROUP BY q_user.id ORDER BY MAX(q_photo.src) DESC, MIN(q_user.id) LIMIT 2")
+
+	qts.Assert().Equal(2, users.Count())
+	qts.Assert().Equal(int64(4), users.Get(0).(*User).id.Int64)
+	qts.Assert().Equal(int64(2), users.Get(1).(*User).id.Int64)
+	qts.Assert().Equal(2, users.Get(1).(*User).photos.Count())
+
+	q = rep.Select()
+	qts.Assert().NoError(q.With("github.com/godzie44/d3/tests/integration/query/Photo"))
+
+	_, err = rep.FindAll(ctx, q.GroupBy("q_user.id").Having("COUNT(q_photo.id)", ">", 1).Limit(2))
+	qts.Assert().Error(err)
+}
+
+func (qts *QueryTS) TestQuerySubqueries() {
+	ctx := qts.orm.CtxWithSession(context.Background())
+	rep, err := qts.orm.MakeRepository((*User)(nil))
+	qts.Assert().NoError(err)
+
+	users, err := rep.FindAll(ctx, rep.Select().Where("q_user.id", "IN", query.New().Select("user_id").From("q_photo")).OrderBy("q_user.id ASC"))
+	qts.Assert().NoError(err)
+	qts.Assert().Equal(3, users.Count())
+	qts.Assert().Equal(int64(2), users.Get(0).(*User).id.Int64)
+
+	users, err = rep.FindAll(ctx, rep.Select().Where("q_user.age", ">", 30).
+		Exists(query.New().Select("1").From("q_photo p").Where("p.user_id", "=", query.Ref("q_user.id")).AndWhere("p.src", "=", "http://emili_pic_url")))
+	qts.Assert().NoError(err)
+	qts.Assert().Equal(1, users.Count())
+	qts.Assert().Equal("Emili", users.Get(0).(*User).name)
+
+	users, err = rep.FindAll(ctx, rep.Select().NotExists(query.New().Select("1").From("q_photo p").Where("p.user_id", "=", query.Ref("q_user.id"))))
+	qts.Assert().NoError(err)
+	qts.Assert().Equal(6, users.Count())
+
+	q := query.New().Select("q_user.id").From("q_user").
+		SelectSubquery(query.New().Select("COUNT(*)").From("q_photo p").Where("p.user_id", "=", query.Ref("q_user.id")), "photo_count").
+		JoinSubquery(query.JoinInner, query.New().Select("user_id").From("q_photo").Where("src", "=", "http://sara_pic_url").GroupBy("user_id"), "sp", "sp.user_id=q_user.id")
+
+	result, err := qts.orm.MakeSession().Execute(context.Background(), q)
+	qts.Assert().NoError(err)
+	qts.Assert().Len(result, 1)
+	qts.Assert().EqualValues(2, result[0]["photo_count"])
+}
+
+func (qts *QueryTS) TestQueryWithPath() {
+	ctx := qts.orm.CtxWithSession(context.Background())
+	rep, err := qts.orm.MakeRepository((*Order)(nil))
+	qts.Assert().NoError(err)
+
+	q := rep.Select()
+	qts.Assert().NoError(q.WithPath("billingAddress.country"))
+	qts.Assert().NoError(q.WithPath("shippingAddress.country"))
+	qts.Assert().NoError(q.WithPath("buyer.photos"))
+
+	orders, err := rep.FindAll(ctx, q.OrderBy("q_order.id ASC"))
+	qts.Assert().NoError(err)
+	qts.Assert().Equal(3, orders.Count())
+
+	order := orders.Get(0).(*Order)
+	billing, shipping := order.billingAddress.Unwrap().(*Address), order.shippingAddress.Unwrap().(*Address)
+	qts.Assert().Equal("Paris", billing.city)
+	qts.Assert().Equal("France", billing.country.Unwrap().(*Country).name)
+	qts.Assert().Equal("Tokyo", shipping.city)
+	qts.Assert().Equal("Japan", shipping.country.Unwrap().(*Country).name)
+	qts.Assert().Equal("Sara", order.buyer.Unwrap().(*User).name)
+	qts.Assert().Equal(2, order.buyer.Unwrap().(*User).photos.Count())
+
+	order = orders.Get(1).(*Order)
+	qts.Assert().Equal("Lyon", order.billingAddress.Unwrap().(*Address).city)
+	qts.Assert().Equal("Lyon", order.shippingAddress.Unwrap().(*Address).city)
+	qts.Assert().Equal("Emili", order.buyer.Unwrap().(*User).name)
+
+	order = orders.Get(2).(*Order)
+	qts.Assert().Equal("Paris", order.billingAddress.Unwrap().(*Address).city)
+	qts.Assert().True(order.shippingAddress.IsNil())
+	qts.Assert().True(order.buyer.IsNil())
+
+	qts.Assert().Equal(1, qts.driver.QueryCounter())
+}
+
+func (qts *QueryTS) TestQueryWithUnknownPath() {
+	rep, err := qts.orm.MakeRepository((*Order)(nil))
+	qts.Assert().NoError(err)
+
+	err = rep.Select().WithPath("billingAddress.unknown")
+	qts.Assert().True(errors.Is(err, query.ErrRelatedFieldNotFound))
+}
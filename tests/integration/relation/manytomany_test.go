@@ -3,6 +3,7 @@ package relation
 import (
 	"context"
 	"github.com/godzie44/d3/orm"
+	"github.com/godzie44/d3/tests/helpers"
 	"github.com/godzie44/d3/tests/helpers/db"
 	"github.com/stretchr/testify/suite"
 	"testing"
@@ -11,6 +12,7 @@ import (
 type ManyToManyRelationTS struct {
 	suite.Suite
 	orm       *orm.Orm
+	adapter   *helpers.DbAdapterWithQueryCounter
 	execSqlFn func(sql string) error
 }
 
@@ -66,6 +68,7 @@ INSERT INTO author_redactor(author_id, redactor_id) VALUES (1, 1);
 	m.NoError(m.orm.Register(
 		(*BookLL)(nil),
 		(*AuthorLL)(nil),
+		(*BookBL)(nil),
 		(*Redactor)(nil),
 		(*BookEL)(nil),
 		(*AuthorEL)(nil),
@@ -83,20 +86,33 @@ DROP TABLE author_redactor;
 }
 
 func TestPGManyToManyTestSuite(t *testing.T) {
-	_, d3orm, execSqlFn, _ := db.CreatePGTestComponents(t)
+	adapter, d3orm, execSqlFn, _ := db.CreatePGTestComponents(t)
 
 	mtmTS := &ManyToManyRelationTS{
 		orm:       d3orm,
+		adapter:   adapter,
 		execSqlFn: execSqlFn,
 	}
 	suite.Run(t, mtmTS)
 }
 
 func TestSQLiteManyToManyTestSuite(t *testing.T) {
-	_, d3orm, execSqlFn, _ := db.CreateSQLiteTestComponents(t, "_m_to_m")
+	adapter, d3orm, execSqlFn, _ := db.CreateSQLiteTestComponents(t, "_m_to_m")
 
 	mtmTS := &ManyToManyRelationTS{
 		orm:       d3orm,
+		adapter:   adapter,
+		execSqlFn: execSqlFn,
+	}
+	suite.Run(t, mtmTS)
+}
+
+func TestMySQLManyToManyTestSuite(t *testing.T) {
+	adapter, d3orm, execSqlFn, _ := db.CreateMySQLTestComponents(t)
+
+	mtmTS := &ManyToManyRelationTS{
+		orm:       d3orm,
+		adapter:   adapter,
 		execSqlFn: execSqlFn,
 	}
 	suite.Run(t, mtmTS)
@@ -122,6 +138,35 @@ func (m *ManyToManyRelationTS) TestLazyRelation() {
 	)
 }
 
+func (m *ManyToManyRelationTS) TestBatchLazyRelation() {
+	ctx := m.orm.CtxWithSession(context.Background())
+	repository, err := m.orm.MakeRepository((*BookBL)(nil))
+	m.Assert().NoError(err)
+
+	books, err := repository.FindAll(ctx, repository.Select().OrderBy("book.id ASC"))
+	m.Assert().NoError(err)
+	m.Assert().Equal(3, books.Count())
+
+	m.adapter.ResetCounters()
+
+	book1, book2, book3 := books.Get(0).(*BookBL), books.Get(1).(*BookBL), books.Get(2).(*BookBL)
+
+	m.Assert().Equal(2, book1.Authors.Count())
+	m.Assert().Subset(
+		[]string{"Aldous Huxley", "Brian Keenan"},
+		[]string{book1.Authors.Get(0).(*AuthorLL).Name, book1.Authors.Get(1).(*AuthorLL).Name},
+	)
+	m.Assert().Equal(1, m.adapter.QueryCounter())
+
+	m.Assert().Equal(1, book2.Authors.Count())
+	m.Assert().Equal("Brian Keenan", book2.Authors.Get(0).(*AuthorLL).Name)
+	m.Assert().Equal(1, m.adapter.QueryCounter())
+
+	m.Assert().Equal(1, book3.Authors.Count())
+	m.Assert().Equal("Aldous Huxley", book3.Authors.Get(0).(*AuthorLL).Name)
+	m.Assert().Equal(2, m.adapter.QueryCounter())
+}
+
 func (m *ManyToManyRelationTS) TestEagerRelation() {
 	ctx := m.orm.CtxWithSession(context.Background())
 	repository, err := m.orm.MakeRepository((*BookEL)(nil))
@@ -17,6 +17,14 @@ type AuthorLL struct {
 	Name string
 }
 
+//d3:entity
+//d3_table:book
+type BookBL struct {
+	ID      int32              `d3:"pk:auto"`
+	Authors *entity.Collection `d3:"many_to_many:<target_entity:github.com/godzie44/d3/tests/integration/relation/AuthorLL,join_on:book_id,reference_on:author_id,join_table:book_author>,type:lazy,batch:2"`
+	Name    string
+}
+
 //d3:entity
 //d3_table:book
 type BookEL struct {
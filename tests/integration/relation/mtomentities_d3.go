@@ -229,6 +229,124 @@ func (a *AuthorLL) __d3_makeComparator() entity.FieldComparator {
 	}
 }
 
+func (b *BookBL) D3Token() entity.MetaToken {
+	return entity.MetaToken{
+		Tpl:       (*BookBL)(nil),
+		TableName: "book",
+		Tools: entity.InternalTools{
+			ExtractField:  b.__d3_makeFieldExtractor(),
+			SetFieldVal:   b.__d3_makeFieldSetter(),
+			CompareFields: b.__d3_makeComparator(),
+			NewInstance:   b.__d3_makeInstantiator(),
+			Copy:          b.__d3_makeCopier(),
+		},
+		Indexes: []entity.Index{},
+	}
+}
+
+func (b *BookBL) __d3_makeFieldExtractor() entity.FieldExtractor {
+	return func(s interface{}, name string) (interface{}, error) {
+		sTyped, ok := s.(*BookBL)
+		if !ok {
+			return nil, fmt.Errorf("invalid entity type")
+		}
+
+		switch name {
+
+		case "ID":
+			return sTyped.ID, nil
+
+		case "Authors":
+			return sTyped.Authors, nil
+
+		case "Name":
+			return sTyped.Name, nil
+
+		default:
+			return nil, fmt.Errorf("field %s not found", name)
+		}
+	}
+}
+
+func (b *BookBL) __d3_makeInstantiator() entity.Instantiator {
+	return func() interface{} {
+		return &BookBL{}
+	}
+}
+
+func (b *BookBL) __d3_makeFieldSetter() entity.FieldSetter {
+	return func(s interface{}, name string, val interface{}) error {
+		eTyped, ok := s.(*BookBL)
+		if !ok {
+			return fmt.Errorf("invalid entity type")
+		}
+
+		switch name {
+		case "ID":
+			eTyped.ID = val.(int32)
+			return nil
+		case "Authors":
+			eTyped.Authors = val.(*entity.Collection)
+			return nil
+		case "Name":
+			eTyped.Name = val.(string)
+			return nil
+
+		default:
+			return fmt.Errorf("field %s not found", name)
+		}
+	}
+}
+
+func (b *BookBL) __d3_makeCopier() entity.Copier {
+	return func(src interface{}) interface{} {
+		srcTyped, ok := src.(*BookBL)
+		if !ok {
+			return fmt.Errorf("invalid entity type")
+		}
+
+		copy := &BookBL{}
+
+		copy.ID = srcTyped.ID
+		copy.Name = srcTyped.Name
+
+		if srcTyped.Authors != nil {
+			copy.Authors = srcTyped.Authors.DeepCopy().(*entity.Collection)
+		}
+
+		return copy
+	}
+}
+
+func (b *BookBL) __d3_makeComparator() entity.FieldComparator {
+	return func(e1, e2 interface{}, fName string) bool {
+		if e1 == nil || e2 == nil {
+			return e1 == e2
+		}
+
+		e1Typed, ok := e1.(*BookBL)
+		if !ok {
+			return false
+		}
+		e2Typed, ok := e2.(*BookBL)
+		if !ok {
+			return false
+		}
+
+		switch fName {
+
+		case "ID":
+			return e1Typed.ID == e2Typed.ID
+		case "Authors":
+			return e1Typed.Authors == e2Typed.Authors
+		case "Name":
+			return e1Typed.Name == e2Typed.Name
+		default:
+			return false
+		}
+	}
+}
+
 func (b *BookEL) D3Token() entity.MetaToken {
 	return entity.MetaToken{
 		Tpl:       (*BookEL)(nil),
@@ -40,6 +40,7 @@ func (o *OneToManyRelationTS) SetupSuite() {
 
 	err = o.execSqlFn(`
 INSERT INTO shop(id, name) VALUES (1, 'book-shop');
+INSERT INTO shop(id, name) VALUES (2, 'empty-shop');
 INSERT INTO book(id, name, t1_id) VALUES (1, 'Antic Hay', 1);
 INSERT INTO book(id, name, t1_id) VALUES (2, 'An Evil Cradling', 1);
 INSERT INTO book(id, name, t1_id) VALUES (3, 'Arms and the Man', 1);
@@ -50,6 +51,7 @@ INSERT INTO discount(id, value, t2_id) VALUES (1, 33, 1);
 	o.NoError(o.orm.Register(
 		(*ShopLR)(nil),
 		(*BookLR)(nil),
+		(*ShopBR)(nil),
 		(*ShopER)(nil),
 		(*BookER)(nil),
 		(*DiscountER)(nil),
@@ -84,6 +86,16 @@ func TestSQLiteOneToManyTestSuite(t *testing.T) {
 	suite.Run(t, mtmTS)
 }
 
+func TestMySQLOneToManyTestSuite(t *testing.T) {
+	_, d3orm, execSqlFn, _ := db.CreateMySQLTestComponents(t)
+
+	mtmTS := &OneToManyRelationTS{
+		orm:       d3orm,
+		execSqlFn: execSqlFn,
+	}
+	suite.Run(t, mtmTS)
+}
+
 func (o *OneToManyRelationTS) TestLazyRelation() {
 	ctx := o.orm.CtxWithSession(context.Background())
 	repository, err := o.orm.MakeRepository((*ShopLR)(nil))
@@ -104,6 +116,48 @@ func (o *OneToManyRelationTS) TestLazyRelation() {
 	)
 }
 
+func (o *OneToManyRelationTS) TestBatchLazyRelation() {
+	ctx := o.orm.CtxWithSession(context.Background())
+	repository, err := o.orm.MakeRepository((*ShopBR)(nil))
+	o.Assert().NoError(err)
+
+	shops, err := repository.FindAll(ctx, repository.Select().OrderBy("shop.id ASC"))
+	o.Assert().NoError(err)
+	o.Assert().Equal(2, shops.Count())
+
+	o.Assert().Equal(3, shops.Get(0).(*ShopBR).Books.Count())
+	o.Assert().Subset(
+		[]string{"Antic Hay", "An Evil Cradling", "Arms and the Man"},
+		[]string{
+			shops.Get(0).(*ShopBR).Books.Get(0).(*BookLR).Name,
+			shops.Get(0).(*ShopBR).Books.Get(1).(*BookLR).Name,
+			shops.Get(0).(*ShopBR).Books.Get(2).(*BookLR).Name,
+		},
+	)
+	o.Assert().True(shops.Get(1).(*ShopBR).Books.Empty())
+}
+
+func (o *OneToManyRelationTS) TestBatchLazyRelationReuseLoadedEntities() {
+	ctx := o.orm.CtxWithSession(context.Background())
+	bookRepository, err := o.orm.MakeRepository((*BookLR)(nil))
+	o.Assert().NoError(err)
+
+	book, err := bookRepository.FindOne(ctx, bookRepository.Select().Where("book.id", "=", 1))
+	o.Assert().NoError(err)
+	book.(*BookLR).Name = "changed"
+
+	repository, err := o.orm.MakeRepository((*ShopBR)(nil))
+	o.Assert().NoError(err)
+
+	shops, err := repository.FindAll(ctx, repository.Select().OrderBy("shop.id ASC"))
+	o.Assert().NoError(err)
+
+	books := shops.Get(0).(*ShopBR).Books
+	o.Assert().Equal(3, books.Count())
+	o.Assert().Contains(books.ToSlice(), book)
+	o.Assert().Equal("changed", book.(*BookLR).Name)
+}
+
 func (o *OneToManyRelationTS) TestEagerRelation() {
 	ctx := o.orm.CtxWithSession(context.Background())
 	repository, err := o.orm.MakeRepository((*ShopER)(nil))
@@ -51,6 +51,7 @@ INSERT INTO photo(id, data) VALUES (1, 'entity_3_data');
 		(*ProfileLL)(nil),
 		(*PhotoLL)(nil),
 		(*ShopEL)(nil),
+		(*ShopBL)(nil),
 	))
 }
 
@@ -83,6 +84,37 @@ func TestSQLiteOneToOneTestSuite(t *testing.T) {
 	suite.Run(t, mtmTS)
 }
 
+func TestMySQLOneToOneTestSuite(t *testing.T) {
+	_, d3orm, execSqlFn, _ := db.CreateMySQLTestComponents(t)
+
+	mtmTS := &OneToOneRelationTS{
+		orm:       d3orm,
+		execSqlFn: execSqlFn,
+	}
+
+	suite.Run(t, mtmTS)
+}
+
+func (o *OneToOneRelationTS) TestBatchLazyRelationFromIdentityMap() {
+	ctx := o.orm.CtxWithSession(context.Background())
+	profileRepository, err := o.orm.MakeRepository((*ProfileLL)(nil))
+	o.Assert().NoError(err)
+
+	profile, err := profileRepository.FindOne(ctx, profileRepository.Select().Where("profile.id", "=", 1))
+	o.Assert().NoError(err)
+	profile.(*ProfileLL).Data = "changed"
+
+	repository, err := o.orm.MakeRepository((*ShopBL)(nil))
+	o.Assert().NoError(err)
+
+	shops, err := repository.FindAll(ctx, repository.Select().OrderBy("shop.id ASC"))
+	o.Assert().NoError(err)
+	o.Assert().Equal(2, shops.Count())
+
+	o.Assert().Same(profile, shops.Get(0).(*ShopBL).Profile.Unwrap())
+	o.Assert().True(shops.Get(1).(*ShopBL).Profile.IsNil())
+}
+
 func (o *OneToOneRelationTS) TestLazyRelation() {
 	ctx := o.orm.CtxWithSession(context.Background())
 	repository, err := o.orm.MakeRepository((*ShopLL)(nil))
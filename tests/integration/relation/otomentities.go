@@ -17,6 +17,14 @@ type BookLR struct {
 	Name string
 }
 
+//d3:entity
+//d3_table:shop
+type ShopBR struct {
+	Id    int32              `d3:"pk:auto"`
+	Books *entity.Collection `d3:"one_to_many:<target_entity:BookLR,join_on:t1_id>,type:lazy,batch:10"`
+	Name  string
+}
+
 //d3:entity
 //d3_table:shop
 type ShopER struct {
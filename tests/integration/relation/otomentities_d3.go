@@ -229,6 +229,124 @@ func (b *BookLR) __d3_makeComparator() entity.FieldComparator {
 	}
 }
 
+func (s *ShopBR) D3Token() entity.MetaToken {
+	return entity.MetaToken{
+		Tpl:       (*ShopBR)(nil),
+		TableName: "shop",
+		Tools: entity.InternalTools{
+			ExtractField:  s.__d3_makeFieldExtractor(),
+			SetFieldVal:   s.__d3_makeFieldSetter(),
+			CompareFields: s.__d3_makeComparator(),
+			NewInstance:   s.__d3_makeInstantiator(),
+			Copy:          s.__d3_makeCopier(),
+		},
+		Indexes: []entity.Index{},
+	}
+}
+
+func (s *ShopBR) __d3_makeFieldExtractor() entity.FieldExtractor {
+	return func(s interface{}, name string) (interface{}, error) {
+		sTyped, ok := s.(*ShopBR)
+		if !ok {
+			return nil, fmt.Errorf("invalid entity type")
+		}
+
+		switch name {
+
+		case "Id":
+			return sTyped.Id, nil
+
+		case "Books":
+			return sTyped.Books, nil
+
+		case "Name":
+			return sTyped.Name, nil
+
+		default:
+			return nil, fmt.Errorf("field %s not found", name)
+		}
+	}
+}
+
+func (s *ShopBR) __d3_makeInstantiator() entity.Instantiator {
+	return func() interface{} {
+		return &ShopBR{}
+	}
+}
+
+func (s *ShopBR) __d3_makeFieldSetter() entity.FieldSetter {
+	return func(s interface{}, name string, val interface{}) error {
+		eTyped, ok := s.(*ShopBR)
+		if !ok {
+			return fmt.Errorf("invalid entity type")
+		}
+
+		switch name {
+		case "Id":
+			eTyped.Id = val.(int32)
+			return nil
+		case "Books":
+			eTyped.Books = val.(*entity.Collection)
+			return nil
+		case "Name":
+			eTyped.Name = val.(string)
+			return nil
+
+		default:
+			return fmt.Errorf("field %s not found", name)
+		}
+	}
+}
+
+func (s *ShopBR) __d3_makeCopier() entity.Copier {
+	return func(src interface{}) interface{} {
+		srcTyped, ok := src.(*ShopBR)
+		if !ok {
+			return fmt.Errorf("invalid entity type")
+		}
+
+		copy := &ShopBR{}
+
+		copy.Id = srcTyped.Id
+		copy.Name = srcTyped.Name
+
+		if srcTyped.Books != nil {
+			copy.Books = srcTyped.Books.DeepCopy().(*entity.Collection)
+		}
+
+		return copy
+	}
+}
+
+func (s *ShopBR) __d3_makeComparator() entity.FieldComparator {
+	return func(e1, e2 interface{}, fName string) bool {
+		if e1 == nil || e2 == nil {
+			return e1 == e2
+		}
+
+		e1Typed, ok := e1.(*ShopBR)
+		if !ok {
+			return false
+		}
+		e2Typed, ok := e2.(*ShopBR)
+		if !ok {
+			return false
+		}
+
+		switch fName {
+
+		case "Id":
+			return e1Typed.Id == e2Typed.Id
+		case "Books":
+			return e1Typed.Books == e2Typed.Books
+		case "Name":
+			return e1Typed.Name == e2Typed.Name
+		default:
+			return false
+		}
+	}
+}
+
 func (s *ShopER) D3Token() entity.MetaToken {
 	return entity.MetaToken{
 		Tpl:       (*ShopER)(nil),
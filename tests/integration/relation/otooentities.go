@@ -35,3 +35,11 @@ type ShopEL struct {
 	Profile *entity.Cell `d3:"one_to_one:<target_entity:ProfileLL,join_on:t2_id,reference_on:id>,type:eager"`
 	Data    string
 }
+
+//d3:entity
+//d3_table:shop
+type ShopBL struct {
+	ID      sql.NullInt32 `d3:"pk:auto"`
+	Profile *entity.Cell  `d3:"one_to_one:<target_entity:ProfileLL,join_on:t2_id>,type:lazy,batch:10"`
+	Data    string
+}
@@ -2,9 +2,9 @@
 
 package relation
 
-import "fmt"
 import "github.com/godzie44/d3/orm/entity"
 import "database/sql/driver"
+import "fmt"
 
 func (s *ShopLL) D3Token() entity.MetaToken {
 	return entity.MetaToken{
@@ -471,3 +471,127 @@ func (s *ShopEL) __d3_makeComparator() entity.FieldComparator {
 		}
 	}
 }
+
+func (s *ShopBL) D3Token() entity.MetaToken {
+	return entity.MetaToken{
+		Tpl:       (*ShopBL)(nil),
+		TableName: "shop",
+		Tools: entity.InternalTools{
+			ExtractField:  s.__d3_makeFieldExtractor(),
+			SetFieldVal:   s.__d3_makeFieldSetter(),
+			CompareFields: s.__d3_makeComparator(),
+			NewInstance:   s.__d3_makeInstantiator(),
+			Copy:          s.__d3_makeCopier(),
+		},
+		Indexes: []entity.Index{},
+	}
+}
+
+func (s *ShopBL) __d3_makeFieldExtractor() entity.FieldExtractor {
+	return func(s interface{}, name string) (interface{}, error) {
+		sTyped, ok := s.(*ShopBL)
+		if !ok {
+			return nil, fmt.Errorf("invalid entity type")
+		}
+
+		switch name {
+
+		case "ID":
+			return sTyped.ID, nil
+
+		case "Profile":
+			return sTyped.Profile, nil
+
+		case "Data":
+			return sTyped.Data, nil
+
+		default:
+			return nil, fmt.Errorf("field %s not found", name)
+		}
+	}
+}
+
+func (s *ShopBL) __d3_makeInstantiator() entity.Instantiator {
+	return func() interface{} {
+		return &ShopBL{}
+	}
+}
+
+func (s *ShopBL) __d3_makeFieldSetter() entity.FieldSetter {
+	return func(s interface{}, name string, val interface{}) error {
+		eTyped, ok := s.(*ShopBL)
+		if !ok {
+			return fmt.Errorf("invalid entity type")
+		}
+
+		switch name {
+		case "Profile":
+			eTyped.Profile = val.(*entity.Cell)
+			return nil
+		case "Data":
+			eTyped.Data = val.(string)
+			return nil
+
+		case "ID":
+			if valuer, isValuer := val.(driver.Valuer); isValuer {
+				v, err := valuer.Value()
+				if err != nil {
+					return eTyped.ID.Scan(nil)
+				}
+				return eTyped.ID.Scan(v)
+			}
+			return eTyped.ID.Scan(val)
+		default:
+			return fmt.Errorf("field %s not found", name)
+		}
+	}
+}
+
+func (s *ShopBL) __d3_makeCopier() entity.Copier {
+	return func(src interface{}) interface{} {
+		srcTyped, ok := src.(*ShopBL)
+		if !ok {
+			return fmt.Errorf("invalid entity type")
+		}
+
+		copy := &ShopBL{}
+
+		copy.ID = srcTyped.ID
+		copy.Data = srcTyped.Data
+
+		if srcTyped.Profile != nil {
+			copy.Profile = srcTyped.Profile.DeepCopy().(*entity.Cell)
+		}
+
+		return copy
+	}
+}
+
+func (s *ShopBL) __d3_makeComparator() entity.FieldComparator {
+	return func(e1, e2 interface{}, fName string) bool {
+		if e1 == nil || e2 == nil {
+			return e1 == e2
+		}
+
+		e1Typed, ok := e1.(*ShopBL)
+		if !ok {
+			return false
+		}
+		e2Typed, ok := e2.(*ShopBL)
+		if !ok {
+			return false
+		}
+
+		switch fName {
+
+		case "ID":
+			return e1Typed.ID == e2Typed.ID
+		case "Profile":
+			return e1Typed.Profile == e2Typed.Profile
+		case "Data":
+			return e1Typed.Data == e2Typed.Data
+		default:
+			return false
+		}
+	}
+}
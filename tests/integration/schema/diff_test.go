@@ -0,0 +1,82 @@
+package schema
+
+import (
+	"github.com/godzie44/d3/orm"
+	"github.com/godzie44/d3/tests/helpers"
+	"github.com/godzie44/d3/tests/helpers/db"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+type DiffTestSuite struct {
+	suite.Suite
+	tester       helpers.DBTester
+	ormV1, ormV2 *orm.Orm
+	execSqlFn    func(sql string) error
+}
+
+func (d *DiffTestSuite) SetupSuite() {
+	d.NoError(d.ormV1.Register((*diffV1)(nil)))
+	d.NoError(d.ormV2.Register((*diffV2)(nil), (*diffTag)(nil)))
+}
+
+func (d *DiffTestSuite) TearDownSuite() {
+	d.NoError(d.execSqlFn(`
+DROP TABLE IF EXISTS diff_tag_d;
+DROP TABLE IF EXISTS diff_d;
+DROP TABLE IF EXISTS tag_d;
+
+DROP INDEX IF EXISTS diff_obsolete_idx;
+DROP INDEX IF EXISTS diff_name_idx;
+`))
+}
+
+func (d *DiffTestSuite) TestMigrateSchema() {
+	schemaSql, err := d.ormV1.GenerateSchema()
+	d.NoError(err)
+	d.NoError(d.execSqlFn(schemaSql))
+	d.NoError(d.execSqlFn("INSERT INTO diff_d(name, obsolete, price) VALUES ('diff', 'obsolete', 10)"))
+
+	diffSql, err := d.ormV2.GenerateSchemaDiff()
+	d.NoError(err)
+	d.NoError(d.execSqlFn(diffSql))
+
+	d.tester.
+		SeeTable("tag_d").
+		SeeTable("diff_tag_d").
+		SeeIndex("diff_d", "diff_name_idx", false).
+		SeeOne("SELECT id, name, price, description FROM diff_d WHERE name = 'diff' AND price = 10 AND description = ''").
+		See(0, "SELECT * FROM diff_d WHERE name IS NULL")
+	d.Error(d.execSqlFn("SELECT obsolete FROM diff_d"))
+	d.NoError(d.execSqlFn("SELECT main_tag_id FROM diff_d"))
+
+	d.NoError(d.execSqlFn("INSERT INTO diff_d(name, price, description) VALUES (NULL, 1.5, 'desc')"))
+
+	diffSql, err = d.ormV2.GenerateSchemaDiff()
+	d.NoError(err)
+	d.Empty(diffSql)
+}
+
+func TestPGDiffTs(t *testing.T) {
+	_, ormV1, execSqlFn, tester := db.CreatePGTestComponents(t)
+	_, ormV2, _, _ := db.CreatePGTestComponents(t)
+
+	suite.Run(t, &DiffTestSuite{
+		ormV1:     ormV1,
+		ormV2:     ormV2,
+		tester:    tester,
+		execSqlFn: execSqlFn,
+	})
+}
+
+func TestSqliteDiffTs(t *testing.T) {
+	_, ormV1, execSqlFn, tester := db.CreateSQLiteTestComponents(t, "_diff")
+	_, ormV2, _, _ := db.CreateSQLiteTestComponents(t, "_diff")
+
+	suite.Run(t, &DiffTestSuite{
+		ormV1:     ormV1,
+		ormV2:     ormV2,
+		tester:    tester,
+		execSqlFn: execSqlFn,
+	})
+}
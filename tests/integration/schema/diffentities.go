@@ -0,0 +1,35 @@
+package schema
+
+import (
+	"database/sql"
+	"github.com/godzie44/d3/orm/entity"
+)
+
+//d3:entity
+//d3_table:diff_d
+//d3_index:diff_obsolete_idx(obsolete)
+type diffV1 struct {
+	Id       sql.NullInt32 `d3:"pk:auto"`
+	Name     string
+	Obsolete sql.NullString
+	Price    int32
+}
+
+//d3:entity
+//d3_table:diff_d
+//d3_index:diff_name_idx(name)
+type diffV2 struct {
+	Id          sql.NullInt32 `d3:"pk:auto"`
+	Name        sql.NullString
+	Price       float64
+	Description string
+	Tags        *entity.Collection `d3:"many_to_many:<target_entity:diffTag,join_on:diff_id,reference_on:tag_id,join_table:diff_tag_d>,type:lazy"`
+	MainTag     *entity.Cell       `d3:"one_to_one:<target_entity:diffTag,join_on:main_tag_id,delete:nullable>,type:lazy"`
+}
+
+//d3:entity
+//d3_table:tag_d
+type diffTag struct {
+	Id   sql.NullInt32 `d3:"pk:auto"`
+	Name string
+}
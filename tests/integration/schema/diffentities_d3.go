@@ -0,0 +1,420 @@
+// Code generated by d3. DO NOT EDIT.
+
+package schema
+
+import "database/sql/driver"
+import "fmt"
+import "github.com/godzie44/d3/orm/entity"
+
+func (d *diffV1) D3Token() entity.MetaToken {
+	return entity.MetaToken{
+		Tpl:       (*diffV1)(nil),
+		TableName: "diff_d",
+		Tools: entity.InternalTools{
+			ExtractField:  d.__d3_makeFieldExtractor(),
+			SetFieldVal:   d.__d3_makeFieldSetter(),
+			CompareFields: d.__d3_makeComparator(),
+			NewInstance:   d.__d3_makeInstantiator(),
+			Copy:          d.__d3_makeCopier(),
+		},
+		Indexes: []entity.Index{
+
+			{Name: "diff_obsolete_idx", Columns: []string{"obsolete"}, Unique: false},
+		},
+	}
+}
+
+func (d *diffV1) __d3_makeFieldExtractor() entity.FieldExtractor {
+	return func(s interface{}, name string) (interface{}, error) {
+		sTyped, ok := s.(*diffV1)
+		if !ok {
+			return nil, fmt.Errorf("invalid entity type")
+		}
+
+		switch name {
+
+		case "Id":
+			return sTyped.Id, nil
+
+		case "Name":
+			return sTyped.Name, nil
+
+		case "Obsolete":
+			return sTyped.Obsolete, nil
+
+		case "Price":
+			return sTyped.Price, nil
+
+		default:
+			return nil, fmt.Errorf("field %s not found", name)
+		}
+	}
+}
+
+func (d *diffV1) __d3_makeInstantiator() entity.Instantiator {
+	return func() interface{} {
+		return &diffV1{}
+	}
+}
+
+func (d *diffV1) __d3_makeFieldSetter() entity.FieldSetter {
+	return func(s interface{}, name string, val interface{}) error {
+		eTyped, ok := s.(*diffV1)
+		if !ok {
+			return fmt.Errorf("invalid entity type")
+		}
+
+		switch name {
+		case "Name":
+			eTyped.Name = val.(string)
+			return nil
+		case "Price":
+			eTyped.Price = val.(int32)
+			return nil
+
+		case "Id":
+			if valuer, isValuer := val.(driver.Valuer); isValuer {
+				v, err := valuer.Value()
+				if err != nil {
+					return eTyped.Id.Scan(nil)
+				}
+				return eTyped.Id.Scan(v)
+			}
+			return eTyped.Id.Scan(val)
+		case "Obsolete":
+			if valuer, isValuer := val.(driver.Valuer); isValuer {
+				v, err := valuer.Value()
+				if err != nil {
+					return eTyped.Obsolete.Scan(nil)
+				}
+				return eTyped.Obsolete.Scan(v)
+			}
+			return eTyped.Obsolete.Scan(val)
+		default:
+			return fmt.Errorf("field %s not found", name)
+		}
+	}
+}
+
+func (d *diffV1) __d3_makeCopier() entity.Copier {
+	return func(src interface{}) interface{} {
+		srcTyped, ok := src.(*diffV1)
+		if !ok {
+			return fmt.Errorf("invalid entity type")
+		}
+
+		copy := &diffV1{}
+
+		copy.Id = srcTyped.Id
+		copy.Name = srcTyped.Name
+		copy.Obsolete = srcTyped.Obsolete
+		copy.Price = srcTyped.Price
+
+		return copy
+	}
+}
+
+func (d *diffV1) __d3_makeComparator() entity.FieldComparator {
+	return func(e1, e2 interface{}, fName string) bool {
+		if e1 == nil || e2 == nil {
+			return e1 == e2
+		}
+
+		e1Typed, ok := e1.(*diffV1)
+		if !ok {
+			return false
+		}
+		e2Typed, ok := e2.(*diffV1)
+		if !ok {
+			return false
+		}
+
+		switch fName {
+
+		case "Id":
+			return e1Typed.Id == e2Typed.Id
+		case "Name":
+			return e1Typed.Name == e2Typed.Name
+		case "Obsolete":
+			return e1Typed.Obsolete == e2Typed.Obsolete
+		case "Price":
+			return e1Typed.Price == e2Typed.Price
+		default:
+			return false
+		}
+	}
+}
+
+func (d *diffV2) D3Token() entity.MetaToken {
+	return entity.MetaToken{
+		Tpl:       (*diffV2)(nil),
+		TableName: "diff_d",
+		Tools: entity.InternalTools{
+			ExtractField:  d.__d3_makeFieldExtractor(),
+			SetFieldVal:   d.__d3_makeFieldSetter(),
+			CompareFields: d.__d3_makeComparator(),
+			NewInstance:   d.__d3_makeInstantiator(),
+			Copy:          d.__d3_makeCopier(),
+		},
+		Indexes: []entity.Index{
+
+			{Name: "diff_name_idx", Columns: []string{"name"}, Unique: false},
+		},
+	}
+}
+
+func (d *diffV2) __d3_makeFieldExtractor() entity.FieldExtractor {
+	return func(s interface{}, name string) (interface{}, error) {
+		sTyped, ok := s.(*diffV2)
+		if !ok {
+			return nil, fmt.Errorf("invalid entity type")
+		}
+
+		switch name {
+
+		case "Id":
+			return sTyped.Id, nil
+
+		case "Name":
+			return sTyped.Name, nil
+
+		case "Price":
+			return sTyped.Price, nil
+
+		case "Description":
+			return sTyped.Description, nil
+
+		case "Tags":
+			return sTyped.Tags, nil
+
+		case "MainTag":
+			return sTyped.MainTag, nil
+
+		default:
+			return nil, fmt.Errorf("field %s not found", name)
+		}
+	}
+}
+
+func (d *diffV2) __d3_makeInstantiator() entity.Instantiator {
+	return func() interface{} {
+		return &diffV2{}
+	}
+}
+
+func (d *diffV2) __d3_makeFieldSetter() entity.FieldSetter {
+	return func(s interface{}, name string, val interface{}) error {
+		eTyped, ok := s.(*diffV2)
+		if !ok {
+			return fmt.Errorf("invalid entity type")
+		}
+
+		switch name {
+		case "Price":
+			eTyped.Price = val.(float64)
+			return nil
+		case "Description":
+			eTyped.Description = val.(string)
+			return nil
+		case "Tags":
+			eTyped.Tags = val.(*entity.Collection)
+			return nil
+		case "MainTag":
+			eTyped.MainTag = val.(*entity.Cell)
+			return nil
+
+		case "Id":
+			if valuer, isValuer := val.(driver.Valuer); isValuer {
+				v, err := valuer.Value()
+				if err != nil {
+					return eTyped.Id.Scan(nil)
+				}
+				return eTyped.Id.Scan(v)
+			}
+			return eTyped.Id.Scan(val)
+		case "Name":
+			if valuer, isValuer := val.(driver.Valuer); isValuer {
+				v, err := valuer.Value()
+				if err != nil {
+					return eTyped.Name.Scan(nil)
+				}
+				return eTyped.Name.Scan(v)
+			}
+			return eTyped.Name.Scan(val)
+		default:
+			return fmt.Errorf("field %s not found", name)
+		}
+	}
+}
+
+func (d *diffV2) __d3_makeCopier() entity.Copier {
+	return func(src interface{}) interface{} {
+		srcTyped, ok := src.(*diffV2)
+		if !ok {
+			return fmt.Errorf("invalid entity type")
+		}
+
+		copy := &diffV2{}
+
+		copy.Id = srcTyped.Id
+		copy.Name = srcTyped.Name
+		copy.Price = srcTyped.Price
+		copy.Description = srcTyped.Description
+
+		if srcTyped.Tags != nil {
+			copy.Tags = srcTyped.Tags.DeepCopy().(*entity.Collection)
+		}
+		if srcTyped.MainTag != nil {
+			copy.MainTag = srcTyped.MainTag.DeepCopy().(*entity.Cell)
+		}
+
+		return copy
+	}
+}
+
+func (d *diffV2) __d3_makeComparator() entity.FieldComparator {
+	return func(e1, e2 interface{}, fName string) bool {
+		if e1 == nil || e2 == nil {
+			return e1 == e2
+		}
+
+		e1Typed, ok := e1.(*diffV2)
+		if !ok {
+			return false
+		}
+		e2Typed, ok := e2.(*diffV2)
+		if !ok {
+			return false
+		}
+
+		switch fName {
+
+		case "Id":
+			return e1Typed.Id == e2Typed.Id
+		case "Name":
+			return e1Typed.Name == e2Typed.Name
+		case "Price":
+			return e1Typed.Price == e2Typed.Price
+		case "Description":
+			return e1Typed.Description == e2Typed.Description
+		case "Tags":
+			return e1Typed.Tags == e2Typed.Tags
+		case "MainTag":
+			return e1Typed.MainTag == e2Typed.MainTag
+		default:
+			return false
+		}
+	}
+}
+
+func (d *diffTag) D3Token() entity.MetaToken {
+	return entity.MetaToken{
+		Tpl:       (*diffTag)(nil),
+		TableName: "tag_d",
+		Tools: entity.InternalTools{
+			ExtractField:  d.__d3_makeFieldExtractor(),
+			SetFieldVal:   d.__d3_makeFieldSetter(),
+			CompareFields: d.__d3_makeComparator(),
+			NewInstance:   d.__d3_makeInstantiator(),
+			Copy:          d.__d3_makeCopier(),
+		},
+		Indexes: []entity.Index{},
+	}
+}
+
+func (d *diffTag) __d3_makeFieldExtractor() entity.FieldExtractor {
+	return func(s interface{}, name string) (interface{}, error) {
+		sTyped, ok := s.(*diffTag)
+		if !ok {
+			return nil, fmt.Errorf("invalid entity type")
+		}
+
+		switch name {
+
+		case "Id":
+			return sTyped.Id, nil
+
+		case "Name":
+			return sTyped.Name, nil
+
+		default:
+			return nil, fmt.Errorf("field %s not found", name)
+		}
+	}
+}
+
+func (d *diffTag) __d3_makeInstantiator() entity.Instantiator {
+	return func() interface{} {
+		return &diffTag{}
+	}
+}
+
+func (d *diffTag) __d3_makeFieldSetter() entity.FieldSetter {
+	return func(s interface{}, name string, val interface{}) error {
+		eTyped, ok := s.(*diffTag)
+		if !ok {
+			return fmt.Errorf("invalid entity type")
+		}
+
+		switch name {
+		case "Name":
+			eTyped.Name = val.(string)
+			return nil
+
+		case "Id":
+			if valuer, isValuer := val.(driver.Valuer); isValuer {
+				v, err := valuer.Value()
+				if err != nil {
+					return eTyped.Id.Scan(nil)
+				}
+				return eTyped.Id.Scan(v)
+			}
+			return eTyped.Id.Scan(val)
+		default:
+			return fmt.Errorf("field %s not found", name)
+		}
+	}
+}
+
+func (d *diffTag) __d3_makeCopier() entity.Copier {
+	return func(src interface{}) interface{} {
+		srcTyped, ok := src.(*diffTag)
+		if !ok {
+			return fmt.Errorf("invalid entity type")
+		}
+
+		copy := &diffTag{}
+
+		copy.Id = srcTyped.Id
+		copy.Name = srcTyped.Name
+
+		return copy
+	}
+}
+
+func (d *diffTag) __d3_makeComparator() entity.FieldComparator {
+	return func(e1, e2 interface{}, fName string) bool {
+		if e1 == nil || e2 == nil {
+			return e1 == e2
+		}
+
+		e1Typed, ok := e1.(*diffTag)
+		if !ok {
+			return false
+		}
+		e2Typed, ok := e2.(*diffTag)
+		if !ok {
+			return false
+		}
+
+		switch fName {
+
+		case "Id":
+			return e1Typed.Id == e2Typed.Id
+		case "Name":
+			return e1Typed.Name == e2Typed.Name
+		default:
+			return false
+		}
+	}
+}
@@ -26,11 +26,11 @@ func (m *MigrationTestSuite) SetupSuite() {
 
 func (m *MigrationTestSuite) TearDownSuite() {
 	m.NoError(m.execSqlFn(`
+DROP TABLE IF EXISTS book_author_m;
+DROP TABLE IF EXISTS book_m;
 DROP TABLE IF EXISTS shop_m;
 DROP TABLE IF EXISTS profile_m;
 DROP TABLE IF EXISTS author_m;
-DROP TABLE IF EXISTS book_m;
-DROP TABLE IF EXISTS book_author_m;
 
 DROP INDEX IF EXISTS shop_name_idx;
 DROP INDEX IF EXISTS book_name_idx;
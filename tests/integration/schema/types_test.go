@@ -72,7 +72,7 @@ func assertTypeConversion(t *testing.T, d3orm *orm.Orm, timeVal time.Time) {
 		NullTimeField:    sql.NullTime{Time: timeVal, Valid: true},
 	}
 	assert.NoError(t, rep.Persists(ctx, entity))
-	assert.NoError(t, orm.Session(ctx).Flush())
+	assert.NoError(t, orm.Session(ctx).Flush(ctx))
 
 	ctx2 := d3orm.CtxWithSession(context.Background())
 	rep, err = d3orm.MakeRepository(&allTypeStruct{})
@@ -95,7 +95,7 @@ func assertCustomTypeConversion(t *testing.T, d3orm *orm.Orm) {
 	}
 
 	assert.NoError(t, rep.Persists(ctx, entity))
-	assert.NoError(t, orm.Session(ctx).Flush())
+	assert.NoError(t, orm.Session(ctx).Flush(ctx))
 
 	ctx2 := d3orm.CtxWithSession(context.Background())
 	rep, err = d3orm.MakeRepository(&entityWithAliases{})